@@ -85,6 +85,22 @@ func NewAdvertisement(adapterID string, props *advertising.LEAdvertisement1Prope
 	return adv, nil
 }
 
+// ValidateAdvertisement check the advertisement properties against the
+// features and secondary channels supported by the advertising manager and
+// the legacy or extended advertising data length
+func ValidateAdvertisement(advManager *advertising.LEAdvertisingManager1, props *advertising.LEAdvertisement1Properties) error {
+
+	managerProps, err := advManager.GetProperties()
+	if err != nil {
+		return err
+	}
+
+	log.Tracef("Advertising features %v, secondary channels %v",
+		managerProps.SupportedFeatures, managerProps.SupportedSecondaryChannels)
+
+	return props.Validate(managerProps)
+}
+
 // Expose to bluez an advertisment instance via the adapter advertisement manager
 func ExposeAdvertisement(adapterID string, props *advertising.LEAdvertisement1Properties, discoverableTimeout uint32) (func(), error) {
 
@@ -94,6 +110,16 @@ func ExposeAdvertisement(adapterID string, props *advertising.LEAdvertisement1Pr
 		return nil, err
 	}
 
+	advManager, err := advertising.NewLEAdvertisingManager1FromAdapterID(adapterID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ValidateAdvertisement(advManager, props)
+	if err != nil {
+		return nil, err
+	}
+
 	adv, err := NewAdvertisement(adapterID, props)
 	if err != nil {
 		return nil, err
@@ -120,11 +146,6 @@ func ExposeAdvertisement(adapterID string, props *advertising.LEAdvertisement1Pr
 	}
 
 	log.Trace("Registering LEAdvertisement1 instance")
-	advManager, err := advertising.NewLEAdvertisingManager1FromAdapterID(adapterID)
-	if err != nil {
-		return nil, err
-	}
-
 	err = advManager.RegisterAdvertisement(adv.Path(), map[string]interface{}{})
 	if err != nil {
 		return nil, err
@@ -2,26 +2,33 @@
 package api
 
 import (
+	"sync"
+
 	"github.com/dimonzozo/go-bluetooth/bluez"
 	"github.com/dimonzozo/go-bluetooth/bluez/profile/adapter"
 )
 
+var adaptersLock sync.Mutex
 var adapters = map[string]*adapter.Adapter1{}
 
 //Exit performs a clean exit
 func Exit() error {
 
+	adaptersLock.Lock()
 	for _, a := range adapters {
 		a.Close()
 	}
-
 	adapters = map[string]*adapter.Adapter1{}
+	adaptersLock.Unlock()
 
 	return bluez.CloseConnections()
 }
 
 func GetAdapter(adapterID string) (*adapter.Adapter1, error) {
 
+	adaptersLock.Lock()
+	defer adaptersLock.Unlock()
+
 	if _, ok := adapters[adapterID]; ok {
 		return adapters[adapterID], nil
 	}
@@ -36,6 +43,12 @@ func GetAdapter(adapterID string) (*adapter.Adapter1, error) {
 	return a, nil
 }
 
+// GetAdapterWithConn return an adapter bound to the provided connection.
+// The instance is not cached, the caller owns both the adapter and the connection
+func GetAdapterWithConn(conn *bluez.Conn, adapterID string) (*adapter.Adapter1, error) {
+	return adapter.GetAdapterWithConn(conn, adapterID)
+}
+
 func GetDefaultAdapter() (*adapter.Adapter1, error) {
 	return GetAdapter(GetDefaultAdapterID())
 }
@@ -2,8 +2,8 @@ package beacon
 
 import (
 	"context"
-	"strings"
 
+	"github.com/dimonzozo/go-bluetooth/bluez"
 	"github.com/dimonzozo/go-bluetooth/bluez/profile/advertising"
 	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
 )
@@ -15,12 +15,14 @@ type BeaconType string
 const (
 	BeaconTypeEddystone = "eddystone"
 	BeaconTypeIBeacon   = "ibeacon"
+	BeaconTypeAltBeacon = "altbeacon"
 )
 
 type Beacon struct {
 	Name      string
 	iBeacon   BeaconIBeacon
 	eddystone BeaconEddystone
+	altBeacon BeaconAltBeacon
 	props     *advertising.LEAdvertisement1Properties
 	Type      BeaconType
 	Device    *device.Device1
@@ -44,6 +46,11 @@ func (b *Beacon) IsIBeacon() bool {
 	return b.Type == BeaconTypeIBeacon
 }
 
+// IsAltBeacon return if the type of beacon is altbeacon
+func (b *Beacon) IsAltBeacon() bool {
+	return b.Type == BeaconTypeAltBeacon
+}
+
 // WatchDeviceChanges watch for properties changes
 func (b *Beacon) WatchDeviceChanges(ctx context.Context) (chan bool, error) {
 
@@ -90,12 +97,21 @@ func (b *Beacon) GetIBeacon() BeaconIBeacon {
 	return b.iBeacon
 }
 
+// GetAltBeacon return altbeacon information
+func (b *Beacon) GetAltBeacon() BeaconAltBeacon {
+	return b.altBeacon
+}
+
 // GetFrames return the bytes content
 func (b *Beacon) GetFrames() []byte {
 	if b.IsIBeacon() {
 		return b.props.ManufacturerData[appleBit].([]byte)
 	}
-	return b.props.ServiceData[eddystoneSrvcUid].([]byte)
+	if b.IsAltBeacon() {
+		return b.props.ManufacturerData[b.altBeacon.ManufacturerID].([]byte)
+	}
+	data, _ := serviceDataFor(b.props.ServiceData, EddystoneServiceUUID)
+	return data
 }
 
 // Load beacon inforamtion if available
@@ -110,6 +126,9 @@ func (b *Beacon) Parse() bool {
 		if b.parserIBeacon(props.ManufacturerData) {
 			return true
 		}
+		if b.parserAltBeacon(props.ManufacturerData) {
+			return true
+		}
 
 	}
 
@@ -121,6 +140,9 @@ func (b *Beacon) Parse() bool {
 		if b.parserIBeacon(props.ManufacturerData) {
 			return true
 		}
+		if b.parserAltBeacon(props.ManufacturerData) {
+			return true
+		}
 	}
 
 	return false
@@ -142,21 +164,29 @@ func (b *Beacon) parserIBeacon(manufacturerData map[uint16]interface{}) bool {
 
 func (b *Beacon) parserEddystone(UUIDs []string, serviceData map[string]interface{}) bool {
 	for _, uuid := range UUIDs {
-		// 0000feaa-
-		srcUUID := uuid
-		if len(uuid) > 8 {
-			uuid = uuid[4:8]
+		if !bluez.EqualUUID(uuid, eddystoneSrvcUid) {
+			continue
 		}
-
-		if strings.ToUpper(uuid) == eddystoneSrvcUid {
-			if data, ok := serviceData[srcUUID]; ok {
-				// log.Debug("Found Eddystone")
-				b.Type = BeaconTypeEddystone
-				// log.Debugf("Eddystone data: %d", data)
-				b.eddystone = b.ParseEddystone(data.([]byte))
-				return true
-			}
+		if data, ok := serviceDataFor(serviceData, EddystoneServiceUUID); ok {
+			// log.Debug("Found Eddystone")
+			b.Type = BeaconTypeEddystone
+			// log.Debugf("Eddystone data: %d", data)
+			b.eddystone = b.ParseEddystone(data)
+			return true
 		}
 	}
 	return false
 }
+
+// serviceDataFor return the service data of a service, keys may use any UUID form
+func serviceDataFor(serviceData map[string]interface{}, uuid bluez.UUID) ([]byte, bool) {
+	for key, data := range serviceData {
+		u, err := bluez.ParseUUID(key)
+		if err != nil || u != uuid {
+			continue
+		}
+		b, ok := data.([]byte)
+		return b, ok
+	}
+	return nil, false
+}
@@ -0,0 +1,127 @@
+package beacon
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// altBeaconCode is the beacon code of the AltBeacon frames
+const altBeaconCode uint16 = 0xBEAC
+
+const altBeaconLength = 24
+
+type BeaconAltBeacon struct {
+	// ManufacturerID is the company identifier of the beacon manufacturer
+	ManufacturerID uint16
+	// BeaconID is the 20 bytes beacon identifier, usually split in
+	// ID1 (16 bytes), ID2 and ID3 (2 bytes)
+	BeaconID string
+	ID1      string
+	ID2      uint16
+	ID3      uint16
+	// ReferenceRSSI is the RSSI at 1 meter
+	ReferenceRSSI int
+	// Reserved is the manufacturer reserved byte
+	Reserved byte
+}
+
+// AltBeacon specification, manufacturer data
+// https://github.com/AltBeacon/spec
+// Byte(s) 	Name 						Notes
+// 0-1 			Company ID 			Manufacturer company identifier, little endian
+// ---- Bluez data starts here ----
+// 2-3 			Beacon Code 		0xBEAC
+// 4-23 		Beacon ID 			20 bytes identifier
+// 24 			Reference RSSI 	Signed RSSI at 1 meter
+// 25 			MFG RSVD 				Manufacturer reserved
+func isAltBeacon(frames []byte) bool {
+	return len(frames) >= altBeaconLength && binary.BigEndian.Uint16(frames[0:2]) == altBeaconCode
+}
+
+func parseAltBeacon(manufacturerID uint16, frames []byte) BeaconAltBeacon {
+
+	info := BeaconAltBeacon{
+		ManufacturerID: manufacturerID,
+	}
+
+	info.BeaconID = strings.ToUpper(hex.EncodeToString(frames[2:22]))
+	info.ID1 = strings.ToUpper(hex.EncodeToString(frames[2:18]))
+	info.ID2 = binary.BigEndian.Uint16(frames[18:20])
+	info.ID3 = binary.BigEndian.Uint16(frames[20:22])
+	info.ReferenceRSSI = byteToInt(frames[22])
+	info.Reserved = frames[23]
+
+	return info
+}
+
+// RadiusNetworksManufacturerID is the company identifier used by the
+// AltBeacon reference implementation, any manufacturer ID can be used
+const RadiusNetworksManufacturerID uint16 = 0x0118
+
+// ParseAltBeacon parse the manufacturer data of an AltBeacon, starting with
+// the beacon code
+func ParseAltBeacon(manufacturerID uint16, frames []byte) (BeaconAltBeacon, error) {
+	if !isAltBeacon(frames) {
+		return BeaconAltBeacon{}, fmt.Errorf("AltBeacon: expected %d bytes starting with %04X", altBeaconLength, altBeaconCode)
+	}
+	return parseAltBeacon(manufacturerID, frames), nil
+}
+
+// MakeAltBeaconFrame return the manufacturer data of an AltBeacon from a 20
+// bytes beacon ID in hex form, dashes are ignored
+func MakeAltBeaconFrame(beaconID string, referenceRSSI int, reserved byte) ([]byte, error) {
+
+	id, err := hex.DecodeString(strings.Replace(beaconID, "-", "", -1))
+	if err != nil {
+		return nil, fmt.Errorf("AltBeacon: %s", err)
+	}
+	if len(id) != 20 {
+		return nil, fmt.Errorf("AltBeacon: beacon ID must be 20 bytes, got %d", len(id))
+	}
+	if referenceRSSI < -128 || referenceRSSI > 127 {
+		return nil, fmt.Errorf("AltBeacon: reference RSSI %d out of range", referenceRSSI)
+	}
+
+	frames := make([]byte, 2, altBeaconLength)
+	binary.BigEndian.PutUint16(frames, altBeaconCode)
+	frames = append(frames, id...)
+	frames = append(frames, byte(int8(referenceRSSI)), reserved)
+
+	return frames, nil
+}
+
+// CreateAltBeacon create a beacon in the AltBeacon format advertised with the
+// manufacturer ID, eg. RadiusNetworksManufacturerID
+func CreateAltBeacon(manufacturerID uint16, beaconID string, referenceRSSI int, reserved byte) (*Beacon, error) {
+
+	frames, err := MakeAltBeaconFrame(beaconID, referenceRSSI, reserved)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := initBeacon()
+	if err != nil {
+		return nil, err
+	}
+
+	b.Type = BeaconTypeAltBeacon
+	b.altBeacon = parseAltBeacon(manufacturerID, frames)
+	b.props.AddManifacturerData(manufacturerID, frames)
+
+	return b, nil
+}
+
+func (b *Beacon) parserAltBeacon(manufacturerData map[uint16]interface{}) bool {
+	for id, data := range manufacturerData {
+		frames, ok := data.([]byte)
+		if !ok || !isAltBeacon(frames) {
+			continue
+		}
+		b.Type = BeaconTypeAltBeacon
+		b.altBeacon = parseAltBeacon(id, frames)
+		return true
+	}
+	return false
+}
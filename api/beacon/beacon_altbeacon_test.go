@@ -0,0 +1,68 @@
+package beacon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAltBeacon(t *testing.T) {
+
+	b, err := CreateAltBeacon(0x004C, "AAAABBBB-CCCC-DDDD-AAAA-BBBBCCCCDDDD00010002", -59, 0x01)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, b.IsAltBeacon())
+	frames := b.GetFrames()
+	assert.Equal(t, 24, len(frames))
+	assert.Equal(t, []byte{0xBE, 0xAC}, frames[:2])
+
+	info, err := ParseAltBeacon(0x004C, frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint16(0x004C), info.ManufacturerID)
+	assert.Equal(t, "AAAABBBBCCCCDDDDAAAABBBBCCCCDDDD", info.ID1)
+	assert.Equal(t, uint16(1), info.ID2)
+	assert.Equal(t, uint16(2), info.ID3)
+	assert.Equal(t, -59, info.ReferenceRSSI)
+	assert.Equal(t, byte(1), info.Reserved)
+	assert.Equal(t, info, b.GetAltBeacon())
+}
+
+func TestParseAltBeaconProps(t *testing.T) {
+
+	frames, err := MakeAltBeaconFrame("000102030405060708090a0b0c0d0e0f10111213", -60, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := Beacon{}
+	assert.True(t, b.parserAltBeacon(map[uint16]interface{}{
+		RadiusNetworksManufacturerID: frames,
+	}))
+	assert.Equal(t, BeaconTypeAltBeacon, string(b.Type))
+	assert.Equal(t, RadiusNetworksManufacturerID, b.GetAltBeacon().ManufacturerID)
+	assert.Equal(t, "000102030405060708090A0B0C0D0E0F10111213", b.GetAltBeacon().BeaconID)
+
+	b = Beacon{}
+	assert.False(t, b.parserAltBeacon(map[uint16]interface{}{
+		RadiusNetworksManufacturerID: frames[:10],
+	}))
+}
+
+func TestMakeAltBeaconFrameErrors(t *testing.T) {
+
+	_, err := MakeAltBeaconFrame("0001", -59, 0)
+	assert.Error(t, err)
+
+	_, err = MakeAltBeaconFrame("zz0102030405060708090a0b0c0d0e0f10111213", -59, 0)
+	assert.Error(t, err)
+
+	_, err = MakeAltBeaconFrame("000102030405060708090a0b0c0d0e0f10111213", -200, 0)
+	assert.Error(t, err)
+
+	_, err = ParseAltBeacon(RadiusNetworksManufacturerID, []byte{0x02, 0x15})
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"strings"
 
+	"github.com/dimonzozo/go-bluetooth/bluez"
 	"github.com/dimonzozo/go-bluetooth/bluez/profile/advertising"
 	eddystone "github.com/suapapa/go_eddystone"
 )
@@ -15,6 +16,11 @@ func initBeacon() (*Beacon, error) {
 	return b, nil
 }
 
+// CreateIBeaconUUID Create a beacon in the IBeacon format from a typed UUID
+func CreateIBeaconUUID(uuid bluez.UUID, major uint16, minor uint16, measuredPower uint16) (*Beacon, error) {
+	return CreateIBeacon(strings.ToUpper(hex.EncodeToString(uuid[:])), major, minor, measuredPower)
+}
+
 // CreateIBeacon Create a beacon in the IBeacon format
 func CreateIBeacon(uuid string, major uint16, minor uint16, measuredPower uint16) (*Beacon, error) {
 
@@ -66,7 +72,7 @@ func CreateIBeacon(uuid string, major uint16, minor uint16, measuredPower uint16
 func appendEddystoneService(UUIDs []string) []string {
 	found := false
 	for _, uuid := range UUIDs {
-		if uuid == eddystoneSrvcUid {
+		if bluez.EqualUUID(uuid, eddystoneSrvcUid) {
 			found = true
 		}
 	}
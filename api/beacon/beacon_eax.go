@@ -0,0 +1,132 @@
+package beacon
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+)
+
+// AES-EAX and AES-CMAC as used by Eddystone encrypted TLM frames
+// http://web.cs.ucdavis.edu/~rogaway/papers/eax.pdf
+// https://tools.ietf.org/html/rfc4493
+
+var errEAXAuth = errors.New("EAX: message authentication failed")
+
+// cmacSubkeys derive the K1 and K2 CMAC subkeys
+func cmacSubkeys(block cipher.Block) ([]byte, []byte) {
+	l := make([]byte, aes.BlockSize)
+	block.Encrypt(l, l)
+	k1 := cmacShift(l)
+	k2 := cmacShift(k1)
+	return k1, k2
+}
+
+// cmacShift shift left by one bit and xor with Rb if the msb was set
+func cmacShift(in []byte) []byte {
+	out := make([]byte, len(in))
+	var carry byte
+	for i := len(in) - 1; i >= 0; i-- {
+		out[i] = in[i]<<1 | carry
+		carry = in[i] >> 7
+	}
+	if carry == 1 {
+		out[len(out)-1] ^= 0x87
+	}
+	return out
+}
+
+// cmac return the AES-CMAC of msg
+func cmac(block cipher.Block, msg []byte) []byte {
+
+	k1, k2 := cmacSubkeys(block)
+
+	n := (len(msg) + aes.BlockSize - 1) / aes.BlockSize
+	complete := n > 0 && len(msg)%aes.BlockSize == 0
+	if n == 0 {
+		n = 1
+	}
+
+	last := make([]byte, aes.BlockSize)
+	tail := msg[(n-1)*aes.BlockSize:]
+	if complete {
+		xorBytes(last, tail, k1)
+	} else {
+		copy(last, tail)
+		last[len(tail)] = 0x80
+		xorBytes(last, last, k2)
+	}
+
+	x := make([]byte, aes.BlockSize)
+	for i := 0; i < n-1; i++ {
+		xorBytes(x, x, msg[i*aes.BlockSize:(i+1)*aes.BlockSize])
+		block.Encrypt(x, x)
+	}
+	xorBytes(x, x, last)
+	block.Encrypt(x, x)
+
+	return x
+}
+
+// omac return the EAX OMAC of msg tweaked with t
+func omac(block cipher.Block, t byte, msg []byte) []byte {
+	buf := make([]byte, aes.BlockSize, aes.BlockSize+len(msg))
+	buf[aes.BlockSize-1] = t
+	return cmac(block, append(buf, msg...))
+}
+
+// eaxSeal encrypt plaintext and return the ciphertext and the tag truncated
+// to tagSize bytes
+func eaxSeal(key, nonce, header, plaintext []byte, tagSize int) ([]byte, []byte, error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	n := omac(block, 0, nonce)
+	h := omac(block, 1, header)
+
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCTR(block, n).XORKeyStream(ciphertext, plaintext)
+
+	c := omac(block, 2, ciphertext)
+
+	tag := make([]byte, aes.BlockSize)
+	xorBytes(tag, n, h)
+	xorBytes(tag, tag, c)
+
+	return ciphertext, tag[:tagSize], nil
+}
+
+// eaxOpen verify the truncated tag and decrypt ciphertext
+func eaxOpen(key, nonce, header, ciphertext, tag []byte) ([]byte, error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	n := omac(block, 0, nonce)
+	h := omac(block, 1, header)
+	c := omac(block, 2, ciphertext)
+
+	expected := make([]byte, aes.BlockSize)
+	xorBytes(expected, n, h)
+	xorBytes(expected, expected, c)
+
+	if len(tag) == 0 || len(tag) > aes.BlockSize || subtle.ConstantTimeCompare(expected[:len(tag)], tag) != 1 {
+		return nil, errEAXAuth
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCTR(block, n).XORKeyStream(plaintext, ciphertext)
+
+	return plaintext, nil
+}
+
+func xorBytes(dst, a, b []byte) {
+	for i := range dst {
+		dst[i] = a[i] ^ b[i]
+	}
+}
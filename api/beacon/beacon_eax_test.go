@@ -0,0 +1,60 @@
+package beacon
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func unhex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestCMAC(t *testing.T) {
+
+	// RFC 4493 test vectors
+	block, err := aes.NewCipher(unhex(t, "2b7e151628aed2a6abf7158809cf4f3c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "bb1d6929e95937287fa37d129b756746", hex.EncodeToString(cmac(block, []byte{})))
+	assert.Equal(t, "070a16b46b4d4144f79bdd9dd04a287c",
+		hex.EncodeToString(cmac(block, unhex(t, "6bc1bee22e409f96e93d7e117393172a"))))
+	assert.Equal(t, "dfa66747de9ae63030ca32611497c827",
+		hex.EncodeToString(cmac(block, unhex(t, "6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e5130c81c46a35ce411"))))
+}
+
+func TestEAX(t *testing.T) {
+
+	// test vectors of the EAX paper
+	vectors := []struct {
+		key, nonce, header, msg, cipher string
+	}{
+		{"233952DEE4D5ED5F9B9C6D6FF80FF478", "62EC67F9C3A4A407FCB2A8C49031A8B3", "6BFB914FD07EAE6B", "", "E037830E8389F27B025A2D6527E79D01"},
+		{"91945D3F4DCBEE0BF45EF52255F095A4", "BECAF043B0A23D843194BA972C66DEBD", "FA3BFD4806EB53FA", "F7FB", "19DD5C4C9331049D0BDAB0277408F67967E5"},
+	}
+
+	for _, v := range vectors {
+		key, nonce, header, msg := unhex(t, v.key), unhex(t, v.nonce), unhex(t, v.header), unhex(t, v.msg)
+		expected := unhex(t, v.cipher)
+
+		ciphertext, tag, err := eaxSeal(key, nonce, header, msg, 16)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, append(ciphertext, tag...))
+
+		plaintext, err := eaxOpen(key, nonce, header, ciphertext, tag)
+		assert.NoError(t, err)
+		assert.Equal(t, msg, plaintext)
+
+		tag[0] ^= 1
+		_, err = eaxOpen(key, nonce, header, ciphertext, tag)
+		assert.Error(t, err)
+	}
+}
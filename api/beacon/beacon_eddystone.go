@@ -4,22 +4,42 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"strings"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
 )
 
 const eddystoneSrvcUid = "FEAA"
 
+// EddystoneServiceUUID is the service UUID of Eddystone frames
+var EddystoneServiceUUID = bluez.UUID16(0xFEAA)
+
 const (
 	frameTypeUID byte = 0x00
 	frameTypeURL      = 0x10
 	frameTypeTLM      = 0x20
+	frameTypeEID      = 0x30
+)
+
+// min length of the frames
+const (
+	frameLengthUID = 18
+	frameLengthURL = 3
+	frameLengthTLM = 14
+	frameLengthEID = 10
+	// encrypted TLM
+	frameLengthETLM = 18
 )
 
+// etlmVersion is the TLM version of the encrypted TLM frames
+const etlmVersion = 0x01
+
 type EddystoneFrame string
 
 const (
 	EddystoneFrameUID EddystoneFrame = "uid"
 	EddystoneFrameURL                = "url"
 	EddystoneFrameTLM                = "tlm"
+	EddystoneFrameEID                = "eid"
 )
 
 type BeaconEddystone struct {
@@ -31,38 +51,58 @@ type BeaconEddystone struct {
 
 	URL string
 
+	// eddystone-eid, ephemeral identifier
+	EID string
+
 	// eddystone-tlm plain
 	TLMVersion          int
 	TLMBatteryVoltage   uint16
 	TLMTemperature      float32
 	TLMAdvertisingPDU   uint32
 	TLMLastRebootedTime uint32
+
+	// eddystone-tlm encrypted, TLMVersion is 1. The plain fields are set by
+	// DecryptETLM
+	ETLM     []byte
+	ETLMSalt uint16
+	ETLMMIC  uint16
 }
 
+// ParseEddystone parse an Eddystone frame, Frame is empty if the frame is
+// unknown or too short
 func (b *Beacon) ParseEddystone(frames []byte) BeaconEddystone {
 
 	info := BeaconEddystone{}
 
+	if len(frames) == 0 {
+		return info
+	}
+
 	switch frames[0] {
 	case frameTypeUID:
-		{
+		if len(frames) >= frameLengthUID {
 			info.Frame = EddystoneFrameUID
 			parseEddystoneUID(&info, frames)
 		}
-		break
 	case frameTypeTLM:
-		{
+		if len(frames) >= 2 && frames[1] == etlmVersion {
+			if len(frames) >= frameLengthETLM {
+				info.Frame = EddystoneFrameTLM
+				parseEddystoneETLM(&info, frames)
+			}
+		} else if len(frames) >= frameLengthTLM {
 			info.Frame = EddystoneFrameTLM
 			parseEddystoneTLM(&info, frames)
 		}
-		break
 	case frameTypeURL:
-		{
+		if len(frames) >= frameLengthURL && parseEddystoneURL(&info, frames) == nil {
 			info.Frame = EddystoneFrameURL
-			parseEddystoneURL(&info, frames)
 		}
-		break
-
+	case frameTypeEID:
+		if len(frames) >= frameLengthEID {
+			info.Frame = EddystoneFrameEID
+			parseEddystoneEID(&info, frames)
+		}
 	}
 
 	return info
@@ -181,3 +221,14 @@ func parseEddystoneURL(info *BeaconEddystone, frames []byte) error {
 
 	return nil
 }
+
+// eddystone-eid
+// https://github.com/google/eddystone/tree/master/eddystone-eid
+// Byte offset	Field	Description
+// 0	          Frame Type	Value = 0x30
+// 1	          TX Power	Calibrated Tx power at 0 m
+// 2-9	        EID	8-byte ephemeral identifier
+func parseEddystoneEID(info *BeaconEddystone, frames []byte) {
+	info.CalibratedTxPower = byteToInt(frames[1])
+	info.EID = strings.ToUpper(hex.EncodeToString(frames[2:10]))
+}
@@ -0,0 +1,292 @@
+package beacon
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	eddystone "github.com/suapapa/go_eddystone"
+)
+
+// MaxRotationExponent is the max EID rotation period exponent, the
+// identifier rotates every 2^exponent seconds
+const MaxRotationExponent = 15
+
+// EIDLength is the length of an ephemeral identifier
+const EIDLength = 8
+
+// eddystone-eid computation
+// https://github.com/google/eddystone/blob/master/eddystone-eid/eid-computation.md
+// temporary key = AES(identity key, 0x00 * 11 | 0xFF | 0x00 * 2 | ts[0] | ts[1])
+// EID = AES(temporary key, 0x00 * 11 | K | ts[0] | ts[1] | ts[2] | ts[3])[0:8]
+// where ts is the beacon time counter in seconds with the lowest K bits cleared
+
+// ComputeEID return the ephemeral identifier of a beacon at beaconTime, the
+// beacon time counter in seconds
+func ComputeEID(identityKey []byte, rotationExponent uint8, beaconTime uint32) ([]byte, error) {
+
+	if rotationExponent > MaxRotationExponent {
+		return nil, fmt.Errorf("EID: rotation exponent %d out of range", rotationExponent)
+	}
+
+	block, err := aes.NewCipher(identityKey)
+	if err != nil {
+		return nil, fmt.Errorf("EID: %s", err)
+	}
+
+	ts := quantizeBeaconTime(rotationExponent, beaconTime)
+
+	tk := make([]byte, aes.BlockSize)
+	tk[11] = 0xFF
+	tk[14] = byte(ts >> 24)
+	tk[15] = byte(ts >> 16)
+	block.Encrypt(tk, tk)
+
+	block, err = aes.NewCipher(tk)
+	if err != nil {
+		return nil, fmt.Errorf("EID: %s", err)
+	}
+
+	eid := make([]byte, aes.BlockSize)
+	eid[11] = rotationExponent
+	binary.BigEndian.PutUint32(eid[12:], ts)
+	block.Encrypt(eid, eid)
+
+	return eid[:EIDLength], nil
+}
+
+// quantizeBeaconTime clear the lowest K bits of the beacon time
+func quantizeBeaconTime(rotationExponent uint8, beaconTime uint32) uint32 {
+	return (beaconTime >> rotationExponent) << rotationExponent
+}
+
+// MakeEIDFrame return an Eddystone-EID frame
+func MakeEIDFrame(identityKey []byte, rotationExponent uint8, beaconTime uint32, txPower int) ([]byte, error) {
+
+	if txPower < -100 || txPower > 20 {
+		return nil, fmt.Errorf("EID: tx power %d out of range", txPower)
+	}
+
+	eid, err := ComputeEID(identityKey, rotationExponent, beaconTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{frameTypeEID, byte(int8(txPower))}, eid...), nil
+}
+
+// CreateEddystoneEID create an eddystone beacon frame with the ephemeral
+// identifier at beaconTime, the frame must be created again on rotation
+func CreateEddystoneEID(identityKey []byte, rotationExponent uint8, beaconTime uint32, txPower int) (*Beacon, error) {
+
+	frames, err := MakeEIDFrame(identityKey, rotationExponent, beaconTime, txPower)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := initBeacon()
+	if err != nil {
+		return nil, err
+	}
+
+	b.props.AddServiceUUID(eddystoneSrvcUid)
+	b.props.AddServiceData(eddystoneSrvcUid, frames)
+
+	b.Type = BeaconTypeEddystone
+	b.eddystone = b.ParseEddystone(frames)
+
+	return b, nil
+}
+
+// eddystone-tlm (encrypted)
+// https://github.com/google/eddystone/blob/master/eddystone-tlm/tlm-encrypted.md
+// Byte offset	Field	Description
+// 0	          Frame Type	Value = 0x20
+// 1	          Version	TLM version, value = 0x01
+// 2-13	        ETLM	12 bytes of encrypted plain TLM data (VBATT to SEC_CNT)
+// 14-15	      SALT	16 bit salt
+// 16-17	      MIC	16 bit message integrity check
+//
+// ETLM is AES-EAX encrypted with the identity key, the nonce is the beacon
+// time quantized as for the EID, followed by the salt
+func parseEddystoneETLM(info *BeaconEddystone, frames []byte) {
+	info.TLMVersion = int(frames[1])
+	info.ETLM = append([]byte{}, frames[2:14]...)
+	info.ETLMSalt = binary.BigEndian.Uint16(frames[14:16])
+	info.ETLMMIC = binary.BigEndian.Uint16(frames[16:18])
+}
+
+func etlmNonce(rotationExponent uint8, beaconTime uint32, salt uint16) []byte {
+	nonce := make([]byte, 6)
+	binary.BigEndian.PutUint32(nonce, quantizeBeaconTime(rotationExponent, beaconTime))
+	binary.BigEndian.PutUint16(nonce[4:], salt)
+	return nonce
+}
+
+// DecryptETLM decrypt an encrypted TLM frame and return the frame with the
+// plain TLM fields set
+func DecryptETLM(identityKey []byte, rotationExponent uint8, beaconTime uint32, info BeaconEddystone) (BeaconEddystone, error) {
+
+	if info.Frame != EddystoneFrameTLM || len(info.ETLM) != frameLengthTLM-2 {
+		return info, errors.New("ETLM: not an encrypted TLM frame")
+	}
+
+	mic := make([]byte, 2)
+	binary.BigEndian.PutUint16(mic, info.ETLMMIC)
+
+	plain, err := eaxOpen(identityKey, etlmNonce(rotationExponent, beaconTime, info.ETLMSalt), nil, info.ETLM, mic)
+	if err != nil {
+		return info, fmt.Errorf("ETLM: %s", err)
+	}
+
+	// the plain fields, version is kept to the ETLM one
+	parseEddystoneTLM(&info, append([]byte{frameTypeTLM, 0x00}, plain...))
+	info.TLMVersion = etlmVersion
+
+	return info, nil
+}
+
+// MakeETLMFrame return an encrypted TLM frame, salt should be random for
+// each frame
+func MakeETLMFrame(identityKey []byte, rotationExponent uint8, beaconTime uint32, salt uint16, batt uint16, temp float32, advCnt, secCnt uint32) ([]byte, error) {
+
+	plain, err := eddystone.MakeTLMFrame(batt, temp, advCnt, secCnt)
+	if err != nil {
+		return nil, err
+	}
+
+	etlm, mic, err := eaxSeal(identityKey, etlmNonce(rotationExponent, beaconTime, salt), nil, []byte(plain)[2:frameLengthTLM], 2)
+	if err != nil {
+		return nil, fmt.Errorf("ETLM: %s", err)
+	}
+
+	frames := []byte{frameTypeTLM, etlmVersion}
+	frames = append(frames, etlm...)
+	frames = append(frames, byte(salt>>8), byte(salt))
+	frames = append(frames, mic...)
+
+	return frames, nil
+}
+
+// DefaultEIDWindows is the number of rotation periods checked before and
+// after the expected one when resolving
+const DefaultEIDWindows = 1
+
+// NewEIDResolver return a resolver of the ephemeral identifiers of the
+// registered beacons
+func NewEIDResolver() *EIDResolver {
+	return &EIDResolver{
+		Windows: DefaultEIDWindows,
+		beacons: make(map[string]*eidBeacon),
+	}
+}
+
+// EIDResolver resolve ephemeral identifiers to the registered beacons
+type EIDResolver struct {
+	// Windows is the number of rotation periods checked before and after
+	// the expected one, to tolerate clock drift
+	Windows int
+
+	lock    sync.RWMutex
+	beacons map[string]*eidBeacon
+}
+
+type eidBeacon struct {
+	identityKey      []byte
+	rotationExponent uint8
+	beaconTime       uint32
+	registered       time.Time
+}
+
+// beaconTime estimate the beacon time counter at now
+func (b *eidBeacon) beaconTimeAt(now time.Time) int64 {
+	return int64(b.beaconTime) + int64(now.Sub(b.registered)/time.Second)
+}
+
+// Register a beacon by id, beaconTime is its time counter at the time at
+func (r *EIDResolver) Register(id string, identityKey []byte, rotationExponent uint8, beaconTime uint32, at time.Time) error {
+
+	if len(identityKey) != aes.BlockSize {
+		return fmt.Errorf("EID: identity key must be %d bytes", aes.BlockSize)
+	}
+	if rotationExponent > MaxRotationExponent {
+		return fmt.Errorf("EID: rotation exponent %d out of range", rotationExponent)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.beacons[id] = &eidBeacon{
+		identityKey:      append([]byte{}, identityKey...),
+		rotationExponent: rotationExponent,
+		beaconTime:       beaconTime,
+		registered:       at,
+	}
+
+	return nil
+}
+
+// Remove a registered beacon
+func (r *EIDResolver) Remove(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.beacons, id)
+}
+
+// candidateTimes return the beacon times of the rotation periods to check
+func (r *EIDResolver) candidateTimes(b *eidBeacon, now time.Time) []uint32 {
+	t := b.beaconTimeAt(now)
+	period := int64(1) << b.rotationExponent
+	times := []uint32{}
+	for w := -r.Windows; w <= r.Windows; w++ {
+		ts := t + int64(w)*period
+		if ts < 0 || ts > int64(^uint32(0)) {
+			continue
+		}
+		times = append(times, uint32(ts))
+	}
+	return times
+}
+
+// Resolve return the id of the beacon advertising the ephemeral identifier
+func (r *EIDResolver) Resolve(eid []byte, now time.Time) (string, bool) {
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for id, b := range r.beacons {
+		for _, ts := range r.candidateTimes(b, now) {
+			computed, err := ComputeEID(b.identityKey, b.rotationExponent, ts)
+			if err == nil && bytes.Equal(computed, eid) {
+				return id, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// DecryptETLM decrypt an encrypted TLM frame of a registered beacon
+func (r *EIDResolver) DecryptETLM(id string, info BeaconEddystone, now time.Time) (BeaconEddystone, error) {
+
+	r.lock.RLock()
+	b, ok := r.beacons[id]
+	r.lock.RUnlock()
+	if !ok {
+		return info, fmt.Errorf("ETLM: beacon %s not registered", id)
+	}
+
+	err := fmt.Errorf("ETLM: no beacon time to check")
+	for _, ts := range r.candidateTimes(b, now) {
+		var decrypted BeaconEddystone
+		decrypted, err = DecryptETLM(b.identityKey, b.rotationExponent, ts, info)
+		if err == nil {
+			return decrypted, nil
+		}
+	}
+
+	return info, err
+}
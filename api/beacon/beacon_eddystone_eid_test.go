@@ -0,0 +1,178 @@
+package beacon
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testIdentityKey = []byte{
+	0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+	0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F,
+}
+
+func TestComputeEID(t *testing.T) {
+
+	// exponent 10, the EID rotates every 1024 seconds
+	eid1, err := ComputeEID(testIdentityKey, 10, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, EIDLength, len(eid1))
+
+	eid2, err := ComputeEID(testIdentityKey, 10, 2047)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, eid1, eid2)
+
+	eid3, err := ComputeEID(testIdentityKey, 10, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, eid1, eid3)
+
+	_, err = ComputeEID(testIdentityKey, MaxRotationExponent+1, 0)
+	assert.Error(t, err)
+	_, err = ComputeEID(testIdentityKey[:4], 10, 0)
+	assert.Error(t, err)
+}
+
+func TestComputeEIDKnownAnswer(t *testing.T) {
+
+	// computed following eid-computation.md with an independent AES
+	// implementation (openssl enc -aes-128-ecb -nopad):
+	// temporary key = AES(ik, 00 * 11 | FF | 00 00 | 00 12) = 3D32BD6E944749545B32514A92F6B0A4
+	// EID = AES(temporary key, 00 * 11 | 0A | 00 12 A0 00)[0:8]
+	eid, err := ComputeEID(testIdentityKey, 10, 0x0012A3F7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "7F9266C3F280B444", strings.ToUpper(hex.EncodeToString(eid)))
+
+	frame, err := MakeEIDFrame(testIdentityKey, 10, 0x0012A3F7, -18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, eid, frame[2:2+EIDLength])
+}
+
+func TestCreateEddystoneEID(t *testing.T) {
+
+	b, err := CreateEddystoneEID(testIdentityKey, 10, 5000, -18)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eid, err := ComputeEID(testIdentityKey, 10, 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, b.IsEddystone())
+	info := b.ParseEddystone(b.GetFrames())
+	assert.Equal(t, EddystoneFrameEID, string(info.Frame))
+	assert.Equal(t, -18, info.CalibratedTxPower)
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(eid)), info.EID)
+
+	_, err = CreateEddystoneEID(testIdentityKey, 10, 5000, 50)
+	assert.Error(t, err)
+}
+
+func TestEIDResolver(t *testing.T) {
+
+	now := time.Now()
+
+	r := NewEIDResolver()
+	err := r.Register("beacon1", testIdentityKey, 10, 5000, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, r.Register("beacon2", testIdentityKey[:4], 10, 5000, now))
+
+	eid, err := ComputeEID(testIdentityKey, 10, 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, ok := r.Resolve(eid, now)
+	assert.True(t, ok)
+	assert.Equal(t, "beacon1", id)
+
+	// the beacon clock is one period late
+	id, ok = r.Resolve(eid, now.Add(1024*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, "beacon1", id)
+
+	// out of the drift windows
+	_, ok = r.Resolve(eid, now.Add(3*1024*time.Second))
+	assert.False(t, ok)
+
+	r.Remove("beacon1")
+	_, ok = r.Resolve(eid, now)
+	assert.False(t, ok)
+}
+
+func TestEddystoneETLM(t *testing.T) {
+
+	frames, err := MakeETLMFrame(testIdentityKey, 10, 5000, 0x1234, 3000, 21.5, 100, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 18, len(frames))
+
+	b := Beacon{}
+	info := b.ParseEddystone(frames)
+	assert.Equal(t, EddystoneFrameTLM, string(info.Frame))
+	assert.Equal(t, 1, info.TLMVersion)
+	assert.Equal(t, uint16(0x1234), info.ETLMSalt)
+	assert.Equal(t, uint16(0), info.TLMBatteryVoltage)
+
+	plain, err := DecryptETLM(testIdentityKey, 10, 5000, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, plain.TLMVersion)
+	assert.Equal(t, uint16(3000), plain.TLMBatteryVoltage)
+	assert.Equal(t, float32(21.5), plain.TLMTemperature)
+	assert.Equal(t, uint32(100), plain.TLMAdvertisingPDU)
+	assert.Equal(t, uint32(200), plain.TLMLastRebootedTime)
+
+	// another rotation period use another nonce
+	_, err = DecryptETLM(testIdentityKey, 10, 4000, info)
+	assert.Error(t, err)
+
+	info.ETLMMIC ^= 0x01
+	_, err = DecryptETLM(testIdentityKey, 10, 5000, info)
+	assert.Error(t, err)
+}
+
+func TestEIDResolverDecryptETLM(t *testing.T) {
+
+	now := time.Now()
+
+	r := NewEIDResolver()
+	err := r.Register("beacon1", testIdentityKey, 10, 5000, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames, err := MakeETLMFrame(testIdentityKey, 10, 6100, 0x0001, 3000, 21.5, 100, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := Beacon{}
+	info := b.ParseEddystone(frames)
+
+	plain, err := r.DecryptETLM("beacon1", info, now.Add(1100*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint16(3000), plain.TLMBatteryVoltage)
+
+	_, err = r.DecryptETLM("beacon2", info, now)
+	assert.Error(t, err)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"strings"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
 )
 
 type BeaconIBeacon struct {
@@ -14,6 +16,11 @@ type BeaconIBeacon struct {
 	MeasuredPower uint16
 }
 
+// GetUUID return the proximity UUID
+func (b BeaconIBeacon) GetUUID() (bluez.UUID, error) {
+	return bluez.ParseUUID(b.ProximityUUID)
+}
+
 // From Apple specifications
 // Byte(s) 	Name 						Value 		Notes
 // 0 				Flags[0] 				0x02 			See Bluetooth 4.0 Core Specification , Volume 3, Appendix C, 18.1.
@@ -0,0 +1,378 @@
+package beacon
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/api"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/adapter"
+	"github.com/godbus/dbus/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+// FrameType is the type of a beacon frame
+type FrameType string
+
+const (
+	FrameIBeacon      FrameType = "ibeacon"
+	FrameEddystoneUID FrameType = "eddystone-uid"
+	FrameEddystoneURL FrameType = "eddystone-url"
+	FrameEddystoneTLM FrameType = "eddystone-tlm"
+	FrameEddystoneEID FrameType = "eddystone-eid"
+	FrameAltBeacon    FrameType = "altbeacon"
+)
+
+// eddystoneLossAt1m is the path loss at 1 meter, Eddystone frames advertise
+// the calibrated TX power at 0 meters
+const eddystoneLossAt1m = 41
+
+// BeaconEvent is emitted for each beacon frame received
+type BeaconEvent struct {
+	Type FrameType
+	// ID is the beacon identity, derived from the frame content so it is
+	// stable across address and path changes. Eddystone TLM and URL frames
+	// get the identity of the UID or EID frames advertised from the same address
+	ID      string
+	Path    dbus.ObjectPath
+	Address string
+	RSSI    int16
+	// MeasuredPower is the RSSI at 1 meter, valid if HasMeasuredPower is set
+	MeasuredPower    int
+	HasMeasuredPower bool
+	// Distance is the estimated distance in meters, -1 if unknown
+	Distance  float64
+	Timestamp time.Time
+
+	IBeacon   *BeaconIBeacon
+	Eddystone *BeaconEddystone
+	AltBeacon *BeaconAltBeacon
+}
+
+// ScannerOptions configure a beacon Scanner
+type ScannerOptions struct {
+	// PathLossExponent of the environment, 2 if zero
+	PathLossExponent float64
+	// DiscoveryFilter is the filter of the discovery session, LE transport
+	// with duplicate data if nil
+	DiscoveryFilter *api.ExtendedDiscoveryFilter
+	// EIDResolver resolve Eddystone-EID frames to the registered beacons,
+	// which identity is used as ID, and decrypt their encrypted TLM frames
+	EIDResolver *EIDResolver
+	// IdentityTimeout is how long the identity of an address is kept after
+	// its last identifying frame, 10 minutes if zero. Identities are kept
+	// when Bluez removes the device, TLM and URL frames often follow later
+	IdentityTimeout time.Duration
+}
+
+// defaultIdentityTimeout is the IdentityTimeout used when not specified
+const defaultIdentityTimeout = 10 * time.Minute
+
+// NewScanner create a beacon scanner for the adapter
+func NewScanner(a *adapter.Adapter1, opts ScannerOptions) *Scanner {
+
+	if opts.PathLossExponent == 0 {
+		opts.PathLossExponent = 2
+	}
+	if opts.IdentityTimeout == 0 {
+		opts.IdentityTimeout = defaultIdentityTimeout
+	}
+	if opts.DiscoveryFilter == nil {
+		opts.DiscoveryFilter = api.NewExtendedDiscoveryFilter(nil)
+		opts.DiscoveryFilter.Transport = adapter.DiscoveryFilterTransportLE
+	}
+
+	return &Scanner{
+		adapter:    a,
+		opts:       opts,
+		identities: make(map[string]addressIdentity),
+		eidBeacons: make(map[string]addressIdentity),
+	}
+}
+
+// addressIdentity is an identity learned from the frames of an address
+type addressIdentity struct {
+	ID   string
+	Seen time.Time
+}
+
+// Scanner run a discovery session and emit an event for each beacon frame
+type Scanner struct {
+	adapter *adapter.Adapter1
+	opts    ScannerOptions
+
+	lock    sync.Mutex
+	session *api.DiscoverySession
+	quit    chan struct{}
+	done    chan struct{}
+	// identities map an address to the identity of its last identifying frame
+	identities map[string]addressIdentity
+	// eidBeacons map an address to the resolved EID beacon
+	eidBeacons map[string]addressIdentity
+}
+
+// Start discovery and return the channel receiving the beacon events,
+// the channel is closed by Stop
+func (s *Scanner) Start() (chan *BeaconEvent, error) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.session != nil {
+		return nil, errors.New("Beacon scanner already started")
+	}
+
+	session, err := api.GetDiscoveryManager(s.adapter).Start(s.opts.DiscoveryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	s.session = session
+	s.quit = make(chan struct{})
+	s.done = make(chan struct{})
+
+	events := make(chan *BeaconEvent)
+	go s.run(session.Reports(), events, s.quit, s.done)
+
+	return events, nil
+}
+
+// Stop discovery and close the events channel
+func (s *Scanner) Stop() error {
+
+	s.lock.Lock()
+	session := s.session
+	if session == nil {
+		s.lock.Unlock()
+		return nil
+	}
+	s.session = nil
+	close(s.quit)
+	done := s.done
+	s.lock.Unlock()
+
+	<-done
+	return session.Stop()
+}
+
+func (s *Scanner) run(reports chan *api.AdvertisementReport, events chan *BeaconEvent, quit chan struct{}, done chan struct{}) {
+
+	defer close(done)
+	defer close(events)
+
+	for {
+		select {
+		case report, ok := <-reports:
+			if !ok {
+				return
+			}
+			for _, ev := range s.handleReport(report) {
+				select {
+				case events <- ev:
+				case <-quit:
+					return
+				}
+			}
+		case <-quit:
+			return
+		}
+	}
+}
+
+// handleReport return the events of the beacon frames of a report. Frames
+// are emitted when their data changed or, for RSSI updates, all the frames
+func (s *Scanner) handleReport(report *api.AdvertisementReport) []*BeaconEvent {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if report.Removed {
+		// the identity of the address is kept until it expires
+		s.expireIdentities(report.Timestamp)
+		return nil
+	}
+
+	rssiOnly := !report.HasChanged("ManufacturerData", "ServiceData")
+	if rssiOnly && !report.HasChanged("RSSI") {
+		return nil
+	}
+
+	events := []*BeaconEvent{}
+
+	if rssiOnly || report.HasChanged("ManufacturerData") {
+		ids := []int{}
+		for id := range report.ManufacturerData {
+			ids = append(ids, int(id))
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			ev := s.parseManufacturerData(uint16(id), report.ManufacturerData[uint16(id)])
+			if ev != nil {
+				events = append(events, ev)
+			}
+		}
+	}
+
+	if rssiOnly || report.HasChanged("ServiceData") {
+		if data, ok := serviceDataFor(toServiceData(report.ServiceData), EddystoneServiceUUID); ok {
+			ev := s.parseEddystone(report.Address, data, report.Timestamp)
+			if ev != nil {
+				events = append(events, ev)
+			}
+		}
+	}
+
+	for _, ev := range events {
+		ev.Path = report.Path
+		ev.Address = report.Address
+		ev.RSSI = report.RSSI
+		ev.Timestamp = report.Timestamp
+		ev.Distance = -1
+		if ev.HasMeasuredPower {
+			ev.Distance = api.EstimateDistance(float64(report.RSSI), float64(ev.MeasuredPower), s.opts.PathLossExponent)
+		}
+	}
+
+	return events
+}
+
+func (s *Scanner) parseManufacturerData(id uint16, frames []byte) *BeaconEvent {
+
+	b := new(Beacon)
+
+	if id == appleBit && isIBeacon(frames) {
+		info := b.ParseIBeacon(frames)
+		return &BeaconEvent{
+			Type:             FrameIBeacon,
+			ID:               fmt.Sprintf("%s:%s:%d:%d", FrameIBeacon, info.ProximityUUID, info.Major, info.Minor),
+			MeasuredPower:    int(int8(info.MeasuredPower)),
+			HasMeasuredPower: true,
+			IBeacon:          &info,
+		}
+	}
+
+	if isAltBeacon(frames) {
+		info := parseAltBeacon(id, frames)
+		return &BeaconEvent{
+			Type:             FrameAltBeacon,
+			ID:               fmt.Sprintf("%s:%04x:%s", FrameAltBeacon, id, info.BeaconID),
+			MeasuredPower:    info.ReferenceRSSI,
+			HasMeasuredPower: true,
+			AltBeacon:        &info,
+		}
+	}
+
+	return nil
+}
+
+func (s *Scanner) parseEddystone(address string, frames []byte, now time.Time) *BeaconEvent {
+
+	b := new(Beacon)
+	info := b.ParseEddystone(frames)
+
+	ev := &BeaconEvent{
+		Eddystone: &info,
+	}
+
+	switch info.Frame {
+	case EddystoneFrameUID:
+		ev.Type = FrameEddystoneUID
+		ev.ID = fmt.Sprintf("eddystone:%s:%s", info.UID, info.InstanceUID)
+	case EddystoneFrameURL:
+		ev.Type = FrameEddystoneURL
+		ev.ID = fmt.Sprintf("eddystone:%s", info.URL)
+		// use the UID or EID identity when the beacon advertise one
+		if id, ok := s.identity(s.identities, address, now); ok {
+			ev.ID = id
+		}
+	case EddystoneFrameEID:
+		ev.Type = FrameEddystoneEID
+		ev.ID = fmt.Sprintf("eddystone:%s", info.EID)
+		if name, ok := s.resolveEID(info.EID, now); ok {
+			ev.ID = fmt.Sprintf("eddystone-eid:%s", name)
+			s.eidBeacons[address] = addressIdentity{name, now}
+		}
+	case EddystoneFrameTLM:
+		ev.Type = FrameEddystoneTLM
+		id, ok := s.identity(s.identities, address, now)
+		if !ok {
+			id = fmt.Sprintf("eddystone:%s", address)
+		}
+		ev.ID = id
+		if info.TLMVersion == etlmVersion {
+			s.decryptETLM(address, ev, now)
+		}
+		// TLM frames have no TX power
+		return ev
+	default:
+		return nil
+	}
+
+	s.identities[address] = addressIdentity{ev.ID, now}
+
+	ev.MeasuredPower = int(int8(info.CalibratedTxPower)) - eddystoneLossAt1m
+	ev.HasMeasuredPower = true
+
+	return ev
+}
+
+// identity return the identity of address if it has not expired at now
+func (s *Scanner) identity(identities map[string]addressIdentity, address string, now time.Time) (string, bool) {
+	identity, ok := identities[address]
+	if !ok || now.Sub(identity.Seen) > s.opts.IdentityTimeout {
+		return "", false
+	}
+	return identity.ID, true
+}
+
+// expireIdentities forget the identities not seen since IdentityTimeout
+func (s *Scanner) expireIdentities(now time.Time) {
+	for _, identities := range []map[string]addressIdentity{s.identities, s.eidBeacons} {
+		for address, identity := range identities {
+			if now.Sub(identity.Seen) > s.opts.IdentityTimeout {
+				delete(identities, address)
+			}
+		}
+	}
+}
+
+func (s *Scanner) resolveEID(eid string, now time.Time) (string, bool) {
+	if s.opts.EIDResolver == nil {
+		return "", false
+	}
+	b, err := hex.DecodeString(eid)
+	if err != nil {
+		return "", false
+	}
+	return s.opts.EIDResolver.Resolve(b, now)
+}
+
+// decryptETLM decrypt the TLM frame of a resolved EID beacon, the frame is
+// kept encrypted on failure
+func (s *Scanner) decryptETLM(address string, ev *BeaconEvent, now time.Time) {
+	name, ok := s.identity(s.eidBeacons, address, now)
+	if !ok || s.opts.EIDResolver == nil {
+		return
+	}
+	info, err := s.opts.EIDResolver.DecryptETLM(name, *ev.Eddystone, now)
+	if err != nil {
+		log.Debugf("Beacon scanner: %s", err)
+		return
+	}
+	ev.Eddystone = &info
+}
+
+func isIBeacon(frames []byte) bool {
+	return len(frames) >= 23 && frames[0] == 0x02 && frames[1] == 0x15
+}
+
+func toServiceData(serviceData map[string][]byte) map[string]interface{} {
+	res := make(map[string]interface{}, len(serviceData))
+	for uuid, data := range serviceData {
+		res[uuid] = data
+	}
+	return res
+}
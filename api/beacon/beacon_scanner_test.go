@@ -0,0 +1,251 @@
+package beacon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/api"
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	eddystone "github.com/suapapa/go_eddystone"
+)
+
+func testReport(address string, rssi int16, changed ...string) *api.AdvertisementReport {
+	return &api.AdvertisementReport{
+		Path:             dbus.ObjectPath("/org/bluez/hci0/dev_" + address),
+		Address:          address,
+		RSSI:             rssi,
+		ManufacturerData: map[uint16][]byte{},
+		ServiceData:      map[string][]byte{},
+		Changed:          changed,
+	}
+}
+
+func TestScannerIBeacon(t *testing.T) {
+
+	b, err := CreateIBeacon("AAAABBBBCCCCDDDDAAAABBBBCCCCDDDD", 1, 2, 0xC5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScanner(nil, ScannerOptions{})
+
+	report := testReport("00:11:22:33:44:55", -59, "ManufacturerData", "RSSI")
+	report.ManufacturerData[appleBit] = b.GetFrames()
+
+	events := s.handleReport(report)
+	assert.Equal(t, 1, len(events))
+	ev := events[0]
+	assert.Equal(t, FrameIBeacon, ev.Type)
+	assert.Equal(t, "ibeacon:AAAABBBBCCCCDDDDAAAABBBBCCCCDDDD:1:2", ev.ID)
+	assert.Equal(t, -59, ev.MeasuredPower)
+	assert.InDelta(t, 1, ev.Distance, 0.001)
+	assert.Equal(t, uint16(2), ev.IBeacon.Minor)
+
+	// RSSI updates emit the known frames
+	report.Changed = []string{"RSSI"}
+	report.RSSI = -79
+	events = s.handleReport(report)
+	assert.Equal(t, 1, len(events))
+	assert.InDelta(t, 10, events[0].Distance, 0.001)
+
+	// other changes are ignored
+	report.Changed = []string{"Name"}
+	assert.Equal(t, 0, len(s.handleReport(report)))
+
+	// truncated frames are ignored
+	report.Changed = []string{"ManufacturerData"}
+	report.ManufacturerData[appleBit] = b.GetFrames()[:10]
+	assert.Equal(t, 0, len(s.handleReport(report)))
+}
+
+func TestScannerAltBeacon(t *testing.T) {
+
+	frames := []byte{0xBE, 0xAC}
+	for i := 0; i < 20; i++ {
+		frames = append(frames, byte(i))
+	}
+	frames = append(frames, 0xC5, 0x01)
+
+	s := NewScanner(nil, ScannerOptions{})
+	report := testReport("00:11:22:33:44:55", -59, "ManufacturerData")
+	report.ManufacturerData[0x0118] = frames
+
+	events := s.handleReport(report)
+	assert.Equal(t, 1, len(events))
+	ev := events[0]
+	assert.Equal(t, FrameAltBeacon, ev.Type)
+	assert.Equal(t, "altbeacon:0118:000102030405060708090A0B0C0D0E0F10111213", ev.ID)
+	assert.Equal(t, "000102030405060708090A0B0C0D0E0F", ev.AltBeacon.ID1)
+	assert.Equal(t, uint16(0x1011), ev.AltBeacon.ID2)
+	assert.Equal(t, uint16(0x1213), ev.AltBeacon.ID3)
+	assert.Equal(t, -59, ev.AltBeacon.ReferenceRSSI)
+	assert.Equal(t, byte(1), ev.AltBeacon.Reserved)
+	assert.InDelta(t, 1, ev.Distance, 0.001)
+}
+
+func TestScannerEddystoneIdentity(t *testing.T) {
+
+	uid, err := eddystone.MakeUIDFrame("EDD1EBEAC04E5DEFA017", "0BDB87539B67", -18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlm, err := eddystone.MakeTLMFrame(3000, 21.5, 100, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err := eddystone.MakeURLFrame("https://example.com", -18)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScanner(nil, ScannerOptions{})
+
+	frame := func(address string, data []byte) *BeaconEvent {
+		report := testReport(address, -59, "ServiceData")
+		report.ServiceData["0000feaa-0000-1000-8000-00805f9b34fb"] = data
+		events := s.handleReport(report)
+		if len(events) != 1 {
+			t.Fatalf("expected one event, got %d", len(events))
+		}
+		return events[0]
+	}
+
+	// TLM before any UID use the address
+	ev := frame("00:11:22:33:44:55", tlm)
+	assert.Equal(t, FrameEddystoneTLM, ev.Type)
+	assert.Equal(t, "eddystone:00:11:22:33:44:55", ev.ID)
+	assert.Equal(t, -1.0, ev.Distance)
+
+	ev = frame("00:11:22:33:44:55", uid)
+	assert.Equal(t, FrameEddystoneUID, ev.Type)
+	identity := ev.ID
+	assert.Equal(t, "eddystone:EDD1EBEAC04E5DEFA017:0BDB87539B67", identity)
+	assert.Equal(t, -59, ev.MeasuredPower)
+	assert.InDelta(t, 1, ev.Distance, 0.001)
+
+	ev = frame("00:11:22:33:44:55", tlm)
+	assert.Equal(t, identity, ev.ID)
+	assert.Equal(t, uint16(3000), ev.Eddystone.TLMBatteryVoltage)
+
+	ev = frame("00:11:22:33:44:55", url)
+	assert.Equal(t, FrameEddystoneURL, ev.Type)
+	assert.Equal(t, identity, ev.ID)
+
+	// the beacon rotates its address, the path changes
+	s.handleReport(&api.AdvertisementReport{Address: "00:11:22:33:44:55", Removed: true})
+	ev = frame("00:11:22:33:44:66", uid)
+	assert.Equal(t, identity, ev.ID)
+	ev = frame("00:11:22:33:44:66", tlm)
+	assert.Equal(t, identity, ev.ID)
+}
+
+func TestScannerEddystoneIdentityRemoved(t *testing.T) {
+
+	uid, err := eddystone.MakeUIDFrame("EDD1EBEAC04E5DEFA017", "0BDB87539B67", -18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlm, err := eddystone.MakeTLMFrame(3000, 21.5, 100, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScanner(nil, ScannerOptions{IdentityTimeout: time.Minute})
+	now := time.Now()
+
+	frame := func(data []byte, ts time.Time) *BeaconEvent {
+		report := testReport("00:11:22:33:44:55", -59, "ServiceData")
+		report.Timestamp = ts
+		report.ServiceData["feaa"] = data
+		events := s.handleReport(report)
+		if len(events) != 1 {
+			t.Fatalf("expected one event, got %d", len(events))
+		}
+		return events[0]
+	}
+	removed := func(ts time.Time) {
+		s.handleReport(&api.AdvertisementReport{Address: "00:11:22:33:44:55", Removed: true, Timestamp: ts})
+	}
+
+	identity := frame(uid, now).ID
+
+	// Bluez removed the device, the TLM frame still get the UID identity
+	removed(now.Add(time.Second))
+	ev := frame(tlm, now.Add(2*time.Second))
+	assert.Equal(t, FrameEddystoneTLM, ev.Type)
+	assert.Equal(t, identity, ev.ID)
+
+	// the identity expires
+	ev = frame(tlm, now.Add(2*time.Minute))
+	assert.Equal(t, "eddystone:00:11:22:33:44:55", ev.ID)
+	removed(now.Add(2 * time.Minute))
+	assert.Len(t, s.identities, 0)
+}
+
+func TestScannerEddystoneEID(t *testing.T) {
+
+	s := NewScanner(nil, ScannerOptions{})
+	report := testReport("00:11:22:33:44:55", -59, "ServiceData")
+	report.ServiceData["feaa"] = []byte{0x30, 0xEE, 1, 2, 3, 4, 5, 6, 7, 8}
+
+	events := s.handleReport(report)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, FrameEddystoneEID, events[0].Type)
+	assert.Equal(t, "eddystone:0102030405060708", events[0].ID)
+	assert.Equal(t, -18, events[0].Eddystone.CalibratedTxPower)
+
+	// short or unknown frames are ignored
+	report.ServiceData["feaa"] = []byte{0x30, 0xEE, 1}
+	assert.Equal(t, 0, len(s.handleReport(report)))
+	report.ServiceData["feaa"] = []byte{}
+	assert.Equal(t, 0, len(s.handleReport(report)))
+	report.ServiceData["feaa"] = []byte{0x40, 0x00}
+	assert.Equal(t, 0, len(s.handleReport(report)))
+}
+
+func TestScannerEIDResolver(t *testing.T) {
+
+	now := time.Now()
+
+	r := NewEIDResolver()
+	err := r.Register("beacon1", testIdentityKey, 10, 5000, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eid, err := MakeEIDFrame(testIdentityKey, 10, 5000, -18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	etlm, err := MakeETLMFrame(testIdentityKey, 10, 5000, 0x0001, 3000, 21.5, 100, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScanner(nil, ScannerOptions{EIDResolver: r})
+
+	frame := func(data []byte) *BeaconEvent {
+		report := testReport("00:11:22:33:44:55", -59, "ServiceData")
+		report.Timestamp = now
+		report.ServiceData["feaa"] = data
+		events := s.handleReport(report)
+		if len(events) != 1 {
+			t.Fatalf("expected one event, got %d", len(events))
+		}
+		return events[0]
+	}
+
+	// encrypted TLM are not decrypted before the EID is resolved
+	ev := frame(etlm)
+	assert.Equal(t, uint16(0), ev.Eddystone.TLMBatteryVoltage)
+
+	ev = frame(eid)
+	assert.Equal(t, FrameEddystoneEID, ev.Type)
+	assert.Equal(t, "eddystone-eid:beacon1", ev.ID)
+
+	ev = frame(etlm)
+	assert.Equal(t, FrameEddystoneTLM, ev.Type)
+	assert.Equal(t, "eddystone-eid:beacon1", ev.ID)
+	assert.Equal(t, uint16(3000), ev.Eddystone.TLMBatteryVoltage)
+}
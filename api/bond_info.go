@@ -0,0 +1,111 @@
+package api
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultBluetoothStorageDir is the Bluez storage directory, bonds are stored
+// in <dir>/<adapter address>/<device address>/info
+const DefaultBluetoothStorageDir = "/var/lib/bluetooth"
+
+// BondInfo is the content of the info file of a bonded device
+type BondInfo struct {
+	Name        string
+	AddressType string
+	Trusted     bool
+	Blocked     bool
+	// HasLinkKey is true for a BR/EDR link key
+	HasLinkKey bool
+	// HasLTK is true for a LE long term key, as central or peripheral
+	HasLTK bool
+	// HasIRK is true when the device identity resolving key is known
+	HasIRK bool
+	// ModTime is the modification time of the info file
+	ModTime time.Time
+}
+
+// ReadBondInfo read the info file of a device from the Bluez storage directory
+func ReadBondInfo(storageDir string, adapterAddress string, deviceAddress string) (*BondInfo, error) {
+
+	filename := filepath.Join(storageDir, strings.ToUpper(adapterAddress), strings.ToUpper(deviceAddress), "info")
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := ParseBondInfo(f)
+	if err != nil {
+		return nil, err
+	}
+	info.ModTime = stat.ModTime()
+
+	return info, nil
+}
+
+// ParseBondInfo parse a Bluez device info file, keys are not returned but
+// only their presence
+func ParseBondInfo(r io.Reader) (*BondInfo, error) {
+
+	info := new(BondInfo)
+	section := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+
+		switch section {
+		case "General":
+			switch key {
+			case "Name":
+				info.Name = value
+			case "AddressType":
+				info.AddressType = value
+			case "Trusted":
+				info.Trusted = value == "true"
+			case "Blocked":
+				info.Blocked = value == "true"
+			}
+		case "LinkKey":
+			if key == "Key" && value != "" {
+				info.HasLinkKey = true
+			}
+		case "LongTermKey", "SlaveLongTermKey", "PeripheralLongTermKey":
+			if key == "Key" && value != "" {
+				info.HasLTK = true
+			}
+		case "IdentityResolvingKey":
+			if key == "Key" && value != "" {
+				info.HasIRK = true
+			}
+		}
+	}
+
+	return info, scanner.Err()
+}
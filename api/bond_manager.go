@@ -0,0 +1,412 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/adapter"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+	"github.com/godbus/dbus/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+// Bond describe a paired device
+type Bond struct {
+	Path        dbus.ObjectPath
+	Address     string
+	AddressType string
+	Name        string
+	Trusted     bool
+	Blocked     bool
+	Connected   bool
+	// Info is the content of the Bluez info file, nil when not accessible
+	Info *BondInfo
+}
+
+// HasLTK return true if a LE long term key is stored, false if unknown
+func (b *Bond) HasLTK() bool {
+	return b.Info != nil && b.Info.HasLTK
+}
+
+// HasIRK return true if the identity resolving key is stored, false if unknown
+func (b *Bond) HasIRK() bool {
+	return b.Info != nil && b.Info.HasIRK
+}
+
+// Age return the time since the info file was last written. Bluez writes it
+// on pairing and on changes of the stored device properties, so this is the
+// time since the bond was last updated. False if the info file is not accessible
+func (b *Bond) Age(now time.Time) (time.Duration, bool) {
+	if b.Info == nil || b.Info.ModTime.IsZero() {
+		return 0, false
+	}
+	return now.Sub(b.Info.ModTime), true
+}
+
+// BondEventType is the type of a bond change
+type BondEventType int
+
+const (
+	// BondPaired a device has been paired
+	BondPaired BondEventType = iota
+	// BondUnpaired a device is not paired anymore or has been removed
+	BondUnpaired
+)
+
+func (t BondEventType) String() string {
+	switch t {
+	case BondPaired:
+		return "paired"
+	case BondUnpaired:
+		return "unpaired"
+	}
+	return "unknown"
+}
+
+// BondEvent is emitted when the Paired property of a device change
+type BondEvent struct {
+	Type    BondEventType
+	Path    dbus.ObjectPath
+	Address string
+}
+
+// NewBondManager create a bond manager for the adapter
+func NewBondManager(a *adapter.Adapter1) *BondManager {
+	return &BondManager{
+		adapter:    a,
+		StorageDir: DefaultBluetoothStorageDir,
+	}
+}
+
+// BondManager list and maintain the bonds of an adapter
+type BondManager struct {
+	adapter *adapter.Adapter1
+	// StorageDir is the Bluez storage directory, see DefaultBluetoothStorageDir
+	StorageDir string
+}
+
+// List return the paired devices sorted by address. Key presence and bond
+// time are read from the Bluez storage when it is accessible
+func (m *BondManager) List() ([]Bond, error) {
+
+	devices, err := m.adapter.GetDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	adapterAddress, err := m.adapter.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	bonds := []Bond{}
+	for _, dev := range devices {
+
+		if !dev.Properties.Paired {
+			continue
+		}
+
+		bond := Bond{
+			Path:        dev.Path(),
+			Address:     dev.Properties.Address,
+			AddressType: dev.Properties.AddressType,
+			Name:        dev.Properties.Name,
+			Trusted:     dev.Properties.Trusted,
+			Blocked:     dev.Properties.Blocked,
+			Connected:   dev.Properties.Connected,
+		}
+
+		info, err := ReadBondInfo(m.StorageDir, adapterAddress, bond.Address)
+		if err != nil {
+			log.Debugf("BondManager: %s", err)
+		} else {
+			bond.Info = info
+		}
+
+		bonds = append(bonds, bond)
+	}
+
+	sort.Slice(bonds, func(i, j int) bool {
+		return bonds[i].Address < bonds[j].Address
+	})
+
+	return bonds, nil
+}
+
+// SetTrusted set or unset the trust of the devices, all the devices are
+// updated and the failures reported in the returned error
+func (m *BondManager) SetTrusted(trusted bool, addresses ...string) error {
+	return m.update(addresses, func(dev *device.Device1) error {
+		return dev.SetTrusted(trusted)
+	})
+}
+
+// SetBlocked block or unblock the devices, all the devices are updated and
+// the failures reported in the returned error
+func (m *BondManager) SetBlocked(blocked bool, addresses ...string) error {
+	return m.update(addresses, func(dev *device.Device1) error {
+		return dev.SetBlocked(blocked)
+	})
+}
+
+func (m *BondManager) update(addresses []string, fn func(dev *device.Device1) error) error {
+
+	devices, err := m.adapter.GetDevices()
+	if err != nil {
+		return err
+	}
+
+	byAddress := make(map[string]*device.Device1)
+	for _, dev := range devices {
+		byAddress[strings.ToUpper(dev.Properties.Address)] = dev
+	}
+
+	failures := []string{}
+	for _, address := range addresses {
+		dev, ok := byAddress[strings.ToUpper(address)]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s: not found", address))
+			continue
+		}
+		err := fn(dev)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", address, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("BondManager: %s", strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+// PruneOlderThan remove the bonds not updated since days, see Bond.Age.
+// Connected devices and bonds with an unknown age are kept. Return the
+// removed bonds, an error if no bond age could be read
+func (m *BondManager) PruneOlderThan(days int) ([]Bond, error) {
+
+	if days < 1 {
+		return nil, fmt.Errorf("BondManager: days must be at least 1, got %d", days)
+	}
+
+	bonds, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bonds) > 0 && !hasBondAge(bonds) {
+		return nil, fmt.Errorf("BondManager: bond age unknown, %s is not readable", m.StorageDir)
+	}
+
+	removed := []Bond{}
+	failures := []string{}
+	for _, bond := range expiredBonds(bonds, time.Duration(days)*24*time.Hour, time.Now()) {
+		err := m.adapter.RemoveDevice(bond.Path)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", bond.Address, err))
+			continue
+		}
+		log.Debugf("BondManager: removed %s", bond.Address)
+		removed = append(removed, bond)
+	}
+
+	if len(failures) > 0 {
+		return removed, fmt.Errorf("BondManager: %s", strings.Join(failures, ", "))
+	}
+	return removed, nil
+}
+
+func hasBondAge(bonds []Bond) bool {
+	for _, bond := range bonds {
+		if _, ok := bond.Age(time.Now()); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func expiredBonds(bonds []Bond, maxAge time.Duration, now time.Time) []Bond {
+	expired := []Bond{}
+	for _, bond := range bonds {
+		if bond.Connected {
+			continue
+		}
+		age, ok := bond.Age(now)
+		if ok && age > maxAge {
+			expired = append(expired, bond)
+		}
+	}
+	return expired
+}
+
+// Watch return a channel receiving an event when a device of the adapter is
+// paired or unpaired. No event is lost, the events not yet received are
+// queued. Use cancel to stop watching, the channel is then closed
+func (m *BondManager) Watch() (chan *BondEvent, func(), error) {
+
+	client := m.adapter.Client()
+	// the subscription is lossless, a coalesced or dropped signal would hide
+	// an unpairing. The reader never blocks on the events channel
+	signal, err := client.RegisterWithOptions(
+		bluez.SubscribeOptions{
+			Overflow: bluez.OverflowBlock,
+		},
+		bluez.SignalFilter{
+			Path:      "/",
+			Interface: bluez.ObjectManagerInterface,
+		},
+		bluez.SignalFilter{
+			PathNamespace: m.adapter.Path(),
+			Interface:     bluez.PropertiesInterface,
+			Member:        "PropertiesChanged",
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w := newBondWatcher(m.adapter.Path())
+
+	devices, err := m.adapter.GetDevices()
+	if err != nil {
+		client.Unregister(m.adapter.Path(), bluez.PropertiesInterface, signal)
+		return nil, nil, err
+	}
+	for _, dev := range devices {
+		w.paired[dev.Path()] = dev.Properties.Paired
+	}
+
+	events := make(chan *BondEvent)
+	quit := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer close(events)
+		w.run(signal, events, quit)
+	}()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			close(quit)
+			<-done
+			client.Unregister(m.adapter.Path(), bluez.PropertiesInterface, signal)
+		})
+	}
+
+	return events, cancel, nil
+}
+
+func newBondWatcher(adapterPath dbus.ObjectPath) *bondWatcher {
+	return &bondWatcher{
+		adapterPath: adapterPath,
+		paired:      make(map[dbus.ObjectPath]bool),
+	}
+}
+
+// bondWatcher track the Paired property of the adapter devices
+type bondWatcher struct {
+	adapterPath dbus.ObjectPath
+	paired      map[dbus.ObjectPath]bool
+}
+
+// run send the events of the signals until quit is closed. The events are
+// queued while the receiver is busy, so the signals are always consumed
+func (w *bondWatcher) run(signal chan *dbus.Signal, events chan *BondEvent, quit chan struct{}) {
+
+	pending := []*BondEvent{}
+	for {
+		var out chan *BondEvent
+		var next *BondEvent
+		if len(pending) > 0 {
+			out = events
+			next = pending[0]
+		}
+
+		select {
+		case sig := <-signal:
+			if sig == nil {
+				return
+			}
+			if ev := w.handleSignal(sig); ev != nil {
+				pending = append(pending, ev)
+			}
+		case out <- next:
+			pending = pending[1:]
+		case <-quit:
+			return
+		}
+	}
+}
+
+// handleSignal return an event if the signal change the paired state of a device
+func (w *bondWatcher) handleSignal(sig *dbus.Signal) *BondEvent {
+
+	if len(sig.Body) < 2 {
+		return nil
+	}
+
+	var path dbus.ObjectPath
+	var props map[string]dbus.Variant
+
+	switch sig.Name {
+	case bluez.InterfacesAdded:
+		path, _ = sig.Body[0].(dbus.ObjectPath)
+		ifaces, _ := sig.Body[1].(map[string]map[string]dbus.Variant)
+		props = ifaces[device.Device1Interface]
+	case bluez.InterfacesRemoved:
+		path, _ = sig.Body[0].(dbus.ObjectPath)
+		ifaces, _ := sig.Body[1].([]string)
+		for _, iface := range ifaces {
+			if iface == device.Device1Interface {
+				paired := w.paired[path]
+				delete(w.paired, path)
+				if paired {
+					return newBondEvent(BondUnpaired, path)
+				}
+			}
+		}
+		return nil
+	case bluez.PropertiesChanged:
+		if iface, _ := sig.Body[0].(string); iface != device.Device1Interface {
+			return nil
+		}
+		path = sig.Path
+		props, _ = sig.Body[1].(map[string]dbus.Variant)
+	default:
+		return nil
+	}
+
+	if props == nil || !strings.HasPrefix(string(path), string(w.adapterPath)+"/") {
+		return nil
+	}
+
+	v, ok := props["Paired"]
+	if !ok {
+		return nil
+	}
+	paired, _ := v.Value().(bool)
+	if paired == w.paired[path] {
+		return nil
+	}
+	w.paired[path] = paired
+
+	if paired {
+		return newBondEvent(BondPaired, path)
+	}
+	return newBondEvent(BondUnpaired, path)
+}
+
+func newBondEvent(t BondEventType, path dbus.ObjectPath) *BondEvent {
+	address, _ := device.ParseDeviceAddress(path)
+	return &BondEvent{
+		Type:    t,
+		Path:    path,
+		Address: address,
+	}
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBondInfo = `[General]
+Name=Keyboard
+AddressType=static
+SupportedTechnologies=LE;
+Trusted=true
+Blocked=false
+
+[IdentityResolvingKey]
+Key=00112233445566778899AABBCCDDEEFF
+
+[PeripheralLongTermKey]
+Key=FFEEDDCCBBAA99887766554433221100
+Authenticated=0
+EncSize=16
+EDiv=0
+Rand=0
+`
+
+func TestParseBondInfo(t *testing.T) {
+
+	info, err := ParseBondInfo(strings.NewReader(testBondInfo))
+	assert.NoError(t, err)
+	assert.Equal(t, "Keyboard", info.Name)
+	assert.Equal(t, "static", info.AddressType)
+	assert.True(t, info.Trusted)
+	assert.False(t, info.Blocked)
+	assert.True(t, info.HasIRK)
+	assert.True(t, info.HasLTK)
+	assert.False(t, info.HasLinkKey)
+
+	info, err = ParseBondInfo(strings.NewReader("[General]\nName=Speaker\n\n[LinkKey]\nKey=0011\nType=4\n"))
+	assert.NoError(t, err)
+	assert.True(t, info.HasLinkKey)
+	assert.False(t, info.HasLTK)
+	assert.False(t, info.HasIRK)
+}
+
+func TestReadBondInfo(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "bluetooth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	deviceDir := filepath.Join(dir, "00:AA:BB:CC:DD:EE", "00:11:22:33:44:55")
+	err = os.MkdirAll(deviceDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(deviceDir, "info"), []byte(testBondInfo), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ReadBondInfo(dir, "00:aa:bb:cc:dd:ee", "00:11:22:33:44:55")
+	assert.NoError(t, err)
+	assert.True(t, info.HasLTK)
+	assert.False(t, info.ModTime.IsZero())
+
+	_, err = ReadBondInfo(dir, "00:aa:bb:cc:dd:ee", "00:11:22:33:44:66")
+	assert.Error(t, err)
+}
+
+func TestExpiredBonds(t *testing.T) {
+
+	now := time.Now()
+	day := 24 * time.Hour
+
+	bonds := []Bond{
+		{Address: "00:00:00:00:00:01", Info: &BondInfo{ModTime: now.Add(-40 * day)}},
+		{Address: "00:00:00:00:00:02", Info: &BondInfo{ModTime: now.Add(-10 * day)}},
+		{Address: "00:00:00:00:00:03", Info: &BondInfo{ModTime: now.Add(-40 * day)}, Connected: true},
+		{Address: "00:00:00:00:00:04"},
+	}
+
+	expired := expiredBonds(bonds, 30*day, now)
+	assert.Equal(t, 1, len(expired))
+	assert.Equal(t, "00:00:00:00:00:01", expired[0].Address)
+
+	_, ok := bonds[3].Age(now)
+	assert.False(t, ok)
+	assert.False(t, bonds[3].HasLTK())
+}
+
+func TestPruneOlderThanDays(t *testing.T) {
+	m := NewBondManager(nil)
+	_, err := m.PruneOlderThan(0)
+	assert.Error(t, err)
+
+	assert.True(t, hasBondAge([]Bond{{}, {Info: &BondInfo{ModTime: time.Now()}}}))
+	assert.False(t, hasBondAge([]Bond{{}, {Info: &BondInfo{}}}))
+}
+
+func TestBondWatcher(t *testing.T) {
+
+	w := newBondWatcher("/org/bluez/hci0")
+	path := dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55")
+
+	changed := func(paired bool) *dbus.Signal {
+		return &dbus.Signal{
+			Path: path,
+			Name: bluez.PropertiesChanged,
+			Body: []interface{}{
+				"org.bluez.Device1",
+				map[string]dbus.Variant{"Paired": dbus.MakeVariant(paired)},
+				[]string{},
+			},
+		}
+	}
+
+	// added not paired
+	assert.Nil(t, w.handleSignal(&dbus.Signal{
+		Name: bluez.InterfacesAdded,
+		Body: []interface{}{
+			path,
+			map[string]map[string]dbus.Variant{
+				"org.bluez.Device1": {"Paired": dbus.MakeVariant(false)},
+			},
+		},
+	}))
+
+	ev := w.handleSignal(changed(true))
+	assert.NotNil(t, ev)
+	assert.Equal(t, BondPaired, ev.Type)
+	assert.Equal(t, "00:11:22:33:44:55", ev.Address)
+
+	// no change
+	assert.Nil(t, w.handleSignal(changed(true)))
+
+	// other adapter
+	other := changed(false)
+	other.Path = "/org/bluez/hci1/dev_00_11_22_33_44_55"
+	assert.Nil(t, w.handleSignal(other))
+
+	ev = w.handleSignal(&dbus.Signal{
+		Name: bluez.InterfacesRemoved,
+		Body: []interface{}{path, []string{"org.bluez.Device1"}},
+	})
+	assert.NotNil(t, ev)
+	assert.Equal(t, BondUnpaired, ev.Type)
+	assert.Equal(t, "unpaired", ev.Type.String())
+}
+
+func TestBondWatcherRun(t *testing.T) {
+
+	w := newBondWatcher("/org/bluez/hci0")
+	signal := make(chan *dbus.Signal)
+	events := make(chan *BondEvent)
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		w.run(signal, events, quit)
+		close(done)
+	}()
+
+	// the signals are consumed while nobody receives the events
+	count := bluez.DefaultSignalBufferSize * 2
+	for i := 0; i < count; i++ {
+		path := dbus.ObjectPath(fmt.Sprintf("/org/bluez/hci0/dev_00_11_22_33_%02X_%02X", i/256, i%256))
+		signal <- &dbus.Signal{
+			Path: path,
+			Name: bluez.PropertiesChanged,
+			Body: []interface{}{
+				"org.bluez.Device1",
+				map[string]dbus.Variant{"Paired": dbus.MakeVariant(true)},
+				[]string{},
+			},
+		}
+	}
+	signal <- &dbus.Signal{
+		Name: bluez.InterfacesRemoved,
+		Body: []interface{}{dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_00_00"), []string{"org.bluez.Device1"}},
+	}
+
+	for i := 0; i < count; i++ {
+		assert.Equal(t, BondPaired, (<-events).Type)
+	}
+	ev := <-events
+	assert.Equal(t, BondUnpaired, ev.Type)
+	assert.Equal(t, "00:11:22:33:00:00", ev.Address)
+
+	close(quit)
+	<-done
+}
@@ -0,0 +1,504 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConnectionState is the state of a managed connection
+type ConnectionState int
+
+const (
+	// StateDisconnected the device is not connected
+	StateDisconnected ConnectionState = iota
+	// StateConnecting a connection attempt is running
+	StateConnecting
+	// StateConnected the link is up, services are not resolved yet
+	StateConnected
+	// StateReady the services are resolved and the device can be used
+	StateReady
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case StateDisconnected:
+		return "disconnected"
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateReady:
+		return "ready"
+	}
+	return "unknown"
+}
+
+// ConnectionOptions configure a ConnectionManager
+type ConnectionOptions struct {
+	// MaxRetries is the number of attempts of Connect, DefaultConnectRetries if zero
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry, doubled at each attempt
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum wait between attempts
+	MaxBackoff time.Duration
+	// ServicesResolvedTimeout is the maximum wait for ServicesResolved after connecting
+	ServicesResolvedTimeout time.Duration
+	// AutoReconnect reconnect after a link loss until Disconnect or Close is called
+	AutoReconnect bool
+	// OnStateChange is called on each state transition
+	OnStateChange func(state ConnectionState)
+}
+
+// Defaults of ConnectionOptions
+const (
+	DefaultConnectRetries          = 5
+	DefaultConnectInitialBackoff   = 500 * time.Millisecond
+	DefaultConnectMaxBackoff       = 30 * time.Second
+	DefaultServicesResolvedTimeout = 30 * time.Second
+)
+
+// NotifyCharacteristic is a characteristic whose notifications are
+// enabled again after a reconnection, eg. *gatt.GattCharacteristic1
+type NotifyCharacteristic interface {
+	Path() dbus.ObjectPath
+	StartNotify() error
+	StopNotify() error
+}
+
+// connectableDevice is the part of device.Device1 used by the ConnectionManager
+type connectableDevice interface {
+	Path() dbus.ObjectPath
+	ConnectContext(ctx context.Context) error
+	Disconnect() error
+	GetConnected() (bool, error)
+	GetServicesResolved() (bool, error)
+	WatchProperties() (chan *bluez.PropertyChanged, error)
+	UnwatchProperties(ch chan *bluez.PropertyChanged) error
+}
+
+// NewConnectionManager create a connection manager for the device
+func NewConnectionManager(dev *device.Device1, opts ConnectionOptions) *ConnectionManager {
+	return newConnectionManager(dev, opts)
+}
+
+func newConnectionManager(dev connectableDevice, opts ConnectionOptions) *ConnectionManager {
+
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultConnectRetries
+	}
+	if opts.InitialBackoff == 0 {
+		opts.InitialBackoff = DefaultConnectInitialBackoff
+	}
+	if opts.MaxBackoff == 0 {
+		opts.MaxBackoff = DefaultConnectMaxBackoff
+	}
+	if opts.ServicesResolvedTimeout == 0 {
+		opts.ServicesResolvedTimeout = DefaultServicesResolvedTimeout
+	}
+
+	return &ConnectionManager{
+		dev:      dev,
+		opts:     opts,
+		changed:  make(chan struct{}),
+		watchers: make(map[chan ConnectionState]bool),
+		notify:   make(map[dbus.ObjectPath]NotifyCharacteristic),
+	}
+}
+
+// ConnectionManager connect a device with retries, wait for its services
+// and reconnect after a link loss
+type ConnectionManager struct {
+	dev  connectableDevice
+	opts ConnectionOptions
+
+	lock      sync.Mutex
+	state     ConnectionState
+	connected bool
+	resolved  bool
+	// changed is closed and replaced when connected or resolved change
+	changed chan struct{}
+
+	props  chan *bluez.PropertyChanged
+	cancel context.CancelFunc
+	closed bool
+
+	watchers map[chan ConnectionState]bool
+	notify   map[dbus.ObjectPath]NotifyCharacteristic
+}
+
+// State return the current state
+func (m *ConnectionManager) State() ConnectionState {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.state
+}
+
+// WatchState return a channel receiving the state transitions.
+// Transitions are dropped if the channel is not consumed
+func (m *ConnectionManager) WatchState() (chan ConnectionState, func()) {
+
+	ch := make(chan ConnectionState, 10)
+
+	m.lock.Lock()
+	m.watchers[ch] = true
+	m.lock.Unlock()
+
+	cancel := func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		if m.watchers[ch] {
+			delete(m.watchers, ch)
+			close(ch)
+		}
+	}
+
+	return ch, cancel
+}
+
+func (m *ConnectionManager) setState(state ConnectionState) {
+
+	m.lock.Lock()
+	if m.state == state {
+		m.lock.Unlock()
+		return
+	}
+	m.state = state
+	for ch := range m.watchers {
+		select {
+		case ch <- state:
+		default:
+			log.Warnf("ConnectionManager %s: state watcher queue full", m.dev.Path())
+		}
+	}
+	m.lock.Unlock()
+
+	log.Debugf("ConnectionManager %s: %s", m.dev.Path(), state)
+
+	if m.opts.OnStateChange != nil {
+		m.opts.OnStateChange(state)
+	}
+}
+
+// Connect the device and wait for its services to be resolved.
+// Failed and InProgress errors are retried with an exponential backoff
+func (m *ConnectionManager) Connect(ctx context.Context) error {
+
+	m.lock.Lock()
+	if m.closed {
+		m.lock.Unlock()
+		return errors.New("Connection manager closed")
+	}
+	if m.cancel != nil {
+		// stop a pending reconnection, it is replaced by this call
+		m.cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+	m.lock.Unlock()
+
+	err := m.watch()
+	if err != nil {
+		return err
+	}
+
+	return m.connect(ctx, m.opts.MaxRetries)
+}
+
+// connect try to connect up to retries times, forever if retries is negative
+func (m *ConnectionManager) connect(ctx context.Context, retries int) error {
+
+	var err error
+	for attempt := 0; retries < 0 || attempt < retries; attempt++ {
+
+		if attempt > 0 {
+			select {
+			case <-time.After(m.backoff(attempt - 1)):
+			case <-ctx.Done():
+				m.setState(StateDisconnected)
+				return ctx.Err()
+			}
+		}
+
+		m.setState(StateConnecting)
+
+		err = m.dev.ConnectContext(ctx)
+		if err != nil && !errors.Is(err, bluez.ErrAlreadyConnected) {
+			if ctx.Err() != nil {
+				m.setState(StateDisconnected)
+				return ctx.Err()
+			}
+			if errors.Is(err, bluez.ErrFailed) || errors.Is(err, bluez.ErrInProgress) {
+				log.Debugf("ConnectionManager %s: attempt %d failed: %s", m.dev.Path(), attempt+1, err)
+				continue
+			}
+			m.setState(StateDisconnected)
+			return err
+		}
+
+		m.setConnected(true)
+		m.setState(StateConnected)
+
+		err = m.waitServicesResolved(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				m.setState(StateDisconnected)
+				return ctx.Err()
+			}
+			log.Debugf("ConnectionManager %s: attempt %d failed: %s", m.dev.Path(), attempt+1, err)
+			continue
+		}
+
+		m.setState(StateReady)
+		m.resubscribe()
+		return nil
+	}
+
+	m.setState(StateDisconnected)
+	return fmt.Errorf("Connect: %w", err)
+}
+
+func (m *ConnectionManager) backoff(attempt int) time.Duration {
+	wait := m.opts.InitialBackoff
+	for i := 0; i < attempt && wait < m.opts.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > m.opts.MaxBackoff {
+		wait = m.opts.MaxBackoff
+	}
+	return wait
+}
+
+func (m *ConnectionManager) waitServicesResolved(ctx context.Context) error {
+
+	resolved, err := m.dev.GetServicesResolved()
+	if err != nil {
+		return err
+	}
+	if resolved {
+		m.setResolved(true)
+		return nil
+	}
+
+	timeout := time.After(m.opts.ServicesResolvedTimeout)
+	for {
+		m.lock.Lock()
+		resolved, connected, changed := m.resolved, m.connected, m.changed
+		m.lock.Unlock()
+
+		if resolved {
+			return nil
+		}
+		if !connected {
+			return errors.New("Disconnected while resolving services")
+		}
+
+		select {
+		case <-changed:
+		case <-timeout:
+			return errors.New("Timeout waiting for ServicesResolved")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (m *ConnectionManager) setConnected(connected bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.connected = connected
+	if !connected {
+		m.resolved = false
+	}
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
+
+func (m *ConnectionManager) setResolved(resolved bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.resolved = resolved
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
+
+// watch start monitoring the Connected and ServicesResolved properties
+func (m *ConnectionManager) watch() error {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.props != nil {
+		return nil
+	}
+
+	props, err := m.dev.WatchProperties()
+	if err != nil {
+		return err
+	}
+	m.props = props
+
+	go func() {
+		for prop := range props {
+			if prop == nil {
+				return
+			}
+			m.handleProperty(prop)
+		}
+	}()
+
+	return nil
+}
+
+func (m *ConnectionManager) handleProperty(prop *bluez.PropertyChanged) {
+
+	switch prop.Name {
+	case "ServicesResolved":
+		resolved, _ := prop.Value.(bool)
+		m.setResolved(resolved)
+
+	case "Connected":
+		connected, _ := prop.Value.(bool)
+		m.setConnected(connected)
+		if connected {
+			return
+		}
+
+		m.lock.Lock()
+		wasUp := m.state == StateConnected || m.state == StateReady
+		reconnect := wasUp && m.opts.AutoReconnect && !m.closed && m.cancel != nil
+		m.lock.Unlock()
+
+		if !wasUp {
+			return
+		}
+
+		m.setState(StateDisconnected)
+		if reconnect {
+			go m.reconnect()
+		}
+	}
+}
+
+// reconnect after a link loss until success, Disconnect or Close
+func (m *ConnectionManager) reconnect() {
+
+	m.lock.Lock()
+	if m.closed || m.cancel == nil {
+		m.lock.Unlock()
+		return
+	}
+	m.cancel()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.lock.Unlock()
+
+	log.Debugf("ConnectionManager %s: link lost, reconnecting", m.dev.Path())
+
+	err := m.connect(ctx, -1)
+	if err != nil && ctx.Err() == nil {
+		log.Warnf("ConnectionManager %s: reconnect: %s", m.dev.Path(), err)
+	}
+}
+
+// EnableNotify start the notifications of a characteristic and enable them
+// again after each reconnection
+func (m *ConnectionManager) EnableNotify(char NotifyCharacteristic) error {
+
+	m.lock.Lock()
+	m.notify[char.Path()] = char
+	ready := m.state == StateReady
+	m.lock.Unlock()
+
+	if !ready {
+		return nil
+	}
+
+	return char.StartNotify()
+}
+
+// DisableNotify stop the notifications of a characteristic
+func (m *ConnectionManager) DisableNotify(char NotifyCharacteristic) error {
+
+	m.lock.Lock()
+	delete(m.notify, char.Path())
+	ready := m.state == StateReady
+	m.lock.Unlock()
+
+	if !ready {
+		return nil
+	}
+
+	return char.StopNotify()
+}
+
+func (m *ConnectionManager) resubscribe() {
+
+	m.lock.Lock()
+	chars := []NotifyCharacteristic{}
+	for _, char := range m.notify {
+		chars = append(chars, char)
+	}
+	m.lock.Unlock()
+
+	for _, char := range chars {
+		err := char.StartNotify()
+		if err != nil {
+			log.Warnf("ConnectionManager %s: StartNotify %s: %s", m.dev.Path(), char.Path(), err)
+		}
+	}
+}
+
+// Disconnect stop reconnecting and disconnect the device
+func (m *ConnectionManager) Disconnect() error {
+
+	m.lock.Lock()
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.lock.Unlock()
+
+	err := m.dev.Disconnect()
+	m.setState(StateDisconnected)
+
+	if err != nil && !errors.Is(err, bluez.ErrNotConnected) {
+		return err
+	}
+	return nil
+}
+
+// Close stop monitoring the device and close the state channels.
+// The device is not disconnected
+func (m *ConnectionManager) Close() error {
+
+	m.lock.Lock()
+	if m.closed {
+		m.lock.Unlock()
+		return nil
+	}
+	m.closed = true
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	props := m.props
+	m.props = nil
+	for ch := range m.watchers {
+		close(ch)
+	}
+	m.watchers = make(map[chan ConnectionState]bool)
+	m.lock.Unlock()
+
+	if props != nil {
+		return m.dev.UnwatchProperties(props)
+	}
+	return nil
+}
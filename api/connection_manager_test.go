@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConnDevice struct {
+	lock      sync.Mutex
+	errors    []error
+	attempts  int
+	connected bool
+	resolved  bool
+	props     chan *bluez.PropertyChanged
+}
+
+func (d *fakeConnDevice) Path() dbus.ObjectPath {
+	return "/org/bluez/hci0/dev_00_11_22_33_44_55"
+}
+
+func (d *fakeConnDevice) ConnectContext(ctx context.Context) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.attempts++
+	if len(d.errors) > 0 {
+		err := d.errors[0]
+		d.errors = d.errors[1:]
+		if err != nil {
+			return err
+		}
+	}
+	d.connected = true
+	return nil
+}
+
+func (d *fakeConnDevice) Disconnect() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.connected = false
+	return nil
+}
+
+func (d *fakeConnDevice) GetConnected() (bool, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.connected, nil
+}
+
+func (d *fakeConnDevice) GetServicesResolved() (bool, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.resolved, nil
+}
+
+func (d *fakeConnDevice) WatchProperties() (chan *bluez.PropertyChanged, error) {
+	d.props = make(chan *bluez.PropertyChanged)
+	return d.props, nil
+}
+
+func (d *fakeConnDevice) UnwatchProperties(ch chan *bluez.PropertyChanged) error {
+	ch <- nil
+	close(ch)
+	return nil
+}
+
+func (d *fakeConnDevice) emit(name string, value interface{}) {
+	d.props <- &bluez.PropertyChanged{
+		Interface: "org.bluez.Device1",
+		Name:      name,
+		Value:     value,
+	}
+}
+
+type fakeNotifyChar struct {
+	lock    sync.Mutex
+	started int
+}
+
+func (c *fakeNotifyChar) Path() dbus.ObjectPath {
+	return "/org/bluez/hci0/dev_00_11_22_33_44_55/service000a/char000b"
+}
+
+func (c *fakeNotifyChar) StartNotify() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.started++
+	return nil
+}
+
+func (c *fakeNotifyChar) StopNotify() error {
+	return nil
+}
+
+func (c *fakeNotifyChar) count() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.started
+}
+
+func waitState(t *testing.T, ch chan ConnectionState, expected ConnectionState) {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case state := <-ch:
+			if state == expected {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Timeout waiting for state %s", expected)
+		}
+	}
+}
+
+func testConnectionOptions() ConnectionOptions {
+	return ConnectionOptions{
+		MaxRetries:              3,
+		InitialBackoff:          time.Millisecond,
+		MaxBackoff:              5 * time.Millisecond,
+		ServicesResolvedTimeout: time.Second,
+	}
+}
+
+func TestConnectionManagerRetry(t *testing.T) {
+
+	dev := &fakeConnDevice{
+		resolved: true,
+		errors: []error{
+			bluez.ParseError(dbus.Error{Name: "org.bluez.Error.InProgress"}),
+			bluez.ParseError(dbus.Error{Name: "org.bluez.Error.Failed"}),
+		},
+	}
+
+	states := []ConnectionState{}
+	opts := testConnectionOptions()
+	opts.OnStateChange = func(state ConnectionState) {
+		states = append(states, state)
+	}
+
+	m := newConnectionManager(dev, opts)
+	defer m.Close()
+
+	err := m.Connect(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, dev.attempts)
+	assert.Equal(t, StateReady, m.State())
+	assert.Equal(t, []ConnectionState{StateConnecting, StateConnected, StateReady}, states)
+}
+
+func TestConnectionManagerNotRetried(t *testing.T) {
+
+	dev := &fakeConnDevice{
+		errors: []error{
+			bluez.ParseError(dbus.Error{Name: "org.bluez.Error.NotReady"}),
+		},
+	}
+
+	m := newConnectionManager(dev, testConnectionOptions())
+	defer m.Close()
+
+	err := m.Connect(context.Background())
+	assert.True(t, errors.Is(err, bluez.ErrNotReady))
+	assert.Equal(t, 1, dev.attempts)
+	assert.Equal(t, StateDisconnected, m.State())
+}
+
+func TestConnectionManagerMaxRetries(t *testing.T) {
+
+	failed := bluez.ParseError(dbus.Error{Name: "org.bluez.Error.Failed"})
+	dev := &fakeConnDevice{
+		errors: []error{failed, failed, failed, failed},
+	}
+
+	m := newConnectionManager(dev, testConnectionOptions())
+	defer m.Close()
+
+	err := m.Connect(context.Background())
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, bluez.ErrFailed))
+	assert.Equal(t, 3, dev.attempts)
+	assert.Equal(t, StateDisconnected, m.State())
+}
+
+func TestConnectionManagerBackoff(t *testing.T) {
+	m := newConnectionManager(&fakeConnDevice{}, ConnectionOptions{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	})
+	assert.Equal(t, 100*time.Millisecond, m.backoff(0))
+	assert.Equal(t, 400*time.Millisecond, m.backoff(2))
+	assert.Equal(t, time.Second, m.backoff(10))
+}
+
+func TestConnectionManagerWaitServicesResolved(t *testing.T) {
+
+	dev := &fakeConnDevice{}
+	m := newConnectionManager(dev, testConnectionOptions())
+	defer m.Close()
+
+	states, cancel := m.WatchState()
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- m.Connect(context.Background())
+	}()
+
+	waitState(t, states, StateConnected)
+	dev.emit("ServicesResolved", true)
+
+	assert.NoError(t, <-done)
+	assert.Equal(t, StateReady, m.State())
+}
+
+func TestConnectionManagerReconnect(t *testing.T) {
+
+	dev := &fakeConnDevice{resolved: true}
+	opts := testConnectionOptions()
+	opts.AutoReconnect = true
+
+	m := newConnectionManager(dev, opts)
+	defer m.Close()
+
+	char := &fakeNotifyChar{}
+	assert.NoError(t, m.EnableNotify(char))
+	assert.Equal(t, 0, char.count())
+
+	assert.NoError(t, m.Connect(context.Background()))
+	assert.Equal(t, 1, char.count())
+
+	states, cancel := m.WatchState()
+	defer cancel()
+
+	dev.Disconnect()
+	dev.emit("Connected", false)
+
+	waitState(t, states, StateDisconnected)
+	waitState(t, states, StateReady)
+
+	assert.Equal(t, 2, dev.attempts)
+	assert.Equal(t, 2, char.count())
+
+	// no reconnection after an explicit disconnect
+	assert.NoError(t, m.Disconnect())
+	dev.emit("Connected", false)
+	assert.Equal(t, StateDisconnected, m.State())
+	assert.Equal(t, 2, dev.attempts)
+}
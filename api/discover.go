@@ -1,48 +1,72 @@
 package api
 
 import (
+	"sync"
+
 	"github.com/dimonzozo/go-bluetooth/bluez/profile/adapter"
 	log "github.com/sirupsen/logrus"
 )
 
-// Discover start device discovery
+// Discover start device discovery. Sessions on the same adapter share
+// discovery via the adapter DiscoveryManager, each receiving the devices
+// matching its own filter
 func Discover(a *adapter.Adapter1, filter *adapter.DiscoveryFilter) (chan *adapter.DeviceDiscovered, func(), error) {
 
-	err := a.SetPairable(false)
-	if err != nil {
-		return nil, nil, err
-	}
-	err = a.SetDiscoverable(false)
-	if err != nil {
-		return nil, nil, err
-	}
-	err = a.SetPowered(true)
+	err := a.SetPowered(true)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	filterMap := make(map[string]interface{})
+	extFilter := NewExtendedDiscoveryFilter(nil)
 	if filter != nil {
-		filterMap = filter.ToMap()
-	}
-	err = a.SetDiscoveryFilter(filterMap)
-	if err != nil {
-		return nil, nil, err
+		extFilter.DiscoveryFilter = *filter
 	}
 
-	err = a.StartDiscovery()
+	session, err := GetDiscoveryManager(a).Start(extFilter)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	ch, discoveryCancel, err := a.OnDeviceDiscovered()
+	ch := make(chan *adapter.DeviceDiscovered)
+	done := make(chan struct{})
 
-	cancel := func() {
-		err := a.StopDiscovery()
-		if err != nil {
-			log.Warnf("Error stopping discovery: %s", err)
+	go func() {
+		defer close(ch)
+		seen := map[string]bool{}
+		for report := range session.Reports() {
+
+			var ev *adapter.DeviceDiscovered
+			if report.Removed {
+				if !seen[string(report.Path)] {
+					continue
+				}
+				delete(seen, string(report.Path))
+				ev = &adapter.DeviceDiscovered{Path: report.Path, Type: adapter.DeviceRemoved}
+			} else {
+				if seen[string(report.Path)] {
+					continue
+				}
+				seen[string(report.Path)] = true
+				ev = &adapter.DeviceDiscovered{Path: report.Path, Type: adapter.DeviceAdded}
+			}
+
+			select {
+			case ch <- ev:
+			case <-done:
+				return
+			}
 		}
-		discoveryCancel()
+	}()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			close(done)
+			err := session.Stop()
+			if err != nil {
+				log.Warnf("Error stopping discovery: %s", err)
+			}
+		})
 	}
 
 	return ch, cancel, nil
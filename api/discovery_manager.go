@@ -0,0 +1,280 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/adapter"
+)
+
+type discoveryManagerKey struct {
+	conn *bluez.Conn
+	path dbus.ObjectPath
+}
+
+var discoveryManagers = map[discoveryManagerKey]*DiscoveryManager{}
+var discoveryManagersLock sync.Mutex
+
+// GetDiscoveryManager return the discovery manager shared by the sessions
+// started on the adapter with the same connection
+func GetDiscoveryManager(a *adapter.Adapter1) *DiscoveryManager {
+	discoveryManagersLock.Lock()
+	defer discoveryManagersLock.Unlock()
+
+	key := discoveryManagerKey{a.Client().Conn(), a.Path()}
+	if m, ok := discoveryManagers[key]; ok {
+		return m
+	}
+
+	m := NewDiscoveryManager(a)
+	discoveryManagers[key] = m
+	return m
+}
+
+// NewDiscoveryManager create a discovery manager for the adapter. Use
+// GetDiscoveryManager to share discovery with other parts of the program
+func NewDiscoveryManager(a *adapter.Adapter1) *DiscoveryManager {
+	m := &DiscoveryManager{
+		adapter:  a,
+		scanner:  NewScanner(a),
+		sessions: make(map[*DiscoverySession]bool),
+	}
+	m.scanner.manager = m
+	return m
+}
+
+// DiscoveryManager arbitrate the discovery sessions of an adapter. Bluez
+// allows a single filter per client, the manager set the union of the
+// sessions filters and apply each filter locally on the reports
+type DiscoveryManager struct {
+	adapter *adapter.Adapter1
+	scanner *Scanner
+
+	lock     sync.Mutex
+	sessions map[*DiscoverySession]bool
+}
+
+// DiscoverySession receive the reports matching its filter
+type DiscoverySession struct {
+	manager     *DiscoveryManager
+	filter      *ExtendedDiscoveryFilter
+	reports     chan *AdvertisementReport
+	unsubscribe func()
+}
+
+// Reports return the channel receiving the session reports, it is closed when the session stops
+func (s *DiscoverySession) Reports() chan *AdvertisementReport {
+	return s.reports
+}
+
+// Filter return the session filter
+func (s *DiscoverySession) Filter() *ExtendedDiscoveryFilter {
+	return s.filter
+}
+
+// Stop the session, discovery is stopped when no sessions are left
+func (s *DiscoverySession) Stop() error {
+	return s.manager.stop(s)
+}
+
+// Scanner return the scanner used by the sessions
+func (m *DiscoveryManager) Scanner() *Scanner {
+	return m.scanner
+}
+
+// Sessions return the number of active sessions
+func (m *DiscoveryManager) Sessions() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.sessions)
+}
+
+// Start a session, discovery is started with the first session.
+// A nil filter matches all the devices
+func (m *DiscoveryManager) Start(filter *ExtendedDiscoveryFilter) (*DiscoverySession, error) {
+	return m.start(filter, true)
+}
+
+// acquire a session contributing its filter to discovery, without
+// subscribing to the reports
+func (m *DiscoveryManager) acquire(filter *ExtendedDiscoveryFilter) (*DiscoverySession, error) {
+	return m.start(filter, false)
+}
+
+func (m *DiscoveryManager) start(filter *ExtendedDiscoveryFilter, subscribe bool) (*DiscoverySession, error) {
+
+	if filter == nil {
+		filter = NewExtendedDiscoveryFilter(nil)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	session := &DiscoverySession{
+		manager:     m,
+		filter:      filter,
+		unsubscribe: func() {},
+	}
+
+	if subscribe {
+		match := MatchDiscoveryFilter(filter.DiscoveryFilter)
+		if filter.Match != nil {
+			match = And(match, filter.Match)
+		}
+		session.reports, session.unsubscribe = m.scanner.Subscribe(match)
+	}
+
+	m.sessions[session] = true
+	merged := m.mergedFilter()
+
+	var err error
+	if len(m.sessions) == 1 {
+		err = m.startDiscovery(&merged)
+	} else {
+		err = m.setDiscoveryFilter(&merged)
+	}
+
+	if err != nil {
+		delete(m.sessions, session)
+		session.unsubscribe()
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// setFilter replace the filter of a session
+func (m *DiscoveryManager) setFilter(session *DiscoverySession, filter *ExtendedDiscoveryFilter) error {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.sessions[session] {
+		return errors.New("Discovery session already stopped")
+	}
+
+	session.filter = filter
+	merged := m.mergedFilter()
+	return m.setDiscoveryFilter(&merged)
+}
+
+func (m *DiscoveryManager) stop(session *DiscoverySession) error {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !m.sessions[session] {
+		return errors.New("Discovery session already stopped")
+	}
+
+	delete(m.sessions, session)
+	session.unsubscribe()
+
+	if len(m.sessions) == 0 {
+		return m.stopDiscovery()
+	}
+
+	merged := m.mergedFilter()
+	return m.setDiscoveryFilter(&merged)
+}
+
+// startDiscovery start watching the devices, set the filter and start discovery
+func (m *DiscoveryManager) startDiscovery(filter *adapter.DiscoveryFilter) error {
+
+	err := m.scanner.startWatch()
+	if err != nil {
+		return err
+	}
+
+	err = m.setDiscoveryFilter(filter)
+	if err != nil {
+		m.scanner.stopWatch()
+		return err
+	}
+
+	err = m.adapter.StartDiscovery()
+	if err != nil {
+		m.scanner.stopWatch()
+		return fmt.Errorf("StartDiscovery: %s", err)
+	}
+
+	return nil
+}
+
+func (m *DiscoveryManager) setDiscoveryFilter(filter *adapter.DiscoveryFilter) error {
+	err := m.adapter.SetDiscoveryFilter(filter.ToMap())
+	if err != nil {
+		return fmt.Errorf("SetDiscoveryFilter: %s", err)
+	}
+	return nil
+}
+
+// stopDiscovery stop discovery and close the subscribers channels
+func (m *DiscoveryManager) stopDiscovery() error {
+
+	m.scanner.stopWatch()
+
+	err := m.adapter.StopDiscovery()
+	if err != nil {
+		return fmt.Errorf("StopDiscovery: %s", err)
+	}
+
+	return nil
+}
+
+func (m *DiscoveryManager) mergedFilter() adapter.DiscoveryFilter {
+	filters := []adapter.DiscoveryFilter{}
+	for session := range m.sessions {
+		filters = append(filters, session.filter.DiscoveryFilter)
+	}
+	return adapter.MergeDiscoveryFilters(filters...)
+}
+
+// MatchDiscoveryFilter evaluate locally the UUIDs, RSSI and Pathloss
+// constraints of a Bluez discovery filter
+func MatchDiscoveryFilter(filter adapter.DiscoveryFilter) ScanFilter {
+
+	uuids := map[string]bool{}
+	for _, uuid := range filter.UUIDs {
+		uuids[bluez.ExpandUUID(uuid)] = true
+	}
+
+	return func(r *AdvertisementReport) bool {
+
+		// the last RSSI of a removed device is not relevant
+		if r.Removed {
+			return true
+		}
+
+		if len(uuids) > 0 {
+			found := false
+			for _, uuid := range r.UUIDs {
+				if uuids[bluez.ExpandUUID(uuid)] {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+
+		if filter.RSSI != 0 && (r.RSSI == 0 || r.RSSI < filter.RSSI) {
+			return false
+		}
+
+		if filter.Pathloss != 0 {
+			if !r.HasTxPower || r.RSSI == 0 {
+				return false
+			}
+			if int(r.TxPower)-int(r.RSSI) > int(filter.Pathloss) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
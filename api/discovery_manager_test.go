@@ -0,0 +1,62 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchDiscoveryFilter(t *testing.T) {
+
+	r := &AdvertisementReport{
+		RSSI:       -70,
+		TxPower:    4,
+		HasTxPower: true,
+		UUIDs:      []string{"0000180f-0000-1000-8000-00805f9b34fb"},
+	}
+
+	f := adapter.NewDiscoveryFilter()
+	assert.True(t, MatchDiscoveryFilter(f)(r))
+
+	f.AddUUIDs("180F")
+	assert.True(t, MatchDiscoveryFilter(f)(r))
+	f.UUIDs = []string{"180D"}
+	assert.False(t, MatchDiscoveryFilter(f)(r))
+
+	f = adapter.NewDiscoveryFilter()
+	f.RSSI = -80
+	assert.True(t, MatchDiscoveryFilter(f)(r))
+	f.RSSI = -60
+	assert.False(t, MatchDiscoveryFilter(f)(r))
+	assert.True(t, MatchDiscoveryFilter(f)(&AdvertisementReport{Removed: true}))
+
+	f = adapter.NewDiscoveryFilter()
+	f.Pathloss = 80
+	assert.True(t, MatchDiscoveryFilter(f)(r))
+	f.Pathloss = 70
+	assert.False(t, MatchDiscoveryFilter(f)(r))
+}
+
+func TestDiscoveryManagerMergedFilter(t *testing.T) {
+
+	m := &DiscoveryManager{
+		sessions: make(map[*DiscoverySession]bool),
+	}
+
+	f1 := NewExtendedDiscoveryFilter(nil)
+	f1.AddUUIDs("180F")
+	f1.RSSI = -60
+	f2 := NewExtendedDiscoveryFilter(nil)
+	f2.AddUUIDs("180D")
+	f2.RSSI = -90
+
+	m.sessions[&DiscoverySession{filter: f1}] = true
+	m.sessions[&DiscoverySession{filter: f2}] = true
+
+	merged := m.mergedFilter()
+	assert.ElementsMatch(t, []string{"180F", "180D"}, merged.UUIDs)
+	assert.Equal(t, int16(-90), merged.RSSI)
+	assert.True(t, merged.DuplicateData)
+	assert.Equal(t, adapter.DiscoveryFilterTransportAuto, merged.Transport)
+}
@@ -0,0 +1,330 @@
+package api
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/adapter"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+	log "github.com/sirupsen/logrus"
+)
+
+// PresenceEventType is the type of a presence change
+type PresenceEventType int
+
+const (
+	// PresenceAppeared a device has been seen for the first time or after being lost
+	PresenceAppeared PresenceEventType = iota
+	// PresenceUpdated a new advertisement of a present device has been received
+	PresenceUpdated
+	// PresenceLost a device has not been seen for PresenceOptions.Timeout
+	PresenceLost
+)
+
+func (t PresenceEventType) String() string {
+	switch t {
+	case PresenceAppeared:
+		return "appeared"
+	case PresenceUpdated:
+		return "updated"
+	case PresenceLost:
+		return "lost"
+	}
+	return "unknown"
+}
+
+// txPowerLossAt1m is the path loss at 1 meter used to compute the
+// measured power from the advertised TX power
+const txPowerLossAt1m = 41
+
+// PresenceOptions configure a PresenceTracker
+type PresenceOptions struct {
+	// Timeout is the time without advertisements after which a device is lost
+	Timeout time.Duration
+	// CheckInterval is the period of the lost devices check, Timeout/4 if zero
+	CheckInterval time.Duration
+	// RemoveLost remove the lost devices from the Bluez cache. Paired and
+	// trusted devices are kept, removing them would delete their bond
+	RemoveLost bool
+	// NewRSSIFilter create the RSSI filter of a device, EWMA with alpha 0.3 if nil
+	NewRSSIFilter func() RSSIFilter
+	// MeasuredPower return the RSSI at 1 meter of a device. If nil it is
+	// computed from the advertised TX power, when available
+	MeasuredPower func(report *AdvertisementReport) (int16, bool)
+	// PathLossExponent of the environment, 2 if zero
+	PathLossExponent float64
+	// DiscoveryFilter is the filter of the discovery session
+	DiscoveryFilter *ExtendedDiscoveryFilter
+}
+
+// DefaultPresenceTimeout is the PresenceOptions timeout used when not set
+const DefaultPresenceTimeout = 30 * time.Second
+
+// TrackedDevice describe the presence of a device
+type TrackedDevice struct {
+	Path      dbus.ObjectPath
+	Address   string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// RSSI is the last received RSSI
+	RSSI int16
+	// SmoothedRSSI is the RSSI filtered by the RSSIFilter
+	SmoothedRSSI float64
+	// Distance is the estimated distance in meters, -1 if unknown
+	Distance float64
+	// Report is the last advertisement report
+	Report *AdvertisementReport
+
+	filter RSSIFilter
+}
+
+// PresenceEvent is emitted when a device appears, is updated or lost
+type PresenceEvent struct {
+	Type   PresenceEventType
+	Device TrackedDevice
+}
+
+// NewPresenceTracker create a presence tracker for the adapter
+func NewPresenceTracker(a *adapter.Adapter1, opts PresenceOptions) *PresenceTracker {
+
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultPresenceTimeout
+	}
+	if opts.CheckInterval == 0 {
+		opts.CheckInterval = opts.Timeout / 4
+	}
+	if opts.NewRSSIFilter == nil {
+		opts.NewRSSIFilter = func() RSSIFilter {
+			return NewEWMAFilter(0.3)
+		}
+	}
+	if opts.PathLossExponent == 0 {
+		opts.PathLossExponent = 2
+	}
+	if opts.DiscoveryFilter == nil {
+		opts.DiscoveryFilter = NewExtendedDiscoveryFilter(nil)
+	}
+
+	return &PresenceTracker{
+		adapter: a,
+		opts:    opts,
+		devices: make(map[dbus.ObjectPath]*TrackedDevice),
+	}
+}
+
+// PresenceTracker track the devices in range using a discovery session
+type PresenceTracker struct {
+	adapter *adapter.Adapter1
+	opts    PresenceOptions
+
+	lock    sync.Mutex
+	devices map[dbus.ObjectPath]*TrackedDevice
+	session *DiscoverySession
+	events  chan *PresenceEvent
+	quit    chan struct{}
+	done    chan struct{}
+}
+
+// Start discovery and return the channel receiving the presence events,
+// the channel is closed by Stop
+func (t *PresenceTracker) Start() (chan *PresenceEvent, error) {
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.session != nil {
+		return nil, errors.New("Presence tracker already started")
+	}
+
+	session, err := GetDiscoveryManager(t.adapter).Start(t.opts.DiscoveryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	t.session = session
+	t.events = make(chan *PresenceEvent)
+	t.quit = make(chan struct{})
+	t.done = make(chan struct{})
+
+	go t.run(session.Reports(), t.events, t.quit, t.done)
+
+	return t.events, nil
+}
+
+// Stop discovery and close the events channel
+func (t *PresenceTracker) Stop() error {
+
+	t.lock.Lock()
+	session := t.session
+	if session == nil {
+		t.lock.Unlock()
+		return nil
+	}
+	t.session = nil
+	close(t.quit)
+	done := t.done
+	t.lock.Unlock()
+
+	<-done
+	return session.Stop()
+}
+
+// Devices return a snapshot of the present devices sorted by path
+func (t *PresenceTracker) Devices() []TrackedDevice {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	list := []TrackedDevice{}
+	for _, dev := range t.devices {
+		list = append(list, *dev)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Path < list[j].Path
+	})
+
+	return list
+}
+
+func (t *PresenceTracker) run(reports chan *AdvertisementReport, events chan *PresenceEvent, quit chan struct{}, done chan struct{}) {
+
+	defer close(done)
+	defer close(events)
+
+	ticker := time.NewTicker(t.opts.CheckInterval)
+	defer ticker.Stop()
+
+	send := func(ev *PresenceEvent) bool {
+		select {
+		case events <- ev:
+			return true
+		case <-quit:
+			return false
+		}
+	}
+
+	for {
+		select {
+		case report, ok := <-reports:
+			if !ok {
+				return
+			}
+			if ev := t.handleReport(report, time.Now()); ev != nil {
+				if !send(ev) {
+					return
+				}
+			}
+		case now := <-ticker.C:
+			for _, ev := range t.expire(now) {
+				if t.opts.RemoveLost {
+					t.removeDevice(ev.Device.Path)
+				}
+				if !send(ev) {
+					return
+				}
+			}
+		case <-quit:
+			return
+		}
+	}
+}
+
+// handleReport update the device state and return the event to emit
+func (t *PresenceTracker) handleReport(report *AdvertisementReport, now time.Time) *PresenceEvent {
+
+	// removals from the Bluez cache are not used, presence rely on the timeout
+	if report.Removed || report.RSSI == 0 {
+		return nil
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	dev, found := t.devices[report.Path]
+	if found && !report.HasChanged("RSSI", "ManufacturerData", "ServiceData", "TxPower") {
+		return nil
+	}
+
+	evType := PresenceUpdated
+	if !found {
+		evType = PresenceAppeared
+		dev = &TrackedDevice{
+			Path:      report.Path,
+			FirstSeen: now,
+			filter:    t.opts.NewRSSIFilter(),
+		}
+		t.devices[report.Path] = dev
+	}
+
+	dev.Address = report.Address
+	dev.LastSeen = now
+	dev.RSSI = report.RSSI
+	dev.SmoothedRSSI = dev.filter.Update(float64(report.RSSI))
+	dev.Report = report
+
+	dev.Distance = -1
+	if power, ok := t.measuredPower(report); ok {
+		dev.Distance = EstimateDistance(dev.SmoothedRSSI, float64(power), t.opts.PathLossExponent)
+	}
+
+	return &PresenceEvent{
+		Type:   evType,
+		Device: *dev,
+	}
+}
+
+func (t *PresenceTracker) measuredPower(report *AdvertisementReport) (int16, bool) {
+	if t.opts.MeasuredPower != nil {
+		return t.opts.MeasuredPower(report)
+	}
+	if !report.HasTxPower {
+		return 0, false
+	}
+	return report.TxPower - txPowerLossAt1m, true
+}
+
+// expire remove the devices not seen since Timeout and return the lost events
+func (t *PresenceTracker) expire(now time.Time) []*PresenceEvent {
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	events := []*PresenceEvent{}
+	for path, dev := range t.devices {
+		if now.Sub(dev.LastSeen) < t.opts.Timeout {
+			continue
+		}
+		delete(t.devices, path)
+		events = append(events, &PresenceEvent{
+			Type:   PresenceLost,
+			Device: *dev,
+		})
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Device.Path < events[j].Device.Path
+	})
+
+	return events
+}
+
+// removeDevice remove a lost device unless it is paired or trusted
+func (t *PresenceTracker) removeDevice(path dbus.ObjectPath) {
+
+	dev, err := device.NewDevice1WithConn(t.adapter.Client().Conn(), path)
+	if err != nil {
+		log.Debugf("PresenceTracker: %s: %s", path, err)
+		return
+	}
+	if dev.Properties.Paired || dev.Properties.Trusted {
+		log.Debugf("PresenceTracker: keep paired or trusted device %s", path)
+		return
+	}
+
+	err = t.adapter.RemoveDevice(path)
+	if err != nil {
+		log.Debugf("PresenceTracker: RemoveDevice %s: %s", path, err)
+	}
+}
@@ -0,0 +1,95 @@
+package api
+
+import "math"
+
+// RSSIFilter smooth the RSSI samples of a device
+type RSSIFilter interface {
+	// Update add a sample and return the smoothed value
+	Update(rssi float64) float64
+	// Value return the smoothed value
+	Value() float64
+}
+
+// NewEWMAFilter create an exponentially weighted moving average filter,
+// alpha in (0, 1] is the weight of new samples
+func NewEWMAFilter(alpha float64) *EWMAFilter {
+	return &EWMAFilter{Alpha: alpha}
+}
+
+// EWMAFilter is an exponentially weighted moving average
+type EWMAFilter struct {
+	Alpha float64
+
+	value float64
+	init  bool
+}
+
+// Update add a sample and return the smoothed value
+func (f *EWMAFilter) Update(rssi float64) float64 {
+	if !f.init {
+		f.value = rssi
+		f.init = true
+		return f.value
+	}
+	f.value = f.Alpha*rssi + (1-f.Alpha)*f.value
+	return f.value
+}
+
+// Value return the smoothed value
+func (f *EWMAFilter) Value() float64 {
+	return f.value
+}
+
+// NewKalmanFilter create a one dimensional Kalman filter for a static
+// signal, processNoise (Q) model the signal variation and
+// measurementNoise (R) the noise of the samples
+func NewKalmanFilter(processNoise float64, measurementNoise float64) *KalmanFilter {
+	return &KalmanFilter{
+		ProcessNoise:     processNoise,
+		MeasurementNoise: measurementNoise,
+	}
+}
+
+// KalmanFilter is a one dimensional Kalman filter
+type KalmanFilter struct {
+	ProcessNoise     float64
+	MeasurementNoise float64
+
+	value float64
+	cov   float64
+	init  bool
+}
+
+// Update add a sample and return the smoothed value
+func (f *KalmanFilter) Update(rssi float64) float64 {
+	if !f.init {
+		f.value = rssi
+		f.cov = f.MeasurementNoise
+		f.init = true
+		return f.value
+	}
+
+	// predict
+	cov := f.cov + f.ProcessNoise
+	// correct
+	gain := cov / (cov + f.MeasurementNoise)
+	f.value = f.value + gain*(rssi-f.value)
+	f.cov = (1 - gain) * cov
+
+	return f.value
+}
+
+// Value return the smoothed value
+func (f *KalmanFilter) Value() float64 {
+	return f.value
+}
+
+// EstimateDistance return the distance in meters using the log-distance
+// path loss model. measuredPower is the RSSI at 1 meter and pathLossExponent
+// depends on the environment, 2 in free space up to 4 indoor
+func EstimateDistance(rssi float64, measuredPower float64, pathLossExponent float64) float64 {
+	if rssi == 0 || pathLossExponent <= 0 {
+		return -1
+	}
+	return math.Pow(10, (measuredPower-rssi)/(10*pathLossExponent))
+}
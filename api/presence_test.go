@@ -0,0 +1,93 @@
+package api
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSSIFilters(t *testing.T) {
+
+	ewma := NewEWMAFilter(0.5)
+	assert.Equal(t, -60.0, ewma.Update(-60))
+	assert.Equal(t, -70.0, ewma.Update(-80))
+	assert.Equal(t, -70.0, ewma.Value())
+
+	kalman := NewKalmanFilter(0.01, 4)
+	assert.Equal(t, -60.0, kalman.Update(-60))
+	v := kalman.Update(-80)
+	assert.True(t, v < -60 && v > -80)
+	for i := 0; i < 100; i++ {
+		kalman.Update(-80)
+	}
+	assert.InDelta(t, -80, kalman.Value(), 1)
+}
+
+func TestEstimateDistance(t *testing.T) {
+	assert.InDelta(t, 1, EstimateDistance(-59, -59, 2), 0.001)
+	assert.InDelta(t, 10, EstimateDistance(-79, -59, 2), 0.001)
+	assert.InDelta(t, math.Pow(10, 0.5), EstimateDistance(-79, -59, 4), 0.001)
+	assert.Equal(t, -1.0, EstimateDistance(0, -59, 2))
+}
+
+func TestPresenceTracker(t *testing.T) {
+
+	tracker := NewPresenceTracker(nil, PresenceOptions{
+		Timeout: 10 * time.Second,
+		NewRSSIFilter: func() RSSIFilter {
+			return NewEWMAFilter(0.5)
+		},
+	})
+
+	path := dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55")
+	now := time.Now()
+
+	ev := tracker.handleReport(&AdvertisementReport{
+		Path:       path,
+		Address:    "00:11:22:33:44:55",
+		RSSI:       -59,
+		TxPower:    -18,
+		HasTxPower: true,
+	}, now)
+	assert.Equal(t, PresenceAppeared, ev.Type)
+	assert.Equal(t, -59.0, ev.Device.SmoothedRSSI)
+	assert.InDelta(t, 1, ev.Device.Distance, 0.001)
+
+	// changes not related to advertisements are ignored
+	assert.Nil(t, tracker.handleReport(&AdvertisementReport{
+		Path:    path,
+		RSSI:    -59,
+		Changed: []string{"Connected"},
+	}, now))
+
+	ev = tracker.handleReport(&AdvertisementReport{
+		Path:    path,
+		RSSI:    -79,
+		Changed: []string{"RSSI"},
+	}, now.Add(5*time.Second))
+	assert.Equal(t, PresenceUpdated, ev.Type)
+	assert.Equal(t, -69.0, ev.Device.SmoothedRSSI)
+	assert.Equal(t, -1.0, ev.Device.Distance)
+	assert.Equal(t, now, ev.Device.FirstSeen)
+
+	assert.Len(t, tracker.expire(now.Add(10*time.Second)), 0)
+	assert.Len(t, tracker.Devices(), 1)
+
+	lost := tracker.expire(now.Add(15 * time.Second))
+	assert.Len(t, lost, 1)
+	assert.Equal(t, PresenceLost, lost[0].Type)
+	assert.Equal(t, path, lost[0].Device.Path)
+	assert.Len(t, tracker.Devices(), 0)
+
+	ev = tracker.handleReport(&AdvertisementReport{
+		Path:    path,
+		RSSI:    -70,
+		Changed: []string{"RSSI"},
+	}, now.Add(20*time.Second))
+	assert.Equal(t, PresenceAppeared, ev.Type)
+
+	assert.Nil(t, tracker.handleReport(&AdvertisementReport{Path: path, Removed: true}, now))
+}
@@ -0,0 +1,470 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/adapter"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+	log "github.com/sirupsen/logrus"
+)
+
+// ScannerBufferSize is the number of reports queued for each subscriber,
+// reports are dropped when a subscriber does not keep up. Removals are never
+// dropped, they wait for room in the queue
+var ScannerBufferSize = 100
+
+// scannerRetryInterval is the delay before retrying to deliver the removals
+// waiting for room in a subscriber queue
+const scannerRetryInterval = 100 * time.Millisecond
+
+// ScanFilter select the reports delivered to a subscriber
+type ScanFilter func(report *AdvertisementReport) bool
+
+// NewScanner create a scanner for the adapter
+func NewScanner(a *adapter.Adapter1) *Scanner {
+	return &Scanner{
+		adapter:     a,
+		path:        a.Path(),
+		devices:     make(map[dbus.ObjectPath]map[string]dbus.Variant),
+		subscribers: make(map[chan *AdvertisementReport]*scanSubscriber),
+	}
+}
+
+// Scanner run discovery and merge the ObjectManager and devices properties
+// signals into a stream of advertisement reports
+type Scanner struct {
+	adapter *adapter.Adapter1
+	path    dbus.ObjectPath
+	// manager is set on the scanner of a DiscoveryManager
+	manager *DiscoveryManager
+
+	sessionLock sync.Mutex
+	session     *DiscoverySession
+
+	lock        sync.Mutex
+	signal      chan *dbus.Signal
+	quit        chan struct{}
+	devices     map[dbus.ObjectPath]map[string]dbus.Variant
+	subscribers map[chan *AdvertisementReport]*scanSubscriber
+}
+
+// scanSubscriber track the devices sent to a subscriber, to deliver their
+// removal whatever the filter
+type scanSubscriber struct {
+	filter ScanFilter
+	sent   map[dbus.ObjectPath]bool
+	// removed are the removals waiting for room in the queue, a device stays
+	// in sent until its removal is delivered
+	removed []*AdvertisementReport
+}
+
+// flush send the waiting removals in order, it return false if some are left
+func (sub *scanSubscriber) flush(ch chan *AdvertisementReport) bool {
+	for len(sub.removed) > 0 {
+		report := sub.removed[0]
+		select {
+		case ch <- report:
+		default:
+			return false
+		}
+		delete(sub.sent, report.Path)
+		sub.removed = sub.removed[1:]
+	}
+	return true
+}
+
+// Adapter return the scanning adapter
+func (s *Scanner) Adapter() *adapter.Adapter1 {
+	return s.adapter
+}
+
+// Start set the discovery filter and start discovery, a nil filter clear it.
+// Discovery is shared with the other sessions of the adapter DiscoveryManager
+func (s *Scanner) Start(filter *adapter.DiscoveryFilter) error {
+
+	s.sessionLock.Lock()
+	defer s.sessionLock.Unlock()
+
+	if s.session != nil {
+		return errors.New("Scanner already started")
+	}
+
+	// the manager scanner is watched while the manager has sessions
+	manager := s.discoveryManager()
+	if manager.scanner != s {
+		err := s.startWatch()
+		if err != nil {
+			return err
+		}
+	}
+
+	session, err := manager.acquire(toExtendedDiscoveryFilter(filter))
+	if err != nil {
+		if manager.scanner != s {
+			s.stopWatch()
+		}
+		return err
+	}
+
+	s.session = session
+	return nil
+}
+
+// SetFilter update the discovery filter, a nil filter clear it
+func (s *Scanner) SetFilter(filter *adapter.DiscoveryFilter) error {
+
+	s.sessionLock.Lock()
+	defer s.sessionLock.Unlock()
+
+	if s.session == nil {
+		return errors.New("Scanner not started")
+	}
+
+	return s.session.manager.setFilter(s.session, toExtendedDiscoveryFilter(filter))
+}
+
+// Stop discovery and close the subscribers channels
+func (s *Scanner) Stop() error {
+
+	s.sessionLock.Lock()
+	defer s.sessionLock.Unlock()
+
+	if s.session == nil {
+		return nil
+	}
+
+	session := s.session
+	s.session = nil
+
+	err := session.Stop()
+	if session.manager.scanner != s {
+		s.stopWatch()
+	}
+
+	return err
+}
+
+func (s *Scanner) discoveryManager() *DiscoveryManager {
+	if s.manager != nil {
+		return s.manager
+	}
+	return GetDiscoveryManager(s.adapter)
+}
+
+func toExtendedDiscoveryFilter(filter *adapter.DiscoveryFilter) *ExtendedDiscoveryFilter {
+	// a nil filter keep the Bluez defaults
+	ext := NewExtendedDiscoveryFilter(nil)
+	if filter != nil {
+		ext.DiscoveryFilter = *filter
+	}
+	return ext
+}
+
+// startWatch load the known devices and watch their changes
+func (s *Scanner) startWatch() error {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.signal != nil {
+		return errors.New("Scanner already started")
+	}
+
+	client := s.adapter.Client()
+	signal, err := client.RegisterWithOptions(
+		bluez.SubscribeOptions{
+			Overflow: bluez.OverflowCoalesce,
+		},
+		bluez.SignalFilter{
+			Path:      "/",
+			Interface: bluez.ObjectManagerInterface,
+		},
+		bluez.SignalFilter{
+			PathNamespace: s.path,
+			Interface:     bluez.PropertiesInterface,
+			Member:        "PropertiesChanged",
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = s.load()
+	if err != nil {
+		client.Unregister(s.adapter.Path(), bluez.PropertiesInterface, signal)
+		return err
+	}
+
+	s.signal = signal
+	s.quit = make(chan struct{})
+	go s.watch(signal, s.quit)
+
+	return nil
+}
+
+// stopWatch stop watching the devices and close the subscribers channels
+func (s *Scanner) stopWatch() {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.signal == nil {
+		return
+	}
+
+	close(s.quit)
+	s.adapter.Client().Unregister(s.adapter.Path(), bluez.PropertiesInterface, s.signal)
+	s.signal = nil
+	s.quit = nil
+
+	for ch := range s.subscribers {
+		close(ch)
+	}
+	s.subscribers = make(map[chan *AdvertisementReport]*scanSubscriber)
+}
+
+// Subscribe return a channel receiving the reports matching filter,
+// a nil filter matches all the reports. The removal of a device is always
+// delivered if the device has been sent. Use cancel to stop receiving reports
+func (s *Scanner) Subscribe(filter ScanFilter) (chan *AdvertisementReport, func()) {
+
+	ch := make(chan *AdvertisementReport, ScannerBufferSize)
+
+	s.lock.Lock()
+	s.subscribers[ch] = &scanSubscriber{
+		filter: filter,
+		sent:   make(map[dbus.ObjectPath]bool),
+	}
+	s.lock.Unlock()
+
+	cancel := func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if _, ok := s.subscribers[ch]; ok {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return ch, cancel
+}
+
+// Reports return a report for each known device matching filter
+func (s *Scanner) Reports(filter ScanFilter) []*AdvertisementReport {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	reports := []*AdvertisementReport{}
+	for path, props := range s.devices {
+		report := NewAdvertisementReport(path, props)
+		if filter == nil || filter(report) {
+			reports = append(reports, report)
+		}
+	}
+
+	return reports
+}
+
+// load the devices already known by Bluez
+func (s *Scanner) load() error {
+
+	om, err := s.adapter.Client().GetObjectsProvider()
+	if err != nil {
+		return err
+	}
+
+	objects, err := om.GetManagedObjects()
+	if err != nil {
+		return fmt.Errorf("GetManagedObjects: %s", err)
+	}
+
+	for path, ifaces := range objects {
+		if props, ok := ifaces[device.Device1Interface]; ok && s.isAdapterDevice(path) {
+			s.devices[path] = props
+		}
+	}
+
+	return nil
+}
+
+func (s *Scanner) isAdapterDevice(path dbus.ObjectPath) bool {
+	return strings.HasPrefix(string(path), string(s.path)+"/")
+}
+
+func (s *Scanner) watch(ch chan *dbus.Signal, quit chan struct{}) {
+	var retry <-chan time.Time
+	for {
+		waiting := false
+		select {
+		case sig := <-ch:
+			if sig == nil {
+				return
+			}
+			report := s.handleSignal(sig, time.Now())
+			if report != nil {
+				waiting = s.publish(report)
+			} else if retry != nil {
+				waiting = s.flushRemovals()
+			}
+		case <-retry:
+			waiting = s.flushRemovals()
+		case <-quit:
+			return
+		}
+		retry = nil
+		if waiting {
+			retry = time.After(scannerRetryInterval)
+		}
+	}
+}
+
+// handleSignal update the devices state and return a report if the signal
+// add, change or remove a device
+func (s *Scanner) handleSignal(sig *dbus.Signal, now time.Time) *AdvertisementReport {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var path dbus.ObjectPath
+	var changed map[string]dbus.Variant
+	var invalidated []string
+
+	switch sig.Name {
+	case bluez.InterfacesAdded:
+		if len(sig.Body) < 2 {
+			return nil
+		}
+		path, _ = sig.Body[0].(dbus.ObjectPath)
+		ifaces, ok := sig.Body[1].(map[string]map[string]dbus.Variant)
+		if !ok || !s.isAdapterDevice(path) {
+			return nil
+		}
+		changed, ok = ifaces[device.Device1Interface]
+		if !ok {
+			return nil
+		}
+		s.devices[path] = make(map[string]dbus.Variant)
+
+	case bluez.InterfacesRemoved:
+		if len(sig.Body) < 2 {
+			return nil
+		}
+		path, _ = sig.Body[0].(dbus.ObjectPath)
+		ifaces, _ := sig.Body[1].([]string)
+		for _, iface := range ifaces {
+			if iface != device.Device1Interface {
+				continue
+			}
+			props, ok := s.devices[path]
+			if !ok {
+				return nil
+			}
+			delete(s.devices, path)
+			report := NewAdvertisementReport(path, props)
+			report.Removed = true
+			report.Timestamp = now
+			return report
+		}
+		return nil
+
+	case bluez.PropertiesChanged:
+		if len(sig.Body) < 2 {
+			return nil
+		}
+		if iface, _ := sig.Body[0].(string); iface != device.Device1Interface {
+			return nil
+		}
+		path = sig.Path
+		changed, _ = sig.Body[1].(map[string]dbus.Variant)
+		if len(sig.Body) > 2 {
+			invalidated, _ = sig.Body[2].([]string)
+		}
+		if _, ok := s.devices[path]; !ok {
+			s.devices[path] = make(map[string]dbus.Variant)
+		}
+
+	default:
+		return nil
+	}
+
+	// copy on write, the previous state may be referenced by Reports
+	props := make(map[string]dbus.Variant, len(s.devices[path])+len(changed))
+	for k, v := range s.devices[path] {
+		props[k] = v
+	}
+	names := []string{}
+	for k, v := range changed {
+		props[k] = v
+		names = append(names, k)
+	}
+	for _, k := range invalidated {
+		delete(props, k)
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	s.devices[path] = props
+
+	report := NewAdvertisementReport(path, props)
+	report.Changed = names
+	report.Timestamp = now
+
+	return report
+}
+
+// publish send report to the matching subscribers, it return true if
+// removals are waiting for room in a queue
+func (s *Scanner) publish(report *AdvertisementReport) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	waiting := false
+	for ch, sub := range s.subscribers {
+		if report.Removed {
+			if !sub.sent[report.Path] {
+				continue
+			}
+			sub.removed = append(sub.removed, report)
+			if !sub.flush(ch) {
+				waiting = true
+			}
+			continue
+		}
+		if sub.filter != nil && !sub.filter(report) {
+			continue
+		}
+		// reports are not sent ahead of the waiting removals
+		if !sub.flush(ch) {
+			waiting = true
+			log.Tracef("Scanner: subscriber queue full, dropped report for %s", report.Path)
+			continue
+		}
+		sub.sent[report.Path] = true
+		select {
+		case ch <- report:
+		default:
+			log.Tracef("Scanner: subscriber queue full, dropped report for %s", report.Path)
+		}
+	}
+	return waiting
+}
+
+// flushRemovals retry to send the waiting removals, it return true if some are left
+func (s *Scanner) flushRemovals() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	waiting := false
+	for ch, sub := range s.subscribers {
+		if !sub.flush(ch) {
+			waiting = true
+		}
+	}
+	return waiting
+}
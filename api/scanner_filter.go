@@ -0,0 +1,172 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/adapter"
+	log "github.com/sirupsen/logrus"
+)
+
+// And match if all the filters match, no filters matches all the reports
+func And(filters ...ScanFilter) ScanFilter {
+	return func(r *AdvertisementReport) bool {
+		for _, f := range filters {
+			if !f(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or match if at least one of the filters match, no filters matches none
+func Or(filters ...ScanFilter) ScanFilter {
+	return func(r *AdvertisementReport) bool {
+		for _, f := range filters {
+			if f(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not invert the filter
+func Not(filter ScanFilter) ScanFilter {
+	return func(r *AdvertisementReport) bool {
+		return !filter(r)
+	}
+}
+
+func normalizeAddress(address string) string {
+	return strings.NewReplacer(":", "", "-", "").Replace(strings.ToUpper(address))
+}
+
+// MatchAddressPrefix match the address starting with prefix, eg. an OUI
+// in the form 00:11:22. Separators and case are ignored
+func MatchAddressPrefix(prefix string) ScanFilter {
+	prefix = normalizeAddress(prefix)
+	return func(r *AdvertisementReport) bool {
+		return strings.HasPrefix(normalizeAddress(r.Address), prefix)
+	}
+}
+
+// MatchAddressType match the address type, public or random
+func MatchAddressType(addressType string) ScanFilter {
+	return func(r *AdvertisementReport) bool {
+		return r.AddressType == addressType
+	}
+}
+
+// MatchName match the device name with a regular expression
+func MatchName(expr string) (ScanFilter, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("name: %s", err)
+	}
+	return func(r *AdvertisementReport) bool {
+		return re.MatchString(r.Name)
+	}, nil
+}
+
+// MatchManufacturer match the manufacturer data of a company identifier.
+// The data is compared from the first byte, mask select the bits to compare,
+// a nil mask compares all the bits of data. A nil data matches the company only
+func MatchManufacturer(companyID uint16, data []byte, mask []byte) ScanFilter {
+	return func(r *AdvertisementReport) bool {
+		value, ok := r.ManufacturerData[companyID]
+		if !ok {
+			return false
+		}
+		return matchMask(value, data, mask)
+	}
+}
+
+// MatchServiceData match the service data of a 16, 32 or 128 bit service UUID
+func MatchServiceData(uuid string) ScanFilter {
+	uuid = bluez.ExpandUUID(uuid)
+	return func(r *AdvertisementReport) bool {
+		_, ok := r.ServiceData[uuid]
+		return ok
+	}
+}
+
+// MatchServiceUUID match the reports advertising a service UUID
+func MatchServiceUUID(uuid bluez.UUID) ScanFilter {
+	return func(r *AdvertisementReport) bool {
+		for _, u := range r.UUIDs {
+			if parsed, err := bluez.ParseUUID(u); err == nil && parsed == uuid {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchRSSI match the reports with an RSSI of at least rssi
+func MatchRSSI(rssi int16) ScanFilter {
+	return func(r *AdvertisementReport) bool {
+		return r.RSSI != 0 && r.RSSI >= rssi
+	}
+}
+
+func matchMask(value []byte, data []byte, mask []byte) bool {
+
+	if len(value) < len(data) {
+		return false
+	}
+
+	if mask == nil {
+		return bytes.Equal(value[:len(data)], data)
+	}
+
+	for i := range data {
+		m := byte(0xff)
+		if i < len(mask) {
+			m = mask[i]
+		}
+		if value[i]&m != data[i]&m {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ExtendedDiscoveryFilter combine the filter applied by Bluez with
+// a filter evaluated locally on the advertisement reports
+type ExtendedDiscoveryFilter struct {
+	adapter.DiscoveryFilter
+	// Match is evaluated locally, nil matches all the reports
+	Match ScanFilter
+}
+
+// NewExtendedDiscoveryFilter initialize a filter with the Bluez defaults and a local filter
+func NewExtendedDiscoveryFilter(match ScanFilter) *ExtendedDiscoveryFilter {
+	return &ExtendedDiscoveryFilter{
+		DiscoveryFilter: adapter.NewDiscoveryFilter(),
+		Match:           match,
+	}
+}
+
+// DiscoverWithFilter start a discovery session and return the advertisement reports matching the filter
+func DiscoverWithFilter(a *adapter.Adapter1, filter *ExtendedDiscoveryFilter) (chan *AdvertisementReport, func(), error) {
+
+	session, err := GetDiscoveryManager(a).Start(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cancel := func() {
+		err := session.Stop()
+		if err != nil {
+			log.Warnf("Error stopping discovery: %s", err)
+		}
+	}
+
+	return session.Reports(), cancel, nil
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFilterReport() *AdvertisementReport {
+	return &AdvertisementReport{
+		Address:     "AC:23:3F:01:02:03",
+		AddressType: "random",
+		Name:        "Sensor-42",
+		RSSI:        -65,
+		UUIDs:       []string{"0000feaa-0000-1000-8000-00805f9b34fb"},
+		ManufacturerData: map[uint16][]byte{
+			0x004c: {0x02, 0x15, 0xaa, 0xbb},
+		},
+		ServiceData: map[string][]byte{
+			"0000feaa-0000-1000-8000-00805f9b34fb": {0x10},
+		},
+	}
+}
+
+func TestScanFilterMatchers(t *testing.T) {
+
+	r := testFilterReport()
+
+	assert.True(t, MatchAddressPrefix("ac:23:3f")(r))
+	assert.True(t, MatchAddressPrefix("AC233F01")(r))
+	assert.False(t, MatchAddressPrefix("00:11:22")(r))
+
+	assert.True(t, MatchAddressType("random")(r))
+	assert.False(t, MatchAddressType("public")(r))
+
+	name, err := MatchName("^Sensor-[0-9]+$")
+	assert.NoError(t, err)
+	assert.True(t, name(r))
+	_, err = MatchName("(")
+	assert.Error(t, err)
+
+	assert.True(t, MatchManufacturer(0x004c, nil, nil)(r))
+	assert.True(t, MatchManufacturer(0x004c, []byte{0x02, 0x15}, nil)(r))
+	assert.False(t, MatchManufacturer(0x004c, []byte{0x02, 0x16}, nil)(r))
+	assert.True(t, MatchManufacturer(0x004c, []byte{0x02, 0x00, 0xa0}, []byte{0xff, 0x00, 0xf0})(r))
+	assert.False(t, MatchManufacturer(0x004c, []byte{0x02, 0x15, 0xaa, 0xbb, 0xcc}, nil)(r))
+	assert.False(t, MatchManufacturer(0x0059, nil, nil)(r))
+
+	assert.True(t, MatchServiceData("FEAA")(r))
+	assert.True(t, MatchServiceData("0000FEAA-0000-1000-8000-00805F9B34FB")(r))
+	assert.False(t, MatchServiceData("180f")(r))
+
+	assert.True(t, MatchServiceUUID(bluez.UUID16(0xfeaa))(r))
+	assert.False(t, MatchServiceUUID(bluez.UUID16(0x180f))(r))
+
+	assert.True(t, MatchRSSI(-70)(r))
+	assert.False(t, MatchRSSI(-60)(r))
+}
+
+func TestScanFilterExpressions(t *testing.T) {
+
+	r := testFilterReport()
+
+	yes := MatchAddressType("random")
+	no := MatchAddressType("public")
+
+	assert.True(t, And()(r))
+	assert.True(t, And(yes, yes)(r))
+	assert.False(t, And(yes, no)(r))
+
+	assert.False(t, Or()(r))
+	assert.True(t, Or(no, yes)(r))
+	assert.False(t, Or(no, no)(r))
+
+	assert.True(t, Not(no)(r))
+	assert.True(t, And(Or(no, yes), Not(And(yes, no)))(r))
+}
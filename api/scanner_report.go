@@ -0,0 +1,132 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// AdvertisementReport is a snapshot of the advertisement data of a device
+type AdvertisementReport struct {
+	// Path is the device object path
+	Path        dbus.ObjectPath
+	Address     string
+	AddressType string
+	Name        string
+	// RSSI is zero if the device is not in range
+	RSSI int16
+	// TxPower is valid only if HasTxPower is set
+	TxPower    int16
+	HasTxPower bool
+	UUIDs      []string
+	// ManufacturerData is keyed by company identifier
+	ManufacturerData map[uint16][]byte
+	// ServiceData is keyed by service UUID
+	ServiceData map[string][]byte
+	// Changed list the properties updated by the event which produced the report
+	Changed []string
+	// Removed is set when Bluez removed the device, the report
+	// carries the last known properties
+	Removed   bool
+	Timestamp time.Time
+}
+
+// HasChanged return true if one of the properties has been updated by the report event
+func (r *AdvertisementReport) HasChanged(names ...string) bool {
+	for _, changed := range r.Changed {
+		for _, name := range names {
+			if changed == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewAdvertisementReport create a report from the properties of a Device1 object
+func NewAdvertisementReport(path dbus.ObjectPath, props map[string]dbus.Variant) *AdvertisementReport {
+
+	report := &AdvertisementReport{
+		Path:             path,
+		ManufacturerData: map[uint16][]byte{},
+		ServiceData:      map[string][]byte{},
+		Changed:          []string{},
+		Timestamp:        time.Now(),
+	}
+
+	for name, variant := range props {
+		val := variant.Value()
+		switch name {
+		case "Address":
+			report.Address, _ = val.(string)
+		case "AddressType":
+			report.AddressType, _ = val.(string)
+		case "Name":
+			report.Name, _ = val.(string)
+		case "RSSI":
+			report.RSSI, _ = val.(int16)
+		case "TxPower":
+			report.TxPower, report.HasTxPower = val.(int16)
+		case "UUIDs":
+			report.UUIDs, _ = val.([]string)
+		case "ManufacturerData":
+			report.ManufacturerData = ParseManufacturerData(val)
+		case "ServiceData":
+			report.ServiceData = ParseServiceData(val)
+		}
+	}
+
+	return report
+}
+
+// ParseManufacturerData convert the ManufacturerData property to a map of byte slices
+func ParseManufacturerData(val interface{}) map[uint16][]byte {
+	res := map[uint16][]byte{}
+	switch m := val.(type) {
+	case map[uint16]dbus.Variant:
+		for id, v := range m {
+			if b, ok := variantBytes(v.Value()); ok {
+				res[id] = b
+			}
+		}
+	case map[uint16]interface{}:
+		for id, v := range m {
+			if b, ok := variantBytes(v); ok {
+				res[id] = b
+			}
+		}
+	}
+	return res
+}
+
+// ParseServiceData convert the ServiceData property to a map of byte slices
+// keyed by lower case UUID
+func ParseServiceData(val interface{}) map[string][]byte {
+	res := map[string][]byte{}
+	switch m := val.(type) {
+	case map[string]dbus.Variant:
+		for uuid, v := range m {
+			if b, ok := variantBytes(v.Value()); ok {
+				res[strings.ToLower(uuid)] = b
+			}
+		}
+	case map[string]interface{}:
+		for uuid, v := range m {
+			if b, ok := variantBytes(v); ok {
+				res[strings.ToLower(uuid)] = b
+			}
+		}
+	}
+	return res
+}
+
+func variantBytes(val interface{}) ([]byte, bool) {
+	switch v := val.(type) {
+	case []byte:
+		return v, true
+	case dbus.Variant:
+		return variantBytes(v.Value())
+	}
+	return nil, false
+}
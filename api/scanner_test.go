@@ -0,0 +1,230 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+	"github.com/stretchr/testify/assert"
+)
+
+const testScanDevicePath = dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55")
+
+func newTestScanner(t *testing.T) *Scanner {
+	return &Scanner{
+		path:        "/org/bluez/hci0",
+		devices:     make(map[dbus.ObjectPath]map[string]dbus.Variant),
+		subscribers: make(map[chan *AdvertisementReport]*scanSubscriber),
+	}
+}
+
+func TestNewAdvertisementReport(t *testing.T) {
+
+	report := NewAdvertisementReport(testScanDevicePath, map[string]dbus.Variant{
+		"Address":     dbus.MakeVariant("00:11:22:33:44:55"),
+		"AddressType": dbus.MakeVariant("random"),
+		"RSSI":        dbus.MakeVariant(int16(-60)),
+		"TxPower":     dbus.MakeVariant(int16(4)),
+		"ManufacturerData": dbus.MakeVariant(map[uint16]dbus.Variant{
+			0x004c: dbus.MakeVariant([]byte{0x02, 0x15}),
+		}),
+		"ServiceData": dbus.MakeVariant(map[string]dbus.Variant{
+			"0000FEAA-0000-1000-8000-00805F9B34FB": dbus.MakeVariant([]byte{0x10}),
+		}),
+	})
+
+	assert.Equal(t, "00:11:22:33:44:55", report.Address)
+	assert.Equal(t, "random", report.AddressType)
+	assert.Equal(t, int16(-60), report.RSSI)
+	assert.True(t, report.HasTxPower)
+	assert.Equal(t, int16(4), report.TxPower)
+	assert.Equal(t, []byte{0x02, 0x15}, report.ManufacturerData[0x004c])
+	assert.Equal(t, []byte{0x10}, report.ServiceData["0000feaa-0000-1000-8000-00805f9b34fb"])
+
+	report = NewAdvertisementReport(testScanDevicePath, map[string]dbus.Variant{})
+	assert.False(t, report.HasTxPower)
+}
+
+func TestScannerHandleSignal(t *testing.T) {
+
+	s := newTestScanner(t)
+	now := time.Now()
+
+	report := s.handleSignal(&dbus.Signal{
+		Name: bluez.InterfacesAdded,
+		Body: []interface{}{
+			testScanDevicePath,
+			map[string]map[string]dbus.Variant{
+				device.Device1Interface: {
+					"Address": dbus.MakeVariant("00:11:22:33:44:55"),
+					"RSSI":    dbus.MakeVariant(int16(-70)),
+				},
+			},
+		},
+	}, now)
+	assert.NotNil(t, report)
+	assert.Equal(t, int16(-70), report.RSSI)
+	assert.Equal(t, now, report.Timestamp)
+
+	report = s.handleSignal(&dbus.Signal{
+		Path: testScanDevicePath,
+		Name: bluez.PropertiesChanged,
+		Body: []interface{}{
+			device.Device1Interface,
+			map[string]dbus.Variant{
+				"RSSI": dbus.MakeVariant(int16(-50)),
+				"ManufacturerData": dbus.MakeVariant(map[uint16]dbus.Variant{
+					0x0059: dbus.MakeVariant([]byte{1, 2}),
+				}),
+			},
+			[]string{},
+		},
+	}, now)
+	assert.NotNil(t, report)
+	assert.Equal(t, "00:11:22:33:44:55", report.Address)
+	assert.Equal(t, int16(-50), report.RSSI)
+	assert.Equal(t, []byte{1, 2}, report.ManufacturerData[0x0059])
+	assert.Equal(t, []string{"ManufacturerData", "RSSI"}, report.Changed)
+	assert.True(t, report.HasChanged("RSSI"))
+
+	report = s.handleSignal(&dbus.Signal{
+		Path: testScanDevicePath,
+		Name: bluez.PropertiesChanged,
+		Body: []interface{}{
+			device.Device1Interface,
+			map[string]dbus.Variant{},
+			[]string{"RSSI"},
+		},
+	}, now)
+	assert.Equal(t, int16(0), report.RSSI)
+
+	// other adapters and interfaces are ignored
+	assert.Nil(t, s.handleSignal(&dbus.Signal{
+		Name: bluez.InterfacesAdded,
+		Body: []interface{}{
+			dbus.ObjectPath("/org/bluez/hci1/dev_00_11_22_33_44_66"),
+			map[string]map[string]dbus.Variant{
+				device.Device1Interface: {},
+			},
+		},
+	}, now))
+	assert.Nil(t, s.handleSignal(&dbus.Signal{
+		Path: testScanDevicePath,
+		Name: bluez.PropertiesChanged,
+		Body: []interface{}{"org.bluez.MediaControl1", map[string]dbus.Variant{}},
+	}, now))
+
+	assert.Len(t, s.Reports(nil), 1)
+
+	report = s.handleSignal(&dbus.Signal{
+		Name: bluez.InterfacesRemoved,
+		Body: []interface{}{testScanDevicePath, []string{device.Device1Interface}},
+	}, now)
+	assert.True(t, report.Removed)
+	assert.Equal(t, "00:11:22:33:44:55", report.Address)
+	assert.Len(t, s.Reports(nil), 0)
+
+	assert.Nil(t, s.handleSignal(&dbus.Signal{
+		Name: bluez.InterfacesRemoved,
+		Body: []interface{}{testScanDevicePath, []string{device.Device1Interface}},
+	}, now))
+}
+
+func TestScannerSubscribe(t *testing.T) {
+
+	s := newTestScanner(t)
+
+	all, cancelAll := s.Subscribe(nil)
+	near, cancelNear := s.Subscribe(func(r *AdvertisementReport) bool {
+		return r.RSSI > -60
+	})
+
+	s.publish(&AdvertisementReport{Path: testScanDevicePath, RSSI: -80})
+	s.publish(&AdvertisementReport{Path: testScanDevicePath, RSSI: -40})
+
+	assert.Equal(t, int16(-80), (<-all).RSSI)
+	assert.Equal(t, int16(-40), (<-all).RSSI)
+	assert.Equal(t, int16(-40), (<-near).RSSI)
+	assert.Len(t, near, 0)
+
+	cancelAll()
+	cancelAll()
+	_, ok := <-all
+	assert.False(t, ok)
+
+	cancelNear()
+}
+
+func TestScannerNotStarted(t *testing.T) {
+
+	s := newTestScanner(t)
+
+	assert.Error(t, s.SetFilter(nil))
+	assert.NoError(t, s.Stop())
+
+	filter := toExtendedDiscoveryFilter(nil)
+	assert.True(t, filter.DuplicateData)
+	assert.Nil(t, filter.Match)
+}
+
+func TestScannerSubscribeRemoved(t *testing.T) {
+
+	s := newTestScanner(t)
+
+	near, cancel := s.Subscribe(func(r *AdvertisementReport) bool {
+		return r.RSSI > -60
+	})
+	defer cancel()
+
+	other := dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_66")
+
+	s.publish(&AdvertisementReport{Path: testScanDevicePath, RSSI: -40})
+	s.publish(&AdvertisementReport{Path: other, RSSI: -80})
+	assert.Equal(t, testScanDevicePath, (<-near).Path)
+
+	// the removal of a sent device is delivered even if the filter fails
+	s.publish(&AdvertisementReport{Path: other, RSSI: -80, Removed: true})
+	s.publish(&AdvertisementReport{Path: testScanDevicePath, RSSI: -80, Removed: true})
+	r := <-near
+	assert.Equal(t, testScanDevicePath, r.Path)
+	assert.True(t, r.Removed)
+
+	// only once
+	s.publish(&AdvertisementReport{Path: testScanDevicePath, RSSI: -80, Removed: true})
+	assert.Len(t, near, 0)
+}
+
+func TestScannerSubscribeRemovedQueueFull(t *testing.T) {
+
+	size := ScannerBufferSize
+	ScannerBufferSize = 1
+	defer func() {
+		ScannerBufferSize = size
+	}()
+
+	s := newTestScanner(t)
+	ch, cancel := s.Subscribe(nil)
+	defer cancel()
+
+	other := dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_66")
+
+	assert.False(t, s.publish(&AdvertisementReport{Path: testScanDevicePath, RSSI: -40}))
+	assert.False(t, s.publish(&AdvertisementReport{Path: other, RSSI: -40}))
+
+	// the queue is full, the removal waits for room
+	assert.True(t, s.publish(&AdvertisementReport{Path: testScanDevicePath, Removed: true}))
+	assert.True(t, s.publish(&AdvertisementReport{Path: other, RSSI: -50}))
+	assert.True(t, s.flushRemovals())
+
+	assert.Equal(t, int16(-40), (<-ch).RSSI)
+	assert.False(t, s.flushRemovals())
+	r := <-ch
+	assert.Equal(t, testScanDevicePath, r.Path)
+	assert.True(t, r.Removed)
+
+	// delivered once
+	assert.False(t, s.publish(&AdvertisementReport{Path: testScanDevicePath, Removed: true}))
+	assert.Len(t, ch, 0)
+}
@@ -7,6 +7,17 @@ func (app *App) createAgent() (agent.Agent1Client, error) {
 	return a, nil
 }
 
+// SetAgent replace the agent exposed by the app, to be called before Run
+func (app *App) SetAgent(a agent.Agent1Client) {
+	app.agent = a
+}
+
+// SetServiceAuthorizer decide the AuthorizeService requests of the app agent
+// with the authorizer rules, to be called before Run
+func (app *App) SetServiceAuthorizer(authorizer *agent.ServiceAuthorizer) {
+	app.agent = agent.WithServiceAuthorizer(app.agent, authorizer)
+}
+
 // Expose app agent on DBus
 func (app *App) ExposeAgent(caps string, setAsDefaultAgent bool) error {
 	return agent.ExposeAgent(app.DBusConn(), app.agent, caps, setAsDefaultAgent)
@@ -40,6 +40,7 @@ func NewApp(adapterID string) (*App, error) {
 
 	app.adapterID = adapterID
 	app.services = make(map[dbus.ObjectPath]*Service)
+	app.defined = make(map[dbus.ObjectPath]bool)
 	app.path = dbus.ObjectPath(
 		fmt.Sprintf(
 			AppPath,
@@ -75,6 +76,8 @@ type App struct {
 	conn          *dbus.Conn
 	objectManager *api.DBusObjectManager
 	services      map[dbus.ObjectPath]*Service
+	// defined are the services added with AddDefinition
+	defined       map[dbus.ObjectPath]bool
 	advertisement *advertising.LEAdvertisement1Properties
 	gm            *gatt.GattManager1
 }
@@ -142,6 +145,11 @@ func (app *App) init() error {
 
 func (app *App) Run() (err error) {
 
+	err = app.validateDefined()
+	if err != nil {
+		return err
+	}
+
 	err = app.ExposeAgent(app.AgentCaps, app.AgentSetAsDefault)
 	if err != nil {
 		return fmt.Errorf("ExposeAgent: %s", err)
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+)
+
+// CharAuthorizeCallback authorize a request on a characteristic flagged
+// authorize, a non nil error deny the request
+type CharAuthorizeCallback func(c *Char, req *Request) error
+
+// DescrAuthorizeCallback authorize a request on a descriptor flagged
+// authorize, a non nil error deny the request
+type DescrAuthorizeCallback func(d *Descr, req *Request) error
+
+// Set the Authorize callback, called before reads and writes when the
+// characteristic is flagged authorize. Without a callback requests are allowed
+func (s *Char) OnAuthorize(fx CharAuthorizeCallback) *Char {
+	s.authorizeCallback = fx
+	return s
+}
+
+// Set the Authorize callback, called before reads and writes when the
+// descriptor is flagged authorize. Without a callback requests are allowed
+func (s *Descr) OnAuthorize(fx DescrAuthorizeCallback) *Descr {
+	s.authorizeCallback = fx
+	return s
+}
+
+func (s *Char) authorize(req *Request) *dbus.Error {
+	if s.authorizeCallback == nil || !hasFlag(s.Properties.Flags, gatt.FlagCharacteristicAuthorize) {
+		return nil
+	}
+	return authorizeError(s.authorizeCallback(s, req))
+}
+
+func (s *Descr) authorize(req *Request) *dbus.Error {
+	if s.authorizeCallback == nil || !hasFlag(s.Properties.Flags, gatt.FlagDescriptorAuthorize) {
+		return nil
+	}
+	return authorizeError(s.authorizeCallback(s, req))
+}
+
+// authorizeError return Bluez errors such as NotPermitted as is,
+// other errors are reported as NotAuthorized
+func authorizeError(err error) *dbus.Error {
+
+	if err == nil {
+		return nil
+	}
+
+	switch err.(type) {
+	case *dbus.Error, dbus.Error, *bluez.Error:
+		return toDBusError(err)
+	}
+
+	if strings.HasPrefix(err.Error(), bluezErrorPrefix) {
+		return toDBusError(err)
+	}
+
+	return &profile.ErrNotAuthorized
+}
@@ -2,17 +2,24 @@ package service
 
 import (
 	"fmt"
+	"os"
+	"sync"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/dimonzozo/go-bluetooth/api"
 	"github.com/dimonzozo/go-bluetooth/bluez"
 	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
-	log "github.com/sirupsen/logrus"
 )
 
 type CharReadCallback func(c *Char, options map[string]interface{}) ([]byte, error)
 type CharWriteCallback func(c *Char, value []byte) ([]byte, error)
 
+// CharReadRequestCallback return the value to read starting at req.Offset
+type CharReadRequestCallback func(c *Char, req *Request) ([]byte, error)
+
+// CharWriteRequestCallback receive the data written at req.Offset and return the new value
+type CharWriteRequestCallback func(c *Char, req *Request, value []byte) ([]byte, error)
+
 type Char struct {
 	ID      int
 	app     *App
@@ -26,6 +33,28 @@ type Char struct {
 
 	readCallback  CharReadCallback
 	writeCallback CharWriteCallback
+
+	readRequestCallback  CharReadRequestCallback
+	writeRequestCallback CharWriteRequestCallback
+
+	authorizeCallback     CharAuthorizeCallback
+	reliableWriteCallback CharReliableWriteCallback
+
+	transactionsLock sync.Mutex
+	transactions     map[dbus.ObjectPath]*WriteTransaction
+
+	subscribeCallback   CharNotifyCallback
+	unsubscribeCallback CharNotifyCallback
+	confirmCallback     CharNotifyCallback
+
+	acquireLock  sync.Mutex
+	writeSocket  *os.File
+	notifySocket *os.File
+	// writeRemote and notifyRemote are our copies of the ends sent to Bluez,
+	// kept open until Bluez is known to hold them
+	writeRemote      *os.File
+	notifyRemote     *os.File
+	notifyConfirming bool
 }
 
 func (s *Char) Path() dbus.ObjectPath {
@@ -147,14 +176,14 @@ func (s *Char) OnWrite(fx CharWriteCallback) *Char {
 	return s
 }
 
-// start notification session
-func (s *Char) StartNotify() *dbus.Error {
-	log.Debug("Char.StartNotify")
-	return nil
+// Set the Read callback receiving the request options, it takes precedence over OnRead
+func (s *Char) OnReadRequest(fx CharReadRequestCallback) *Char {
+	s.readRequestCallback = fx
+	return s
 }
 
-// stop notification session
-func (s *Char) StopNotify() *dbus.Error {
-	log.Debug("Char.StopNotify")
-	return nil
+// Set the Write callback receiving the request options, it takes precedence over OnWrite
+func (s *Char) OnWriteRequest(fx CharWriteRequestCallback) *Char {
+	s.writeRequestCallback = fx
+	return s
 }
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/godbus/dbus/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultAcquireMTU is used when Bluez does not provide the MTU
+const defaultAcquireMTU = 23
+
+func newSocketPair(name string) (*os.File, *os.File, error) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_SEQPACKET|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return os.NewFile(uintptr(fds[0]), name), os.NewFile(uintptr(fds[1]), name+":remote"), nil
+}
+
+// AcquireConfirmTimeout is how long a notification can stay unread on a
+// socket sent with AcquireNotify before the socket is considered released
+var AcquireConfirmTimeout = 5 * time.Second
+
+// acquirePollInterval is the delay between checks of the unread notifications
+const acquirePollInterval = 10 * time.Millisecond
+
+// The remote end of a socketpair is sent to Bluez in the reply of the acquire
+// call. godbus writes the reply after the handler returns and does not close the
+// file descriptors it sends, so our copy is kept open until Bluez is known to
+// hold the socket: a value is read from it or a notification written on it is
+// consumed. Closing our copy is then needed to detect when Bluez release it
+
+// socketInQueue return the size of the data waiting to be read on socket
+func socketInQueue(socket *os.File) (int, error) {
+	rc, err := socket.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	var errno syscall.Errno
+	err = rc.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCINQ, uintptr(unsafe.Pointer(&n)))
+	})
+	if err != nil {
+		return 0, err
+	}
+	if errno != 0 {
+		return 0, errno
+	}
+	return n, nil
+}
+
+// shutdownSocket unblock the readers and writers of socket
+func shutdownSocket(socket *os.File) error {
+	rc, err := socket.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	err = rc.Control(func(fd uintptr) {
+		serr = syscall.Shutdown(int(fd), syscall.SHUT_RDWR)
+	})
+	if err != nil {
+		return err
+	}
+	return serr
+}
+
+// closeRemote close our copy of the remote end if it is still current
+func closeRemote(current **os.File, remote *os.File) {
+	if remote != nil && *current == remote {
+		remote.Close()
+		*current = nil
+	}
+}
+
+func optionMTU(options map[string]interface{}) uint16 {
+	if mtu, ok := options["mtu"].(uint16); ok && mtu > 0 {
+		return mtu
+	}
+	return defaultAcquireMTU
+}
+
+func (s *Char) setAcquired(name string, acquired bool) {
+	s.Properties.Lock()
+	switch name {
+	case "WriteAcquired":
+		s.Properties.WriteAcquired = acquired
+	case "NotifyAcquired":
+		s.Properties.NotifyAcquired = acquired
+	}
+	s.Properties.Unlock()
+	if s.iprops.Instance() != nil {
+		s.iprops.Instance().SetMust(s.Interface(), name, acquired)
+	}
+}
+
+// AcquireWrite is called by Bluez to obtain a socket receiving the written values
+func (s *Char) AcquireWrite(options map[string]interface{}) (dbus.UnixFD, uint16, *dbus.Error) {
+	log.Debug("Char.AcquireWrite")
+
+	remote, mtu, err := s.acquireWrite(options)
+	if err != nil {
+		return 0, 0, dbus.MakeFailedError(err)
+	}
+
+	return dbus.UnixFD(remote.Fd()), mtu, nil
+}
+
+// acquireWrite return the socket end to send to Bluez, it stays open until
+// the first value is read
+func (s *Char) acquireWrite(options map[string]interface{}) (*os.File, uint16, error) {
+
+	mtu := optionMTU(options)
+
+	local, remote, err := newSocketPair(string(s.Path()) + ":write")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.acquireLock.Lock()
+	if s.writeSocket != nil {
+		s.writeSocket.Close()
+	}
+	closeRemote(&s.writeRemote, s.writeRemote)
+	s.writeSocket = local
+	s.writeRemote = remote
+	s.acquireLock.Unlock()
+
+	s.setAcquired("WriteAcquired", true)
+	go s.readAcquiredWrites(local, remote, mtu, options)
+
+	return remote, mtu, nil
+}
+
+func (s *Char) readAcquiredWrites(socket, remote *os.File, mtu uint16, acquireOptions map[string]interface{}) {
+
+	// values written on the socket are write without response
+	options := map[string]interface{}{}
+	for k, v := range acquireOptions {
+		options[k] = v
+	}
+	options["type"] = WriteTypeCommand
+
+	buf := make([]byte, mtu)
+	for {
+		n, err := socket.Read(buf)
+		if err != nil || n == 0 {
+			break
+		}
+		// Bluez wrote the value, it holds the socket
+		s.acquireLock.Lock()
+		closeRemote(&s.writeRemote, remote)
+		s.acquireLock.Unlock()
+
+		value := make([]byte, n)
+		copy(value, buf[:n])
+		if derr := s.WriteValue(value, options); derr != nil {
+			log.Warnf("Char.AcquireWrite: %s", derr)
+		}
+	}
+
+	s.acquireLock.Lock()
+	released := s.writeSocket == socket
+	if released {
+		s.writeSocket = nil
+		closeRemote(&s.writeRemote, remote)
+	}
+	s.acquireLock.Unlock()
+
+	socket.Close()
+
+	if released {
+		s.setAcquired("WriteAcquired", false)
+	}
+}
+
+// AcquireNotify is called by Bluez to obtain a socket to send notifications.
+// Bluez closes the socket when the client unsubscribe
+func (s *Char) AcquireNotify(options map[string]interface{}) (dbus.UnixFD, uint16, *dbus.Error) {
+	log.Debug("Char.AcquireNotify")
+
+	remote, mtu, err := s.acquireNotify(options)
+	if err != nil {
+		return 0, 0, dbus.MakeFailedError(err)
+	}
+
+	return dbus.UnixFD(remote.Fd()), mtu, nil
+}
+
+// acquireNotify return the socket end to send to Bluez, it stays open until
+// a notification is consumed
+func (s *Char) acquireNotify(options map[string]interface{}) (*os.File, uint16, error) {
+
+	mtu := optionMTU(options)
+
+	local, remote, err := newSocketPair(string(s.Path()) + ":notify")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.acquireLock.Lock()
+	if s.notifySocket != nil {
+		s.notifySocket.Close()
+	}
+	closeRemote(&s.notifyRemote, s.notifyRemote)
+	s.notifySocket = local
+	s.notifyRemote = remote
+	s.notifyConfirming = false
+	s.acquireLock.Unlock()
+
+	s.setAcquired("NotifyAcquired", true)
+	if s.setNotifying(true) && s.subscribeCallback != nil {
+		s.subscribeCallback(s)
+	}
+
+	go s.waitNotifyRelease(local, remote)
+
+	return remote, mtu, nil
+}
+
+// waitNotifyRelease wait for Bluez to close the socket
+func (s *Char) waitNotifyRelease(socket, remote *os.File) {
+
+	buf := make([]byte, 1)
+	for {
+		_, err := socket.Read(buf)
+		if err != nil {
+			break
+		}
+	}
+
+	s.acquireLock.Lock()
+	released := s.notifySocket == socket
+	if released {
+		s.notifySocket = nil
+		closeRemote(&s.notifyRemote, remote)
+	}
+	s.acquireLock.Unlock()
+
+	socket.Close()
+
+	if released {
+		s.setAcquired("NotifyAcquired", false)
+		if s.setNotifying(false) && s.unsubscribeCallback != nil {
+			s.unsubscribeCallback(s)
+		}
+	}
+}
+
+// writeNotifySocket send value on the acquired notify socket, if any
+func (s *Char) writeNotifySocket(value []byte) (bool, error) {
+	s.acquireLock.Lock()
+	defer s.acquireLock.Unlock()
+
+	if s.notifySocket == nil {
+		return false, nil
+	}
+
+	_, err := s.notifySocket.Write(value)
+	if err == nil && s.notifyRemote != nil && !s.notifyConfirming {
+		s.notifyConfirming = true
+		go s.confirmNotifySocket(s.notifySocket, s.notifyRemote)
+	}
+	return true, err
+}
+
+// confirmNotifySocket close our copy of the remote end once the notifications
+// are consumed by Bluez. If they are still unread after AcquireConfirmTimeout,
+// Bluez does not hold the socket and it is released
+func (s *Char) confirmNotifySocket(socket, remote *os.File) {
+
+	deadline := time.Now().Add(AcquireConfirmTimeout)
+	for {
+		n, err := socketInQueue(remote)
+		if err != nil {
+			// closed by a new acquire or the release
+			return
+		}
+		if n == 0 {
+			s.acquireLock.Lock()
+			closeRemote(&s.notifyRemote, remote)
+			s.acquireLock.Unlock()
+			return
+		}
+		if time.Now().After(deadline) {
+			log.Warnf("Char.AcquireNotify: notifications not read, releasing %s", socket.Name())
+			// wake up waitNotifyRelease and a blocked write
+			shutdownSocket(socket)
+			return
+		}
+		time.Sleep(acquirePollInterval)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+	"github.com/stretchr/testify/assert"
+)
+
+// receiveSocket duplicate the remote end as Bluez does when reading the reply
+func receiveSocket(t *testing.T, remote *os.File) *os.File {
+	fd, err := syscall.Dup(int(remote.Fd()))
+	assert.NoError(t, err)
+	return os.NewFile(uintptr(fd), remote.Name())
+}
+
+func TestCharAcquireWrite(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicWriteWithoutResponse)
+	written := make(chan []byte, 1)
+	c.OnWrite(func(c *Char, value []byte) ([]byte, error) {
+		written <- value
+		return value, nil
+	})
+
+	remote, mtu, err := c.acquireWrite(map[string]interface{}{"mtu": uint16(64)})
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(64), mtu)
+	assert.True(t, c.Properties.WriteAcquired)
+
+	// the remote end is kept open after the handler returns
+	time.Sleep(50 * time.Millisecond)
+	_, err = socketInQueue(remote)
+	assert.NoError(t, err)
+
+	f := receiveSocket(t, remote)
+	_, err = f.Write([]byte{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, <-written)
+
+	f.Close()
+	time.Sleep(50 * time.Millisecond)
+	c.Properties.Lock()
+	assert.False(t, c.Properties.WriteAcquired)
+	c.Properties.Unlock()
+}
+
+func TestCharAcquireNotify(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicNotify)
+	unsubscribed := make(chan bool, 1)
+	c.OnUnsubscribe(func(c *Char) {
+		unsubscribed <- true
+	})
+
+	remote, _, err := c.acquireNotify(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.True(t, c.IsNotifying())
+
+	f := receiveSocket(t, remote)
+	assert.NoError(t, c.Notify([]byte{42}))
+
+	buf := make([]byte, defaultAcquireMTU)
+	n, err := f.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{42}, buf[:n])
+
+	f.Close()
+	select {
+	case <-unsubscribed:
+	case <-time.After(time.Second):
+		t.Fatal("unsubscribe not detected")
+	}
+	assert.False(t, c.IsNotifying())
+}
+
+func TestCharAcquireNotifyUnread(t *testing.T) {
+
+	timeout := AcquireConfirmTimeout
+	AcquireConfirmTimeout = 100 * time.Millisecond
+	defer func() {
+		AcquireConfirmTimeout = timeout
+	}()
+
+	c := newTestChar(t, gatt.FlagCharacteristicNotify)
+	unsubscribed := make(chan bool, 1)
+	c.OnUnsubscribe(func(c *Char) {
+		unsubscribed <- true
+	})
+
+	// Bluez did not receive the socket, the notification is never read
+	_, _, err := c.acquireNotify(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.NoError(t, c.Notify([]byte{42}))
+
+	select {
+	case <-unsubscribed:
+	case <-time.After(time.Second):
+		t.Fatal("release not detected")
+	}
+	assert.False(t, c.IsNotifying())
+	c.Properties.Lock()
+	assert.False(t, c.Properties.NotifyAcquired)
+	c.Properties.Unlock()
+}
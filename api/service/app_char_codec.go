@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt/codec"
+	log "github.com/sirupsen/logrus"
+)
+
+// CharReadValueCallback return the typed value of the characteristic
+type CharReadValueCallback func(c *Char, req *Request) (codec.Value, error)
+
+// CharWriteValueCallback receive the written value decoded with the codec
+// of the characteristic UUID
+type CharWriteValueCallback func(c *Char, req *Request, value codec.Value) error
+
+// OnReadValue set a read callback returning a typed value, encoded by its codec
+func (s *Char) OnReadValue(fx CharReadValueCallback) *Char {
+	return s.OnReadRequest(func(c *Char, req *Request) ([]byte, error) {
+		v, err := fx(c, req)
+		if err != nil {
+			return nil, err
+		}
+		b, err := v.MarshalGATT()
+		if err != nil {
+			return nil, err
+		}
+		b, derr := readAt(b, req.Offset)
+		if derr != nil {
+			return nil, derr
+		}
+		return b, nil
+	})
+}
+
+// OnWriteValue set a write callback receiving the value decoded with the
+// codec registered for the characteristic UUID. The decoded value is the
+// stored value up to the offset followed by the written data, the bytes
+// after it are not decoded. Values which can not be decoded are rejected
+// with InvalidValueLength
+func (s *Char) OnWriteValue(fx CharWriteValueCallback) *Char {
+	return s.OnWriteRequest(func(c *Char, req *Request, data []byte) ([]byte, error) {
+
+		c.Properties.Lock()
+		b, derr := writeAt(c.Properties.Value, data, req.Offset)
+		c.Properties.Unlock()
+		if derr != nil {
+			return nil, derr
+		}
+
+		v, err := c.newValue()
+		if err != nil {
+			return nil, err
+		}
+
+		err = v.UnmarshalGATT(b)
+		if err != nil {
+			log.Debugf("Char.WriteValue %s: %s", c.Path(), err)
+			return nil, &profile.ErrInvalidValueLength
+		}
+
+		err = fx(c, req, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return b, nil
+	})
+}
+
+// StoreValue encode a typed value and store it as the characteristic value
+func (s *Char) StoreValue(v codec.Value) error {
+	b, err := v.MarshalGATT()
+	if err != nil {
+		return err
+	}
+	s.setValue(b)
+	return nil
+}
+
+// NotifyValue encode a typed value and notify it, see Notify
+func (s *Char) NotifyValue(v codec.Value) error {
+	b, err := v.MarshalGATT()
+	if err != nil {
+		return err
+	}
+	return s.Notify(b)
+}
+
+// newValue return an empty value from the codec of the characteristic UUID
+func (s *Char) newValue() (codec.Value, error) {
+	uuid, err := bluez.ParseUUID(s.Properties.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("Char %s: %s", s.Path(), err)
+	}
+	return codec.New(uuid)
+}
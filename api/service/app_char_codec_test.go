@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dimonzozo/go-bluetooth/bluez/profile"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharReadValueCodec(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicRead)
+	c.OnReadValue(func(c *Char, req *Request) (codec.Value, error) {
+		level := codec.BatteryLevel(87)
+		return &level, nil
+	})
+
+	b, derr := c.ReadValue(map[string]interface{}{})
+	assert.Nil(t, derr)
+	assert.Equal(t, []byte{87}, b)
+
+	c.OnReadValue(func(c *Char, req *Request) (codec.Value, error) {
+		level := codec.BatteryLevel(101)
+		return &level, nil
+	})
+	_, derr = c.ReadValue(map[string]interface{}{})
+	assert.NotNil(t, derr)
+}
+
+func TestCharWriteValueCodec(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicWrite)
+
+	var written codec.Value
+	c.OnWriteValue(func(c *Char, req *Request, value codec.Value) error {
+		written = value
+		if *value.(*codec.BatteryLevel) > 100 {
+			return errors.New("org.bluez.Error.InvalidArguments")
+		}
+		return nil
+	})
+
+	derr := c.WriteValue([]byte{42}, map[string]interface{}{})
+	assert.Nil(t, derr)
+	assert.Equal(t, codec.BatteryLevel(42), *written.(*codec.BatteryLevel))
+	assert.Equal(t, []byte{42}, c.Properties.Value)
+
+	derr = c.WriteValue([]byte{200}, map[string]interface{}{})
+	assert.Equal(t, profile.ErrInvalidArguments.Name, derr.Name)
+
+	c.Properties.Value = nil
+	derr = c.WriteValue([]byte{}, map[string]interface{}{})
+	assert.Equal(t, profile.ErrInvalidValueLength.Name, derr.Name)
+}
+
+func TestCharWriteValueCodecShorter(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicWrite)
+	c.Properties.UUID = codec.DeviceNameUUID.String()
+	c.Properties.Value = []byte("long name")
+
+	var written codec.Value
+	c.OnWriteValue(func(c *Char, req *Request, value codec.Value) error {
+		written = value
+		return nil
+	})
+
+	// the stale bytes of the previous value are not decoded
+	derr := c.WriteValue([]byte("abc"), map[string]interface{}{})
+	assert.Nil(t, derr)
+	assert.Equal(t, codec.String("abc"), *written.(*codec.String))
+	assert.Equal(t, []byte("abc"), c.Properties.Value)
+
+	derr = c.WriteValue([]byte("de"), map[string]interface{}{"offset": uint16(1)})
+	assert.Nil(t, derr)
+	assert.Equal(t, codec.String("ade"), *written.(*codec.String))
+}
+
+func TestCharStoreValue(t *testing.T) {
+	c := newTestChar(t, gatt.FlagCharacteristicRead)
+	level := codec.BatteryLevel(12)
+	assert.NoError(t, c.StoreValue(&level))
+	assert.Equal(t, []byte{12}, c.Properties.Value)
+}
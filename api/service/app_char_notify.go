@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+	log "github.com/sirupsen/logrus"
+)
+
+// CharNotifyCallback is called on subscription changes and indication confirmations
+type CharNotifyCallback func(c *Char)
+
+// Set the Subscribe callback, called when a client enable notifications or indications
+func (s *Char) OnSubscribe(fx CharNotifyCallback) *Char {
+	s.subscribeCallback = fx
+	return s
+}
+
+// Set the Unsubscribe callback, called when a client disable notifications or indications
+func (s *Char) OnUnsubscribe(fx CharNotifyCallback) *Char {
+	s.unsubscribeCallback = fx
+	return s
+}
+
+// Set the Confirm callback, called when a client acknowledge an indication
+func (s *Char) OnConfirm(fx CharNotifyCallback) *Char {
+	s.confirmCallback = fx
+	return s
+}
+
+// IsNotifying return true if a client is subscribed to notifications or indications
+func (s *Char) IsNotifying() bool {
+	s.Properties.Lock()
+	defer s.Properties.Unlock()
+	return s.Properties.Notifying
+}
+
+func (s *Char) setNotifying(notifying bool) bool {
+	s.Properties.Lock()
+	changed := s.Properties.Notifying != notifying
+	s.Properties.Notifying = notifying
+	s.Properties.Unlock()
+
+	if changed && s.iprops.Instance() != nil {
+		s.iprops.Instance().SetMust(s.Interface(), "Notifying", notifying)
+	}
+
+	return changed
+}
+
+// start notification session
+func (s *Char) StartNotify() *dbus.Error {
+	log.Debug("Char.StartNotify")
+
+	if !hasFlag(s.Properties.Flags, gatt.FlagCharacteristicNotify, gatt.FlagCharacteristicIndicate) {
+		return &profile.ErrNotSupported
+	}
+
+	if s.setNotifying(true) && s.subscribeCallback != nil {
+		s.subscribeCallback(s)
+	}
+
+	return nil
+}
+
+// stop notification session
+func (s *Char) StopNotify() *dbus.Error {
+	log.Debug("Char.StopNotify")
+
+	if s.setNotifying(false) && s.unsubscribeCallback != nil {
+		s.unsubscribeCallback(s)
+	}
+
+	return nil
+}
+
+// Confirm is called by Bluez when a client acknowledge an indication
+func (s *Char) Confirm() *dbus.Error {
+	log.Debug("Char.Confirm")
+	if s.confirmCallback != nil {
+		s.confirmCallback(s)
+	}
+	return nil
+}
+
+// Notify update the characteristic value and send it to the subscribed
+// clients as a notification or indication, using the acquired notify socket
+// if available. The value is only stored when no client is subscribed
+func (s *Char) Notify(value []byte) error {
+
+	s.Properties.Lock()
+	s.Properties.Value = value
+	notifying := s.Properties.Notifying
+	s.Properties.Unlock()
+
+	if !notifying {
+		return nil
+	}
+
+	if acquired, err := s.writeNotifySocket(value); acquired {
+		return err
+	}
+
+	if s.iprops.Instance() == nil {
+		return errors.New("Characteristic is not exposed")
+	}
+
+	err := s.iprops.Instance().Set(s.Interface(), "Value", dbus.MakeVariant(value))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
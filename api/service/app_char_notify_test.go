@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dimonzozo/go-bluetooth/api"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestChar(t *testing.T, flags ...string) *Char {
+	iprops, err := api.NewDBusProperties(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Char{
+		Properties: NewGattCharacteristic1Properties("00002A19-0000-1000-8000-00805F9B34FB"),
+		iprops:     iprops,
+	}
+	c.Properties.Flags = flags
+	return c
+}
+
+func TestCharNotifySubscription(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicRead)
+	assert.NotNil(t, c.StartNotify())
+
+	c = newTestChar(t, gatt.FlagCharacteristicRead, gatt.FlagCharacteristicNotify)
+
+	subscribed := 0
+	unsubscribed := 0
+	c.OnSubscribe(func(c *Char) {
+		subscribed++
+	}).OnUnsubscribe(func(c *Char) {
+		unsubscribed++
+	})
+
+	// not subscribed, the value is only stored
+	assert.NoError(t, c.Notify([]byte{1}))
+	assert.Equal(t, []byte{1}, c.Properties.Value)
+
+	assert.Nil(t, c.StartNotify())
+	assert.Nil(t, c.StartNotify())
+	assert.True(t, c.IsNotifying())
+	assert.Equal(t, 1, subscribed)
+
+	assert.Nil(t, c.StopNotify())
+	assert.False(t, c.IsNotifying())
+	assert.Equal(t, 1, unsubscribed)
+}
+
+func TestCharConfirm(t *testing.T) {
+	c := newTestChar(t, gatt.FlagCharacteristicIndicate)
+	confirmed := false
+	c.OnConfirm(func(c *Char) {
+		confirmed = true
+	})
+	assert.Nil(t, c.Confirm())
+	assert.True(t, confirmed)
+}
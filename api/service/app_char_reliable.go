@@ -0,0 +1,209 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+	"github.com/godbus/dbus/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+// PreparedWrite is a write queued in a reliable write transaction
+type PreparedWrite struct {
+	Offset uint16
+	Value  []byte
+}
+
+// WriteTransaction group the reliable writes of a remote device. Bluez queue
+// the prepared writes and send them once the client execute the queue, the
+// contiguous writes of a characteristic in a single write of type reliable
+type WriteTransaction struct {
+	Device dbus.ObjectPath
+	Writes []PreparedWrite
+
+	// quit is closed when the transaction is committed or discarded
+	quit chan struct{}
+}
+
+// Apply return a copy of value with the prepared writes applied in order
+func (tx *WriteTransaction) Apply(value []byte) ([]byte, error) {
+	res := value
+	for _, w := range tx.Writes {
+		b, derr := writeAt(res, w.Value, w.Offset)
+		if derr != nil {
+			return nil, derr
+		}
+		res = b
+	}
+	return res, nil
+}
+
+// CharReliableWriteCallback commit a transaction and return the new value.
+// When an error is returned the transaction is discarded
+type CharReliableWriteCallback func(c *Char, tx *WriteTransaction) ([]byte, error)
+
+// Set the ReliableWrite callback, called when a transaction is committed.
+// Without a callback the writes are applied to the current value
+func (s *Char) OnReliableWrite(fx CharReliableWriteCallback) *Char {
+	s.reliableWriteCallback = fx
+	return s
+}
+
+// executeWrite commit the writes executed by the client. The commit error is
+// returned to Bluez, which abort the execute and report it to the client.
+// The writes queued on prepare authorization are superseded
+func (s *Char) executeWrite(req *Request, value []byte) *dbus.Error {
+
+	s.DiscardWrites(req.Device)
+
+	tx := &WriteTransaction{
+		Device: req.Device,
+		Writes: []PreparedWrite{newPreparedWrite(req, value)},
+	}
+
+	if err := s.commitTransaction(tx); err != nil {
+		return toDBusError(err)
+	}
+
+	return nil
+}
+
+func newPreparedWrite(req *Request, value []byte) PreparedWrite {
+	data := make([]byte, len(value))
+	copy(data, value)
+	return PreparedWrite{
+		Offset: req.Offset,
+		Value:  data,
+	}
+}
+
+// prepareWrite queue a write Bluez ask to authorize before it is executed.
+// The transaction is discarded if the device disconnects
+func (s *Char) prepareWrite(req *Request, value []byte) {
+
+	s.transactionsLock.Lock()
+
+	if s.transactions == nil {
+		s.transactions = make(map[dbus.ObjectPath]*WriteTransaction)
+	}
+
+	tx, ok := s.transactions[req.Device]
+	if !ok {
+		tx = &WriteTransaction{
+			Device: req.Device,
+			quit:   make(chan struct{}),
+		}
+		s.transactions[req.Device] = tx
+	}
+
+	tx.Writes = append(tx.Writes, newPreparedWrite(req, value))
+
+	s.transactionsLock.Unlock()
+
+	if !ok {
+		s.watchDisconnect(tx)
+	}
+}
+
+// watchDisconnect discard the transaction when the device disconnects
+func (s *Char) watchDisconnect(tx *WriteTransaction) {
+
+	dev, err := device.NewDevice1(tx.Device)
+	if err != nil {
+		log.Debugf("Char.ReliableWrite %s: %s", tx.Device, err)
+		return
+	}
+
+	props, err := dev.WatchProperties()
+	if err != nil {
+		log.Debugf("Char.ReliableWrite %s: %s", tx.Device, err)
+		return
+	}
+
+	go s.discardOnDisconnect(tx, props, func() {
+		dev.UnwatchProperties(props)
+	})
+}
+
+// discardOnDisconnect read props until it is closed, unwatch is called once
+// the transaction ends
+func (s *Char) discardOnDisconnect(tx *WriteTransaction, props chan *bluez.PropertyChanged, unwatch func()) {
+	quit := tx.quit
+	for {
+		select {
+		case prop := <-props:
+			if prop == nil {
+				return
+			}
+			if connected, ok := prop.Value.(bool); ok && prop.Name == "Connected" && !connected {
+				log.Debugf("Char.ReliableWrite %s: device disconnected, writes discarded", tx.Device)
+				s.takeTransaction(tx.Device, tx)
+			}
+		case <-quit:
+			quit = nil
+			go unwatch()
+		}
+	}
+}
+
+// takeTransaction remove the transaction of a device, if expected is not nil
+// the transaction is removed only if it is still the pending one
+func (s *Char) takeTransaction(device dbus.ObjectPath, expected *WriteTransaction) *WriteTransaction {
+	s.transactionsLock.Lock()
+	defer s.transactionsLock.Unlock()
+
+	tx, ok := s.transactions[device]
+	if !ok || (expected != nil && tx != expected) {
+		return nil
+	}
+
+	delete(s.transactions, device)
+	close(tx.quit)
+
+	return tx
+}
+
+func (s *Char) commitTransaction(tx *WriteTransaction) error {
+
+	var val []byte
+	var err error
+	if s.reliableWriteCallback != nil {
+		val, err = s.reliableWriteCallback(s, tx)
+	} else {
+		s.Properties.Lock()
+		val, err = tx.Apply(s.Properties.Value)
+		s.Properties.Unlock()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.setValue(val)
+	return nil
+}
+
+// HasPendingWrites return true if prepared writes of device are pending
+func (s *Char) HasPendingWrites(device dbus.ObjectPath) bool {
+	s.transactionsLock.Lock()
+	defer s.transactionsLock.Unlock()
+	_, ok := s.transactions[device]
+	return ok
+}
+
+// CommitWrites apply the pending prepared writes of device at once.
+// The value is unchanged if an error is returned
+func (s *Char) CommitWrites(device dbus.ObjectPath) error {
+	tx := s.takeTransaction(device, nil)
+	if tx == nil {
+		return errors.New("No pending writes")
+	}
+	return s.commitTransaction(tx)
+}
+
+// DiscardWrites drop the pending prepared writes of device, it is called
+// when the device disconnects
+func (s *Char) DiscardWrites(device dbus.ObjectPath) {
+	s.takeTransaction(device, nil)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDevicePath = dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55")
+
+func reliableOptions(offset uint16) map[string]interface{} {
+	return map[string]interface{}{
+		"device": testDevicePath,
+		"offset": offset,
+		"type":   WriteTypeReliable,
+	}
+}
+
+func prepareOptions(offset uint16) map[string]interface{} {
+	return map[string]interface{}{
+		"device":            testDevicePath,
+		"offset":            offset,
+		"prepare-authorize": true,
+	}
+}
+
+func TestCharReliableWrite(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicWrite, gatt.FlagCharacteristicReliableWrite)
+	c.Properties.Value = []byte{0, 0, 0, 0}
+
+	// the executed writes are committed at once
+	assert.Nil(t, c.WriteValue([]byte{1, 2}, reliableOptions(0)))
+	assert.Nil(t, c.WriteValue([]byte{3, 4, 5}, reliableOptions(2)))
+	assert.False(t, c.HasPendingWrites(testDevicePath))
+	assert.Equal(t, []byte{1, 2, 3, 4, 5}, c.Properties.Value)
+
+	// the commit error is returned to Bluez
+	derr := c.WriteValue([]byte{8}, reliableOptions(20))
+	assert.NotNil(t, derr)
+	assert.Equal(t, "org.bluez.Error.InvalidOffset", derr.Name)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5}, c.Properties.Value)
+}
+
+func TestCharPreparedWrite(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicWrite, gatt.FlagCharacteristicReliableWrite)
+	c.Properties.Value = []byte{0, 0, 0, 0}
+
+	assert.Nil(t, c.WriteValue([]byte{1, 2}, prepareOptions(0)))
+	assert.Nil(t, c.WriteValue([]byte{3, 4, 5}, prepareOptions(2)))
+
+	// nothing is written until commit
+	assert.True(t, c.HasPendingWrites(testDevicePath))
+	assert.Equal(t, []byte{0, 0, 0, 0}, c.Properties.Value)
+
+	assert.NoError(t, c.CommitWrites(testDevicePath))
+	assert.False(t, c.HasPendingWrites(testDevicePath))
+	assert.Equal(t, []byte{1, 2, 3, 4, 5}, c.Properties.Value)
+
+	assert.Nil(t, c.WriteValue([]byte{9}, prepareOptions(0)))
+	c.DiscardWrites(testDevicePath)
+	assert.Error(t, c.CommitWrites(testDevicePath))
+	assert.Equal(t, []byte{1, 2, 3, 4, 5}, c.Properties.Value)
+
+	// an invalid offset discard the whole transaction
+	assert.Nil(t, c.WriteValue([]byte{7}, prepareOptions(0)))
+	assert.Nil(t, c.WriteValue([]byte{8}, prepareOptions(20)))
+	assert.Error(t, c.CommitWrites(testDevicePath))
+	assert.Equal(t, []byte{1, 2, 3, 4, 5}, c.Properties.Value)
+
+	// the executed writes supersede the prepared ones
+	assert.Nil(t, c.WriteValue([]byte{7}, prepareOptions(0)))
+	assert.Nil(t, c.WriteValue([]byte{6}, reliableOptions(0)))
+	assert.False(t, c.HasPendingWrites(testDevicePath))
+	assert.Equal(t, []byte{6}, c.Properties.Value)
+}
+
+func TestCharReliableWriteCallback(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicWrite, gatt.FlagCharacteristicReliableWrite)
+
+	c.OnReliableWrite(func(c *Char, tx *WriteTransaction) ([]byte, error) {
+		if len(tx.Writes) > 2 {
+			return nil, errors.New("too many writes")
+		}
+		return tx.Apply(nil)
+	})
+
+	assert.Nil(t, c.WriteValue([]byte{1}, prepareOptions(0)))
+	assert.Nil(t, c.WriteValue([]byte{2}, prepareOptions(1)))
+	assert.NoError(t, c.CommitWrites(testDevicePath))
+	assert.Equal(t, []byte{1, 2}, c.Properties.Value)
+
+	assert.Nil(t, c.WriteValue([]byte{1}, prepareOptions(0)))
+	assert.Nil(t, c.WriteValue([]byte{1}, prepareOptions(1)))
+	assert.Nil(t, c.WriteValue([]byte{1}, prepareOptions(2)))
+	assert.Error(t, c.CommitWrites(testDevicePath))
+	assert.Equal(t, []byte{1, 2}, c.Properties.Value)
+
+	assert.Nil(t, c.WriteValue([]byte{3}, reliableOptions(0)))
+	assert.Equal(t, []byte{3}, c.Properties.Value)
+}
+
+func TestCharReliableWriteDisconnect(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicWrite, gatt.FlagCharacteristicReliableWrite)
+
+	assert.Nil(t, c.WriteValue([]byte{1, 2}, prepareOptions(0)))
+	tx := c.transactions[testDevicePath]
+
+	props := make(chan *bluez.PropertyChanged)
+	unwatched := make(chan bool)
+	go func() {
+		c.discardOnDisconnect(tx, props, func() {
+			props <- nil
+		})
+		unwatched <- true
+	}()
+
+	props <- &bluez.PropertyChanged{Name: "RSSI", Value: int16(-60)}
+	assert.True(t, c.HasPendingWrites(testDevicePath))
+
+	props <- &bluez.PropertyChanged{Name: "Connected", Value: false}
+	select {
+	case <-unwatched:
+	case <-time.After(time.Second):
+		t.Fatal("transaction not discarded")
+	}
+	assert.False(t, c.HasPendingWrites(testDevicePath))
+	assert.Nil(t, c.Properties.Value)
+}
+
+func TestCharAuthorize(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicRead, gatt.FlagCharacteristicWrite)
+	c.Properties.Value = []byte{1}
+
+	c.OnAuthorize(func(c *Char, req *Request) error {
+		if req.Device != testDevicePath {
+			return errors.New("unknown device")
+		}
+		if req.Type == WriteTypeCommand {
+			return profile.ErrNotPermitted
+		}
+		return nil
+	})
+
+	// not flagged authorize, the callback is not used
+	_, derr := c.ReadValue(map[string]interface{}{})
+	assert.Nil(t, derr)
+
+	c.Properties.Flags = append(c.Properties.Flags, gatt.FlagCharacteristicAuthorize)
+
+	_, derr = c.ReadValue(map[string]interface{}{})
+	assert.Equal(t, "org.bluez.Error.NotAuthorized", derr.Name)
+
+	b, derr := c.ReadValue(map[string]interface{}{"device": testDevicePath})
+	assert.Nil(t, derr)
+	assert.Equal(t, []byte{1}, b)
+
+	derr = c.WriteValue([]byte{2}, map[string]interface{}{"device": testDevicePath, "type": WriteTypeCommand})
+	assert.Equal(t, "org.bluez.Error.NotPermitted", derr.Name)
+
+	// prepare authorization does not write the value
+	derr = c.WriteValue([]byte{}, map[string]interface{}{"device": testDevicePath, "prepare-authorize": true})
+	assert.Nil(t, derr)
+	assert.Equal(t, []byte{1}, c.Properties.Value)
+
+	derr = c.WriteValue([]byte{2}, map[string]interface{}{"device": testDevicePath, "type": WriteTypeRequest})
+	assert.Nil(t, derr)
+	assert.Equal(t, []byte{2}, c.Properties.Value)
+}
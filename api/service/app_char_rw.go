@@ -2,6 +2,7 @@ package service
 
 import (
 	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -9,15 +10,31 @@ import (
 func (s *Char) ReadValue(options map[string]interface{}) ([]byte, *dbus.Error) {
 
 	log.Debug("Characteristic.ReadValue")
+
+	req := NewRequest(options)
+	if derr := s.authorize(req); derr != nil {
+		return nil, derr
+	}
+
+	if s.readRequestCallback != nil {
+		b, err := s.readRequestCallback(s, req)
+		if err != nil {
+			return nil, toDBusError(err)
+		}
+		return b, nil
+	}
+
 	if s.readCallback != nil {
 		b, err := s.readCallback(s, options)
 		if err != nil {
-			return nil, dbus.MakeFailedError(err)
+			return nil, toDBusError(err)
 		}
 		return b, nil
 	}
 
-	return s.Properties.Value, nil
+	s.Properties.Lock()
+	defer s.Properties.Unlock()
+	return readAt(s.Properties.Value, req.Offset)
 }
 
 //WriteValue write a value
@@ -25,21 +42,65 @@ func (s *Char) WriteValue(value []byte, options map[string]interface{}) *dbus.Er
 
 	log.Trace("Characteristic.WriteValue")
 
-	val := value
-	if s.writeCallback != nil {
+	req := NewRequest(options)
+	if derr := s.authorize(req); derr != nil {
+		return derr
+	}
+
+	reliable := hasFlag(s.Properties.Flags, gatt.FlagCharacteristicReliableWrite)
+
+	// prepare write authorization, the value is written on execute
+	if req.PrepareAuthorize {
+		if reliable {
+			log.Trace("Queue prepared write")
+			s.prepareWrite(req, value)
+		}
+		return nil
+	}
+
+	if req.Type == WriteTypeReliable && reliable {
+		log.Trace("Execute reliable write")
+		return s.executeWrite(req, value)
+	}
+
+	var val []byte
+	if s.writeRequestCallback != nil {
+		log.Trace("Used write request callback")
+		b, err := s.writeRequestCallback(s, req, value)
+		if err != nil {
+			return toDBusError(err)
+		}
+		val = b
+	} else if s.writeCallback != nil {
 		log.Trace("Used write callback")
 		b, err := s.writeCallback(s, value)
-		val = b
 		if err != nil {
-			return dbus.MakeFailedError(err)
+			return toDBusError(err)
 		}
+		val = b
 	} else {
 		log.Trace("Store directly to value (no callback)")
+		s.Properties.Lock()
+		b, derr := writeAt(s.Properties.Value, value, req.Offset)
+		s.Properties.Unlock()
+		if derr != nil {
+			return derr
+		}
+		val = b
 	}
 
-	// TODO update on Properties interface
-	s.Properties.Value = val
-	s.iprops.Instance().Set(s.Interface(), "Value", dbus.MakeVariant(value))
+	s.setValue(val)
 
 	return nil
 }
+
+// setValue store the value and update the Value property
+func (s *Char) setValue(val []byte) {
+	s.Properties.Lock()
+	s.Properties.Value = val
+	s.Properties.Unlock()
+
+	if s.iprops.Instance() != nil {
+		s.iprops.Instance().Set(s.Interface(), "Value", dbus.MakeVariant(val))
+	}
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/api"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// AppDefinition describe a GATT database to expose with App.AddDefinition.
+// It can be built in code or loaded from a YAML or JSON document
+type AppDefinition struct {
+	Services []ServiceDefinition `json:"services" yaml:"services"`
+
+	// CharHandlers resolve the characteristic handlers referenced by name
+	CharHandlers map[string]CharHandler `json:"-" yaml:"-"`
+	// DescrHandlers resolve the descriptor handlers referenced by name
+	DescrHandlers map[string]DescrHandler `json:"-" yaml:"-"`
+}
+
+// ServiceDefinition describe a GATT service
+type ServiceDefinition struct {
+	// ID is an optional reference used by Includes, defaults to UUID
+	ID   string     `json:"id" yaml:"id"`
+	UUID bluez.UUID `json:"uuid" yaml:"uuid"`
+	// Secondary mark the service as not primary
+	Secondary bool `json:"secondary" yaml:"secondary"`
+	// Includes list the ID of the included services
+	Includes        []string         `json:"includes" yaml:"includes"`
+	Characteristics []CharDefinition `json:"characteristics" yaml:"characteristics"`
+}
+
+// CharDefinition describe a GATT characteristic
+type CharDefinition struct {
+	UUID  bluez.UUID `json:"uuid" yaml:"uuid"`
+	Flags []string   `json:"flags" yaml:"flags"`
+	// Value is the hex encoded initial value
+	Value string `json:"value" yaml:"value"`
+	// Handler is the name of an entry in AppDefinition.CharHandlers
+	Handler     string            `json:"handler" yaml:"handler"`
+	Descriptors []DescrDefinition `json:"descriptors" yaml:"descriptors"`
+
+	OnRead  CharReadCallback  `json:"-" yaml:"-"`
+	OnWrite CharWriteCallback `json:"-" yaml:"-"`
+}
+
+// DescrDefinition describe a GATT descriptor
+type DescrDefinition struct {
+	UUID  bluez.UUID `json:"uuid" yaml:"uuid"`
+	Flags []string   `json:"flags" yaml:"flags"`
+	// Value is the hex encoded initial value
+	Value string `json:"value" yaml:"value"`
+	// Handler is the name of an entry in AppDefinition.DescrHandlers
+	Handler string `json:"handler" yaml:"handler"`
+
+	OnRead  DescrReadCallback  `json:"-" yaml:"-"`
+	OnWrite DescrWriteCallback `json:"-" yaml:"-"`
+}
+
+// CharHandler group the callbacks of a characteristic
+type CharHandler struct {
+	OnRead  CharReadCallback
+	OnWrite CharWriteCallback
+
+	OnReadRequest  CharReadRequestCallback
+	OnWriteRequest CharWriteRequestCallback
+}
+
+// DescrHandler group the callbacks of a descriptor
+type DescrHandler struct {
+	OnRead  DescrReadCallback
+	OnWrite DescrWriteCallback
+
+	OnReadRequest  DescrReadRequestCallback
+	OnWriteRequest DescrWriteRequestCallback
+}
+
+// ParseAppDefinition parse a YAML or JSON document
+func ParseAppDefinition(data []byte) (*AppDefinition, error) {
+
+	def := new(AppDefinition)
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		err := json.Unmarshal(data, def)
+		if err != nil {
+			return nil, fmt.Errorf("json: %s", err)
+		}
+		return def, nil
+	}
+
+	err := yaml.UnmarshalStrict(data, def)
+	if err != nil {
+		return nil, fmt.Errorf("yaml: %s", err)
+	}
+
+	return def, nil
+}
+
+// LoadAppDefinition load a YAML or JSON document from a file
+func LoadAppDefinition(filename string) (*AppDefinition, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAppDefinition(data)
+}
+
+// ValidationError list the issues found in a GATT database
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("GATT validation failed: %s", strings.Join(e.Errors, "; "))
+}
+
+func (e *ValidationError) add(format string, args ...interface{}) {
+	e.Errors = append(e.Errors, fmt.Sprintf(format, args...))
+}
+
+func (e *ValidationError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate report UUIDs, flags, handlers and includes issues in the definition
+func (d *AppDefinition) Validate() error {
+
+	verr := new(ValidationError)
+
+	ids := map[string]bool{}
+	for i, s := range d.Services {
+		id := s.id()
+		if id == "" {
+			continue
+		}
+		if ids[id] {
+			verr.add("service[%d]: duplicated ID %s", i, id)
+		}
+		ids[id] = true
+	}
+
+	for i, s := range d.Services {
+
+		sname := fmt.Sprintf("service[%d]", i)
+
+		if s.UUID == (bluez.UUID{}) {
+			verr.add("%s: missing UUID", sname)
+		}
+
+		for _, include := range s.Includes {
+			id := include
+			if !ids[id] {
+				id = includeID(include)
+			}
+			if id == s.id() {
+				verr.add("%s: cannot include itself", sname)
+				continue
+			}
+			if !ids[id] {
+				verr.add("%s: included service %s not found", sname, include)
+			}
+		}
+
+		for j, c := range s.Characteristics {
+
+			cname := fmt.Sprintf("%s.char[%d]", sname, j)
+
+			if c.UUID == (bluez.UUID{}) {
+				verr.add("%s: missing UUID", cname)
+			}
+
+			if len(c.Flags) == 0 {
+				verr.add("%s: no flags", cname)
+			}
+			for _, msg := range validateCharFlags(c.Flags) {
+				verr.add("%s: %s", cname, msg)
+			}
+
+			if _, err := hex.DecodeString(c.Value); err != nil {
+				verr.add("%s: value is not hex encoded", cname)
+			}
+
+			handler := CharHandler{OnRead: c.OnRead, OnWrite: c.OnWrite}
+			if c.Handler != "" {
+				h, ok := d.CharHandlers[c.Handler]
+				if !ok {
+					verr.add("%s: handler %s not found", cname, c.Handler)
+				}
+				handler = h
+			}
+			if (handler.OnRead != nil || handler.OnReadRequest != nil) && !hasReadFlag(c.Flags) {
+				verr.add("%s: read handler without a read flag", cname)
+			}
+			if (handler.OnWrite != nil || handler.OnWriteRequest != nil) && !hasWriteFlag(c.Flags) {
+				verr.add("%s: write handler without a write flag", cname)
+			}
+
+			descrUUIDs := map[bluez.UUID]bool{}
+			for k, descr := range c.Descriptors {
+
+				dname := fmt.Sprintf("%s.descr[%d]", cname, k)
+
+				if descr.UUID == (bluez.UUID{}) {
+					verr.add("%s: missing UUID", dname)
+				} else {
+					if descrUUIDs[descr.UUID] {
+						verr.add("%s: duplicated UUID %s", dname, descr.UUID)
+					}
+					descrUUIDs[descr.UUID] = true
+					if msg := validateDescrUUID(descr.UUID); msg != "" {
+						verr.add("%s: %s", dname, msg)
+					}
+				}
+
+				for _, msg := range validateDescrFlags(descr.Flags) {
+					verr.add("%s: %s", dname, msg)
+				}
+
+				if _, err := hex.DecodeString(descr.Value); err != nil {
+					verr.add("%s: value is not hex encoded", dname)
+				}
+
+				if descr.Handler != "" {
+					if _, ok := d.DescrHandlers[descr.Handler]; !ok {
+						verr.add("%s: handler %s not found", dname, descr.Handler)
+					}
+				}
+			}
+		}
+	}
+
+	return verr.err()
+}
+
+func (s ServiceDefinition) id() string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return s.UUID.String()
+}
+
+// includeID return the ID of a service included by UUID
+func includeID(include string) string {
+	if u, err := bluez.ParseUUID(include); err == nil {
+		return u.String()
+	}
+	return include
+}
+
+// AddDefinition validate the definition and expose all the services it
+// describes. Nothing is exposed if an error is returned. Call Run to register
+// the application once done
+func (app *App) AddDefinition(def *AppDefinition) ([]*Service, error) {
+
+	err := def.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	services := []*Service{}
+	servicesByID := map[string]*Service{}
+
+	for i, sdef := range def.Services {
+		s, err := app.newService(len(app.services) + i + 1000)
+		if err != nil {
+			return nil, err
+		}
+		s.Properties.UUID = sdef.UUID.String()
+		s.Properties.Primary = !sdef.Secondary
+		services = append(services, s)
+		servicesByID[sdef.id()] = s
+	}
+
+	for i, sdef := range def.Services {
+
+		s := services[i]
+
+		includes := []dbus.ObjectPath{}
+		for _, include := range sdef.Includes {
+			included, ok := servicesByID[include]
+			if !ok {
+				included = servicesByID[includeID(include)]
+			}
+			includes = append(includes, included.Path())
+		}
+		s.Properties.Includes = includes
+
+		for _, cdef := range sdef.Characteristics {
+			err = buildCharDefinition(def, s, cdef)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i, s := range services {
+		err = app.exposeDefinedService(s)
+		if err != nil {
+			for _, exposed := range services[:i+1] {
+				if rerr := app.RemoveService(exposed); rerr != nil {
+					log.Warnf("AddDefinition: remove %s: %s", exposed.Path(), rerr)
+				}
+				delete(app.defined, exposed.Path())
+			}
+			return nil, err
+		}
+	}
+
+	return services, nil
+}
+
+// exposeDefinedService expose a service built from a definition with its
+// characteristics and descriptors
+func (app *App) exposeDefinedService(s *Service) error {
+
+	app.services[s.Path()] = s
+	app.defined[s.Path()] = true
+
+	for _, c := range s.GetChars() {
+		err := api.ExposeDBusService(c)
+		if err != nil {
+			return err
+		}
+		for _, descr := range c.GetDescr() {
+			err = api.ExposeDBusService(descr)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return app.AddService(s)
+}
+
+// buildCharDefinition add a characteristic and its descriptors to the
+// service, without exposing them
+func buildCharDefinition(def *AppDefinition, s *Service, cdef CharDefinition) error {
+
+	c, err := s.NewChar()
+	if err != nil {
+		return err
+	}
+
+	c.Properties.UUID = cdef.UUID.String()
+	c.Properties.Flags = cdef.Flags
+	c.Properties.Value, _ = hex.DecodeString(cdef.Value)
+
+	handler := CharHandler{OnRead: cdef.OnRead, OnWrite: cdef.OnWrite}
+	if cdef.Handler != "" {
+		handler = def.CharHandlers[cdef.Handler]
+	}
+	if handler.OnRead != nil {
+		c.OnRead(handler.OnRead)
+	}
+	if handler.OnWrite != nil {
+		c.OnWrite(handler.OnWrite)
+	}
+	c.OnReadRequest(handler.OnReadRequest).OnWriteRequest(handler.OnWriteRequest)
+
+	s.chars[c.Path()] = c
+
+	for _, ddef := range cdef.Descriptors {
+
+		descr, err := c.NewDescr()
+		if err != nil {
+			return err
+		}
+
+		descr.Properties.UUID = ddef.UUID.String()
+		if len(ddef.Flags) > 0 {
+			descr.Properties.Flags = ddef.Flags
+		}
+		descr.Properties.Value, _ = hex.DecodeString(ddef.Value)
+
+		dhandler := DescrHandler{OnRead: ddef.OnRead, OnWrite: ddef.OnWrite}
+		if ddef.Handler != "" {
+			dhandler = def.DescrHandlers[ddef.Handler]
+		}
+		descr.OnRead(dhandler.OnRead).OnWrite(dhandler.OnWrite).
+			OnReadRequest(dhandler.OnReadRequest).OnWriteRequest(dhandler.OnWriteRequest)
+
+		c.descr[descr.Path()] = descr
+	}
+
+	return nil
+}
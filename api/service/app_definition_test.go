@@ -0,0 +1,141 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDefinitionYAML = `
+services:
+  - id: battery
+    uuid: "180F"
+    characteristics:
+      - uuid: "2A19"
+        flags: [read, notify]
+        value: "64"
+        handler: level
+  - uuid: "12345678-1234-5678-1234-56789abcdef0"
+    includes: [battery]
+    characteristics:
+      - uuid: "12345678-1234-5678-1234-56789abcdef1"
+        flags: [read, write]
+        descriptors:
+          - uuid: "2901"
+            flags: [read]
+            value: "6e616d65"
+`
+
+const testDefinitionJSON = `{
+  "services": [
+    {"uuid": "180A", "characteristics": [{"uuid": "2A29", "flags": ["read"]}]}
+  ]
+}`
+
+func TestParseAppDefinition(t *testing.T) {
+
+	def, err := ParseAppDefinition([]byte(testDefinitionYAML))
+	assert.NoError(t, err)
+	assert.Len(t, def.Services, 2)
+	assert.Equal(t, []string{"battery"}, def.Services[1].Includes)
+	assert.Equal(t, bluez.UUID16(0x2901), def.Services[1].Characteristics[0].Descriptors[0].UUID)
+
+	// handler is not registered
+	assert.Error(t, def.Validate())
+
+	def.CharHandlers = map[string]CharHandler{
+		"level": {
+			OnRead: func(c *Char, options map[string]interface{}) ([]byte, error) {
+				return []byte{100}, nil
+			},
+		},
+	}
+	assert.NoError(t, def.Validate())
+
+	def, err = ParseAppDefinition([]byte(testDefinitionJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, bluez.UUID16(0x2A29), def.Services[0].Characteristics[0].UUID)
+	assert.NoError(t, def.Validate())
+
+	_, err = ParseAppDefinition([]byte(`{"services": [{"uuid": "not-an-uuid"}]}`))
+	assert.Error(t, err)
+}
+
+func TestAppDefinitionConflicts(t *testing.T) {
+
+	def := &AppDefinition{
+		Services: []ServiceDefinition{
+			{
+				UUID:     bluez.UUID16(0x180F),
+				Includes: []string{"180A"},
+				Characteristics: []CharDefinition{
+					{
+						UUID:  bluez.UUID16(0x2A19),
+						Flags: []string{"read", "read", "notify", "fly"},
+						OnWrite: func(c *Char, value []byte) ([]byte, error) {
+							return value, nil
+						},
+						Descriptors: []DescrDefinition{
+							{UUID: bluez.UUID16(0x2902)},
+							{UUID: bluez.UUID16(0x2901), Value: "zz"},
+							{UUID: bluez.UUID16(0x2901)},
+						},
+					},
+					{},
+				},
+			},
+		},
+	}
+
+	err := def.Validate()
+	assert.Error(t, err)
+
+	verr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"service[0]: included service 180A not found",
+		"service[0].char[0]: duplicated flag read",
+		"service[0].char[0]: unknown flag fly",
+		"service[0].char[0]: write handler without a write flag",
+		"service[0].char[0].descr[0]: Client Characteristic Configuration descriptor is managed by Bluez",
+		"service[0].char[0].descr[1]: value is not hex encoded",
+		"service[0].char[0].descr[2]: duplicated UUID 00002901-0000-1000-8000-00805f9b34fb",
+		"service[0].char[1]: missing UUID",
+		"service[0].char[1]: no flags",
+	}, verr.Errors)
+}
+
+func TestAppValidateDefined(t *testing.T) {
+
+	// a service built by hand exposing the CCC descriptor
+	s := &Service{
+		path:       "/app/service0",
+		Properties: NewGattService1Properties("180F"),
+		chars:      map[dbus.ObjectPath]*Char{},
+	}
+	c := &Char{
+		path:       "/app/service0/char0",
+		service:    s,
+		Properties: NewGattCharacteristic1Properties("2A19"),
+		descr:      map[dbus.ObjectPath]*Descr{},
+	}
+	c.Properties.Flags = []string{"read", "notify"}
+	c.descr["/app/service0/char0/descr0"] = &Descr{
+		path:       "/app/service0/char0/descr0",
+		Properties: NewGattDescriptor1Properties("2902"),
+	}
+	s.chars[c.path] = c
+
+	app := &App{
+		services: map[dbus.ObjectPath]*Service{s.path: s},
+		defined:  map[dbus.ObjectPath]bool{},
+	}
+
+	assert.Error(t, app.Validate())
+	assert.NoError(t, app.validateDefined())
+
+	app.defined[s.path] = true
+	assert.Error(t, app.validateDefined())
+}
@@ -10,6 +10,12 @@ import (
 type DescrReadCallback func(c *Descr, options map[string]interface{}) ([]byte, error)
 type DescrWriteCallback func(c *Descr, value []byte) ([]byte, error)
 
+// DescrReadRequestCallback return the value to read starting at req.Offset
+type DescrReadRequestCallback func(c *Descr, req *Request) ([]byte, error)
+
+// DescrWriteRequestCallback receive the data written at req.Offset and return the new value
+type DescrWriteRequestCallback func(c *Descr, req *Request, value []byte) ([]byte, error)
+
 type Descr struct {
 	ID   int
 	app  *App
@@ -22,6 +28,11 @@ type Descr struct {
 
 	readCallback  DescrReadCallback
 	writeCallback DescrWriteCallback
+
+	readRequestCallback  DescrReadRequestCallback
+	writeRequestCallback DescrWriteRequestCallback
+
+	authorizeCallback DescrAuthorizeCallback
 }
 
 func (s *Descr) DBusProperties() *api.DBusProperties {
@@ -66,3 +77,27 @@ func (s *Descr) Expose() error {
 func (s *Descr) Remove() error {
 	return api.RemoveDBusService(s)
 }
+
+// Set the Read callback, called when a client attempt to read
+func (s *Descr) OnRead(fx DescrReadCallback) *Descr {
+	s.readCallback = fx
+	return s
+}
+
+// Set the Write callback, called when a client attempt to write
+func (s *Descr) OnWrite(fx DescrWriteCallback) *Descr {
+	s.writeCallback = fx
+	return s
+}
+
+// Set the Read callback receiving the request options, it takes precedence over OnRead
+func (s *Descr) OnReadRequest(fx DescrReadRequestCallback) *Descr {
+	s.readRequestCallback = fx
+	return s
+}
+
+// Set the Write callback receiving the request options, it takes precedence over OnWrite
+func (s *Descr) OnWriteRequest(fx DescrWriteRequestCallback) *Descr {
+	s.writeRequestCallback = fx
+	return s
+}
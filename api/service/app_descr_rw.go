@@ -10,15 +10,30 @@ func (s *Descr) ReadValue(options map[string]interface{}) ([]byte, *dbus.Error)
 
 	log.Trace("Descr.ReadValue")
 
+	req := NewRequest(options)
+	if derr := s.authorize(req); derr != nil {
+		return nil, derr
+	}
+
+	if s.readRequestCallback != nil {
+		b, err := s.readRequestCallback(s, req)
+		if err != nil {
+			return nil, toDBusError(err)
+		}
+		return b, nil
+	}
+
 	if s.readCallback != nil {
 		b, err := s.readCallback(s, options)
 		if err != nil {
-			return nil, dbus.MakeFailedError(err)
+			return nil, toDBusError(err)
 		}
 		return b, nil
 	}
 
-	return s.Properties.Value, nil
+	s.Properties.Lock()
+	defer s.Properties.Unlock()
+	return readAt(s.Properties.Value, req.Offset)
 }
 
 //WriteValue write a value
@@ -26,21 +41,54 @@ func (s *Descr) WriteValue(value []byte, options map[string]interface{}) *dbus.E
 
 	log.Trace("Descr.WriteValue")
 
-	val := value
-	if s.writeCallback != nil {
+	req := NewRequest(options)
+	if derr := s.authorize(req); derr != nil {
+		return derr
+	}
+
+	// prepare write authorization, the value is written on execute
+	if req.PrepareAuthorize {
+		return nil
+	}
+
+	var val []byte
+	if s.writeRequestCallback != nil {
+		log.Trace("Used write request callback")
+		b, err := s.writeRequestCallback(s, req, value)
+		if err != nil {
+			return toDBusError(err)
+		}
+		val = b
+	} else if s.writeCallback != nil {
 		log.Trace("Used write callback")
 		b, err := s.writeCallback(s, value)
-		val = b
 		if err != nil {
-			return dbus.MakeFailedError(err)
+			return toDBusError(err)
 		}
+		val = b
 	} else {
 		log.Trace("Store directly to value (no callback)")
+		s.Properties.Lock()
+		b, derr := writeAt(s.Properties.Value, value, req.Offset)
+		s.Properties.Unlock()
+		if derr != nil {
+			return derr
+		}
+		val = b
 	}
 
-	// TODO update on Properties interface
-	s.Properties.Value = val
-	s.iprops.Instance().Set(s.Interface(), "Value", dbus.MakeVariant(value))
+	s.setValue(val)
 
 	return nil
 }
+
+// setValue store the value and update the Value property
+func (s *Descr) setValue(val []byte) {
+	s.Properties.Lock()
+	s.Properties.Value = val
+	s.Properties.Unlock()
+
+	if s.iprops.Instance() != nil {
+		s.iprops.Instance().Set(s.Interface(), "Value", dbus.MakeVariant(val))
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+)
+
+// Write types reported by Bluez in the WriteValue options
+const (
+	// WriteTypeCommand is a write without response
+	WriteTypeCommand = "command"
+	// WriteTypeRequest is a write with response
+	WriteTypeRequest = "request"
+	// WriteTypeReliable is a write part of a reliable write transaction
+	WriteTypeReliable = "reliable"
+)
+
+const bluezErrorPrefix = "org.bluez.Error."
+
+// Request describe the options of a read or write received from Bluez
+type Request struct {
+	// Offset is the position in the value, used by long reads and writes
+	Offset uint16
+	// MTU is the exchanged MTU, zero if not provided
+	MTU uint16
+	// Device is the path of the remote device
+	Device dbus.ObjectPath
+	// Link is the link type, BR/EDR or LE
+	Link string
+	// Type is the write type (command, request or reliable), empty on read
+	Type string
+	// PrepareAuthorize is set when Bluez ask to authorize a prepared write
+	PrepareAuthorize bool
+	// Options are the raw options
+	Options map[string]interface{}
+}
+
+// NewRequest parse the options of a ReadValue or WriteValue call
+func NewRequest(options map[string]interface{}) *Request {
+
+	req := &Request{
+		Options: options,
+	}
+
+	if options == nil {
+		return req
+	}
+
+	req.Offset, _ = optionValue(options, "offset").(uint16)
+	req.Device, _ = optionValue(options, "device").(dbus.ObjectPath)
+	req.Link, _ = optionValue(options, "link").(string)
+	req.Type, _ = optionValue(options, "type").(string)
+	req.PrepareAuthorize, _ = optionValue(options, "prepare-authorize").(bool)
+
+	req.MTU, _ = optionValue(options, "mtu").(uint16)
+	if req.MTU == 0 {
+		req.MTU, _ = optionValue(options, "MTU").(uint16)
+	}
+
+	return req
+}
+
+func optionValue(options map[string]interface{}, name string) interface{} {
+	val := options[name]
+	if variant, ok := val.(dbus.Variant); ok {
+		return variant.Value()
+	}
+	return val
+}
+
+// GetDevice return the remote device which sent the request
+func (r *Request) GetDevice() (*device.Device1, error) {
+	if r.Device == "" {
+		return nil, errors.New("Device not available in request")
+	}
+	return device.NewDevice1(r.Device)
+}
+
+// readAt return the value starting at offset
+func readAt(value []byte, offset uint16) ([]byte, *dbus.Error) {
+	if int(offset) > len(value) {
+		return nil, &profile.ErrInvalidOffset
+	}
+	return value[offset:], nil
+}
+
+// writeAt return the value replaced from offset by data, the bytes
+// following the written data are dropped
+func writeAt(value []byte, data []byte, offset uint16) ([]byte, *dbus.Error) {
+
+	if int(offset) > len(value) {
+		return nil, &profile.ErrInvalidOffset
+	}
+
+	res := make([]byte, int(offset)+len(data))
+	copy(res, value[:offset])
+	copy(res[offset:], data)
+
+	return res, nil
+}
+
+// toDBusError convert the errors of the callbacks. DBus errors such as
+// profile.ErrNotAuthorized are returned as is, Bluez sentinel errors such as
+// bluez.ErrNotPermitted are converted to the matching DBus error
+func toDBusError(err error) *dbus.Error {
+	switch e := err.(type) {
+	case *dbus.Error:
+		return e
+	case dbus.Error:
+		return &e
+	case *bluez.Error:
+		return &e.DBusError
+	}
+	if strings.HasPrefix(err.Error(), bluezErrorPrefix) {
+		name := err.Error()
+		return dbus.NewError(name, []interface{}{strings.TrimPrefix(name, bluezErrorPrefix)})
+	}
+	return dbus.MakeFailedError(err)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/api"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRequest(t *testing.T) {
+
+	req := NewRequest(map[string]interface{}{
+		"offset":            uint16(18),
+		"mtu":               dbus.MakeVariant(uint16(185)),
+		"device":            dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55"),
+		"link":              "LE",
+		"type":              "reliable",
+		"prepare-authorize": true,
+	})
+
+	assert.Equal(t, uint16(18), req.Offset)
+	assert.Equal(t, uint16(185), req.MTU)
+	assert.Equal(t, dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55"), req.Device)
+	assert.Equal(t, "LE", req.Link)
+	assert.Equal(t, WriteTypeReliable, req.Type)
+	assert.True(t, req.PrepareAuthorize)
+
+	req = NewRequest(nil)
+	assert.Equal(t, uint16(0), req.Offset)
+	_, err := req.GetDevice()
+	assert.Error(t, err)
+}
+
+func TestCharReadOffset(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicRead)
+	c.Properties.Value = []byte{1, 2, 3, 4}
+
+	b, derr := c.ReadValue(map[string]interface{}{})
+	assert.Nil(t, derr)
+	assert.Equal(t, []byte{1, 2, 3, 4}, b)
+
+	b, derr = c.ReadValue(map[string]interface{}{"offset": uint16(2)})
+	assert.Nil(t, derr)
+	assert.Equal(t, []byte{3, 4}, b)
+
+	b, derr = c.ReadValue(map[string]interface{}{"offset": uint16(4)})
+	assert.Nil(t, derr)
+	assert.Equal(t, []byte{}, b)
+
+	_, derr = c.ReadValue(map[string]interface{}{"offset": uint16(5)})
+	assert.Equal(t, profile.ErrInvalidOffset.Name, derr.Name)
+}
+
+func TestCharWriteOffset(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicWrite)
+	c.Properties.Value = []byte{1, 2, 3, 4}
+
+	// the value is truncated after the written data
+	assert.Nil(t, c.WriteValue([]byte{9}, map[string]interface{}{"offset": uint16(1)}))
+	assert.Equal(t, []byte{1, 9}, c.Properties.Value)
+
+	assert.Nil(t, c.WriteValue([]byte{7, 8}, map[string]interface{}{"offset": uint16(2)}))
+	assert.Equal(t, []byte{1, 9, 7, 8}, c.Properties.Value)
+
+	assert.Nil(t, c.WriteValue([]byte{5}, nil))
+	assert.Equal(t, []byte{5}, c.Properties.Value)
+
+	derr := c.WriteValue([]byte{0}, map[string]interface{}{"offset": uint16(10)})
+	assert.Equal(t, profile.ErrInvalidOffset.Name, derr.Name)
+	assert.Equal(t, []byte{5}, c.Properties.Value)
+}
+
+func TestCharRequestCallbacks(t *testing.T) {
+
+	c := newTestChar(t, gatt.FlagCharacteristicRead, gatt.FlagCharacteristicWrite)
+
+	allowed := dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55")
+
+	c.OnReadRequest(func(c *Char, req *Request) ([]byte, error) {
+		if req.Device != allowed {
+			return nil, profile.ErrNotAuthorized
+		}
+		return []byte{byte(req.Offset)}, nil
+	}).OnWriteRequest(func(c *Char, req *Request, value []byte) ([]byte, error) {
+		if req.Type == WriteTypeCommand {
+			return nil, bluez.ErrNotPermitted
+		}
+		if req.Offset > 0 {
+			return nil, errors.New("long write not supported")
+		}
+		return value, nil
+	})
+
+	b, derr := c.ReadValue(map[string]interface{}{"device": allowed, "offset": uint16(3)})
+	assert.Nil(t, derr)
+	assert.Equal(t, []byte{3}, b)
+
+	_, derr = c.ReadValue(map[string]interface{}{})
+	assert.Equal(t, "org.bluez.Error.NotAuthorized", derr.Name)
+
+	assert.Nil(t, c.WriteValue([]byte{5}, map[string]interface{}{"type": WriteTypeRequest}))
+	assert.Equal(t, []byte{5}, c.Properties.Value)
+
+	derr = c.WriteValue([]byte{6}, map[string]interface{}{"type": WriteTypeCommand})
+	assert.Equal(t, "org.bluez.Error.NotPermitted", derr.Name)
+
+	derr = c.WriteValue([]byte{6}, map[string]interface{}{"offset": uint16(1)})
+	assert.Equal(t, "org.freedesktop.DBus.Error.Failed", derr.Name)
+	assert.Equal(t, []byte{5}, c.Properties.Value)
+}
+
+func TestDescrWriteOffset(t *testing.T) {
+
+	iprops, err := api.NewDBusProperties(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &Descr{
+		Properties: NewGattDescriptor1Properties("00002901-0000-1000-8000-00805F9B34FB"),
+		iprops:     iprops,
+	}
+	d.Properties.Value = []byte("hello world")
+
+	assert.Nil(t, d.WriteValue([]byte("p!"), map[string]interface{}{"offset": uint16(3)}))
+	assert.Equal(t, []byte("help!"), d.Properties.Value)
+
+	b, derr := d.ReadValue(map[string]interface{}{"offset": uint16(3)})
+	assert.Nil(t, derr)
+	assert.Equal(t, []byte("p!"), b)
+}
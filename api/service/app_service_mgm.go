@@ -15,9 +15,13 @@ func (app *App) GetServices() map[dbus.ObjectPath]*Service {
 }
 
 func (app *App) NewService() (*Service, error) {
+	return app.newService(len(app.services) + 1000)
+}
+
+func (app *App) newService(id int) (*Service, error) {
 
 	s := new(Service)
-	s.ID = len(app.services) + 1000
+	s.ID = id
 
 	if app.baseUUID == "" {
 		rndUUID, err := RandomUUID()
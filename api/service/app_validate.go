@@ -0,0 +1,183 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+)
+
+// UUIDs of descriptors managed by Bluez
+var reservedDescrUUIDs = map[bluez.UUID]bool{
+	bluez.UUID16(0x2900): true,
+	bluez.UUID16(0x2902): true,
+}
+
+var charFlags = []string{
+	gatt.FlagCharacteristicBroadcast,
+	gatt.FlagCharacteristicRead,
+	gatt.FlagCharacteristicWriteWithoutResponse,
+	gatt.FlagCharacteristicWrite,
+	gatt.FlagCharacteristicNotify,
+	gatt.FlagCharacteristicIndicate,
+	gatt.FlagCharacteristicAuthenticatedSignedWrites,
+	gatt.FlagCharacteristicReliableWrite,
+	gatt.FlagCharacteristicWritableAuxiliaries,
+	gatt.FlagCharacteristicEncryptRead,
+	gatt.FlagCharacteristicEncryptWrite,
+	gatt.FlagCharacteristicEncryptAuthenticatedRead,
+	gatt.FlagCharacteristicEncryptAuthenticatedWrite,
+	gatt.FlagCharacteristicSecureRead,
+	gatt.FlagCharacteristicSecureWrite,
+	gatt.FlagCharacteristicAuthorize,
+}
+
+var descrFlags = []string{
+	gatt.FlagDescriptorRead,
+	gatt.FlagDescriptorWrite,
+	gatt.FlagDescriptorEncryptRead,
+	gatt.FlagDescriptorEncryptWrite,
+	gatt.FlagDescriptorEncryptAuthenticatedRead,
+	gatt.FlagDescriptorEncryptAuthenticatedWrite,
+	gatt.FlagDescriptorSecureRead,
+	gatt.FlagDescriptorSecureWrite,
+	gatt.FlagDescriptorAuthorize,
+}
+
+func hasFlag(flags []string, list ...string) bool {
+	for _, flag := range flags {
+		for _, f := range list {
+			if flag == f {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasReadFlag(flags []string) bool {
+	return hasFlag(flags,
+		gatt.FlagCharacteristicRead,
+		gatt.FlagCharacteristicEncryptRead,
+		gatt.FlagCharacteristicEncryptAuthenticatedRead,
+		gatt.FlagCharacteristicSecureRead,
+	)
+}
+
+func hasWriteFlag(flags []string) bool {
+	return hasFlag(flags,
+		gatt.FlagCharacteristicWrite,
+		gatt.FlagCharacteristicWriteWithoutResponse,
+		gatt.FlagCharacteristicAuthenticatedSignedWrites,
+		gatt.FlagCharacteristicReliableWrite,
+		gatt.FlagCharacteristicEncryptWrite,
+		gatt.FlagCharacteristicEncryptAuthenticatedWrite,
+		gatt.FlagCharacteristicSecureWrite,
+	)
+}
+
+func validateFlags(flags []string, known []string) []string {
+	errs := []string{}
+	seen := map[string]bool{}
+	for _, flag := range flags {
+		if seen[flag] {
+			errs = append(errs, fmt.Sprintf("duplicated flag %s", flag))
+			continue
+		}
+		seen[flag] = true
+		if !hasFlag([]string{flag}, known...) {
+			errs = append(errs, fmt.Sprintf("unknown flag %s", flag))
+		}
+	}
+	return errs
+}
+
+func validateCharFlags(flags []string) []string {
+	errs := validateFlags(flags, charFlags)
+	if hasFlag(flags, gatt.FlagCharacteristicReliableWrite) && !hasFlag(flags, gatt.FlagCharacteristicWrite) {
+		errs = append(errs, fmt.Sprintf("flag %s requires %s", gatt.FlagCharacteristicReliableWrite, gatt.FlagCharacteristicWrite))
+	}
+	return errs
+}
+
+func validateDescrFlags(flags []string) []string {
+	return validateFlags(flags, descrFlags)
+}
+
+// validateDescrUUID report descriptors which are exposed by Bluez itself
+func validateDescrUUID(uuid bluez.UUID) string {
+	if reservedDescrUUIDs[uuid] {
+		return fmt.Sprintf("%s descriptor is managed by Bluez", uuid.Name())
+	}
+	return ""
+}
+
+// Validate report flags and UUIDs conflicts of the exposed services
+func (app *App) Validate() error {
+	services := []*Service{}
+	for _, s := range app.GetServices() {
+		services = append(services, s)
+	}
+	return app.validateServices(services)
+}
+
+// validateDefined check the services added with AddDefinition, the
+// services built by hand are left as is
+func (app *App) validateDefined() error {
+	services := []*Service{}
+	for path := range app.defined {
+		if s, ok := app.services[path]; ok {
+			services = append(services, s)
+		}
+	}
+	return app.validateServices(services)
+}
+
+func (app *App) validateServices(services []*Service) error {
+
+	verr := new(ValidationError)
+
+	for _, s := range services {
+
+		if _, err := bluez.ParseUUID(s.Properties.UUID); err != nil {
+			verr.add("%s: %s", s.Path(), err)
+		}
+
+		for _, include := range s.Properties.Includes {
+			if _, ok := app.services[include]; !ok || include == s.Path() {
+				verr.add("%s: invalid included service %s", s.Path(), include)
+			}
+		}
+
+		for _, c := range s.GetChars() {
+
+			if _, err := bluez.ParseUUID(c.Properties.UUID); err != nil {
+				verr.add("%s: %s", c.Path(), err)
+			}
+			for _, msg := range validateCharFlags(c.Properties.Flags) {
+				verr.add("%s: %s", c.Path(), msg)
+			}
+
+			descrUUIDs := map[bluez.UUID]bool{}
+			for _, descr := range c.GetDescr() {
+				uuid, err := bluez.ParseUUID(descr.Properties.UUID)
+				if err != nil {
+					verr.add("%s: %s", descr.Path(), err)
+					continue
+				}
+				if descrUUIDs[uuid] {
+					verr.add("%s: duplicated UUID %s", descr.Path(), uuid)
+				}
+				descrUUIDs[uuid] = true
+				if msg := validateDescrUUID(uuid); msg != "" {
+					verr.add("%s: %s", descr.Path(), msg)
+				}
+				for _, msg := range validateDescrFlags(descr.Properties.Flags) {
+					verr.add("%s: %s", descr.Path(), msg)
+				}
+			}
+		}
+	}
+
+	return verr.err()
+}
@@ -1,6 +1,7 @@
 package bluez
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/godbus/dbus/v5"
@@ -28,6 +29,14 @@ func (c *Client) isConnected() bool {
 //Disconnect from DBus
 func (c *Client) Disconnect() {
 
+	// do not close an explicit connection
+	// as it is owned by the caller
+	if c.Config.Conn != nil {
+		c.conn = nil
+		c.dbusObject = nil
+		return
+	}
+
 	// do not disconnect SystemBus
 	// as it is a singleton from dbus package
 	if c.Config.Bus == SystemBus {
@@ -43,6 +52,13 @@ func (c *Client) Disconnect() {
 
 // Connect connects to DBus
 func (c *Client) Connect() error {
+
+	if c.Config.Conn != nil {
+		c.conn = c.Config.Conn.DBus()
+		c.dbusObject = c.conn.Object(c.Config.Name, dbus.ObjectPath(c.Config.Path))
+		return nil
+	}
+
 	dbusConn, err := GetConnection(c.Config.Bus)
 	if err != nil {
 		return err
@@ -52,6 +68,22 @@ func (c *Client) Connect() error {
 	return nil
 }
 
+// Conn return the explicit connection of the client, nil if the shared one is used
+func (c *Client) Conn() *Conn {
+	return c.Config.Conn
+}
+
+// GetObjectManager return the Bluez object manager on the same connection of the client
+func (c *Client) GetObjectManager() (*ObjectManager, error) {
+	return GetObjectManagerWithConn(c.Config.Conn)
+}
+
+// GetObjectsProvider return the object cache if enabled on the connection
+// of the client, otherwise the Bluez object manager
+func (c *Client) GetObjectsProvider() (ObjectsProvider, error) {
+	return GetObjectsProviderWithConn(c.Config.Conn)
+}
+
 // Call a DBus method
 func (c *Client) Call(method string, flags dbus.Flags, args ...interface{}) *dbus.Call {
 
@@ -68,6 +100,22 @@ func (c *Client) Call(method string, flags dbus.Flags, args ...interface{}) *dbu
 	return c.dbusObject.Call(methodPath, flags, args...)
 }
 
+// CallWithContext call a DBus method, the call is aborted when ctx is done
+func (c *Client) CallWithContext(ctx context.Context, method string, flags dbus.Flags, args ...interface{}) *dbus.Call {
+
+	if !c.isConnected() {
+		err := c.Connect()
+		if err != nil {
+			return &dbus.Call{
+				Err: err,
+			}
+		}
+	}
+
+	methodPath := fmt.Sprint(c.Config.Iface, ".", method)
+	return c.dbusObject.CallWithContext(ctx, methodPath, flags, args...)
+}
+
 //GetProperty return a property value
 func (c *Client) GetProperty(p string) (dbus.Variant, error) {
 	if !c.isConnected() {
@@ -114,12 +162,16 @@ func (c *Client) GetProperties(props interface{}) error {
 	return nil
 }
 
-func getMatchString(path dbus.ObjectPath, iface string) string {
-	return fmt.Sprintf("type='signal',interface='%s',path='%s'", iface, path)
-}
-
 //Register for signals
 func (c *Client) Register(path dbus.ObjectPath, iface string) (chan *dbus.Signal, error) {
+	return c.RegisterWithOptions(SubscribeOptions{}, SignalFilter{
+		Path:      path,
+		Interface: iface,
+	})
+}
+
+// RegisterWithOptions subscribe to the signals matching at least one of the filters
+func (c *Client) RegisterWithOptions(opts SubscribeOptions, filters ...SignalFilter) (chan *dbus.Signal, error) {
 
 	if !c.isConnected() {
 		err := c.Connect()
@@ -128,30 +180,21 @@ func (c *Client) Register(path dbus.ObjectPath, iface string) (chan *dbus.Signal
 		}
 	}
 
-	matchstr := getMatchString(path, iface)
-	c.conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, matchstr)
-
-	channel := make(chan *dbus.Signal, 1)
-	c.conn.Signal(channel)
-
-	return channel, nil
+	return GetSignalRouter(c.conn).Subscribe(opts, filters...)
 }
 
 //Unregister for signals
 func (c *Client) Unregister(path dbus.ObjectPath, iface string, signal chan *dbus.Signal) error {
+	if signal == nil {
+		return nil
+	}
 	if !c.isConnected() {
 		err := c.Connect()
 		if err != nil {
 			return err
 		}
 	}
-	matchstr := getMatchString(path, iface)
-	c.conn.BusObject().Call("org.freedesktop.DBus.RemoveMatch", 0, matchstr)
-	if signal != nil {
-		c.conn.RemoveSignal(signal)
-	}
-
-	return nil
+	return GetSignalRouter(c.conn).Unsubscribe(signal)
 }
 
 // Emit
@@ -0,0 +1,128 @@
+package bluez
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// NewConn open a new private connection to DBus. The connection is owned by
+// the caller and it is not shared with other components, use Close to release it.
+func NewConn(busType BusType) (*Conn, error) {
+
+	var conn *dbus.Conn
+	var err error
+
+	switch busType {
+	case SystemBus:
+		conn, err = dbus.SystemBusPrivate()
+	case SessionBus:
+		conn, err = dbus.SessionBusPrivate()
+	default:
+		return nil, errors.New("Unmanged DBus type code")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn.Auth(nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	err = conn.Hello()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return NewConnFromDBus(conn, busType), nil
+}
+
+// NewConnFromDBus wraps an already established DBus connection,
+// eg. to a private bus or a test bus
+func NewConnFromDBus(conn *dbus.Conn, busType BusType) *Conn {
+	return &Conn{
+		conn:    conn,
+		busType: busType,
+	}
+}
+
+// Conn is an explicit DBus connection which can be passed to clients,
+// object managers and API constructors in place of the shared default connections
+type Conn struct {
+	conn    *dbus.Conn
+	busType BusType
+
+	lock          sync.Mutex
+	objectManager *ObjectManager
+	objectCache   *ObjectCache
+}
+
+// DBus return the underlying DBus connection
+func (c *Conn) DBus() *dbus.Conn {
+	return c.conn
+}
+
+// Bus return the bus type of the connection
+func (c *Conn) Bus() BusType {
+	return c.busType
+}
+
+// GetObjectManager return the Bluez object manager bound to this connection
+func (c *Conn) GetObjectManager() (*ObjectManager, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.objectManager != nil {
+		return c.objectManager, nil
+	}
+
+	om, err := NewObjectManagerWithConn(c, OrgBluezInterface, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	c.objectManager = om
+	return om, nil
+}
+
+// GetObjectCache return the object cache bound to this connection,
+// the cache is created and loaded on first use. Once created, lookups
+// made by clients on this connection are served from the cache
+func (c *Conn) GetObjectCache() (*ObjectCache, error) {
+
+	om, err := c.GetObjectManager()
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.objectCache != nil {
+		return c.objectCache, nil
+	}
+
+	cache, err := NewObjectCache(om)
+	if err != nil {
+		return nil, err
+	}
+
+	c.objectCache = cache
+	return cache, nil
+}
+
+// Close the connection
+func (c *Conn) Close() error {
+	c.lock.Lock()
+	if c.objectCache != nil {
+		c.objectCache.Close()
+		c.objectCache = nil
+	}
+	c.objectManager = nil
+	c.lock.Unlock()
+	return c.conn.Close()
+}
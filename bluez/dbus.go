@@ -2,6 +2,7 @@ package bluez
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/godbus/dbus/v5"
 	log "github.com/sirupsen/logrus"
@@ -32,6 +33,7 @@ const (
 )
 
 var conns = make([]*dbus.Conn, 2)
+var connsLock sync.Mutex
 
 // Config pass configuration to a DBUS client
 type Config struct {
@@ -39,10 +41,18 @@ type Config struct {
 	Iface string
 	Path  dbus.ObjectPath
 	Bus   BusType
+	// Conn is the connection to use, if nil the shared connection for Bus is used
+	Conn *Conn
 }
 
-// CloseConnections close all open connection to DBus
+// CloseConnections close the shared connections to DBus.
+// Connections created with NewConn are not affected.
 func CloseConnections() (err error) {
+	// the shared object cache is bound to the shared connection
+	closeObjectCache()
+
+	connsLock.Lock()
+	defer connsLock.Unlock()
 	for _, conn := range conns {
 		if conn != nil {
 			err = conn.Close()
@@ -55,8 +65,10 @@ func CloseConnections() (err error) {
 	return err
 }
 
-//GetConnection get a DBus connection
+//GetConnection get a shared DBus connection
 func GetConnection(connType BusType) (*dbus.Conn, error) {
+	connsLock.Lock()
+	defer connsLock.Unlock()
 	switch connType {
 	case SystemBus:
 		if conns[SystemBus] == nil {
@@ -0,0 +1,58 @@
+package bluez
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+// Error wraps an error returned by Bluez over DBus. It matches the
+// corresponding sentinel error, eg. errors.Is(err, bluez.ErrInProgress)
+type Error struct {
+	err       error
+	DBusError dbus.Error
+}
+
+// Name return the Bluez error name, eg. org.bluez.Error.InProgress
+func (e *Error) Name() string {
+	return e.DBusError.Name
+}
+
+func (e *Error) Error() string {
+	return e.DBusError.Error()
+}
+
+// Unwrap return the sentinel error matching the Bluez error name
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// ParseError convert a dbus.Error with a known Bluez error name to an *Error.
+// Other errors are returned unchanged
+func ParseError(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	var dbusErr dbus.Error
+	switch e := err.(type) {
+	case dbus.Error:
+		dbusErr = e
+	case *dbus.Error:
+		if e == nil {
+			return err
+		}
+		dbusErr = *e
+	default:
+		return err
+	}
+
+	sentinel, ok := errorsMap[dbusErr.Name]
+	if !ok {
+		return err
+	}
+
+	return &Error{
+		err:       sentinel,
+		DBusError: dbusErr,
+	}
+}
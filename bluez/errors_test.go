@@ -0,0 +1,41 @@
+package bluez
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseError(t *testing.T) {
+
+	dbusErr := dbus.Error{
+		Name: "org.bluez.Error.InProgress",
+		Body: []interface{}{"Operation already in progress"},
+	}
+
+	err := ParseError(dbusErr)
+	assert.True(t, errors.Is(err, ErrInProgress))
+	assert.False(t, errors.Is(err, ErrFailed))
+	assert.Equal(t, "Operation already in progress", err.Error())
+
+	var bluezErr *Error
+	assert.True(t, errors.As(err, &bluezErr))
+	assert.Equal(t, "org.bluez.Error.InProgress", bluezErr.Name())
+
+	err = ParseError(&dbus.Error{Name: "org.bluez.Error.AlreadyConnected"})
+	assert.True(t, errors.Is(err, ErrAlreadyConnected))
+}
+
+func TestParseErrorPassthrough(t *testing.T) {
+
+	assert.Nil(t, ParseError(nil))
+
+	other := fmt.Errorf("some error")
+	assert.Equal(t, other, ParseError(other))
+
+	unknown := dbus.Error{Name: "org.freedesktop.DBus.Error.ServiceUnknown"}
+	assert.Equal(t, unknown, ParseError(unknown))
+}
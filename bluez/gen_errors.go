@@ -0,0 +1,102 @@
+
+package bluez
+
+import (
+	"errors"
+)
+
+var (
+
+	// ErrNotReady match org.bluez.Error.NotReady
+	ErrNotReady = errors.New("org.bluez.Error.NotReady")
+
+	// ErrFailed match org.bluez.Error.Failed
+	ErrFailed = errors.New("org.bluez.Error.Failed")
+
+	// ErrNotAuthorized match org.bluez.Error.NotAuthorized
+	ErrNotAuthorized = errors.New("org.bluez.Error.NotAuthorized")
+
+	// ErrInvalidArguments match org.bluez.Error.InvalidArguments
+	ErrInvalidArguments = errors.New("org.bluez.Error.InvalidArguments")
+
+	// ErrNotSupported match org.bluez.Error.NotSupported
+	ErrNotSupported = errors.New("org.bluez.Error.NotSupported")
+
+	// ErrAlreadyExists match org.bluez.Error.AlreadyExists
+	ErrAlreadyExists = errors.New("org.bluez.Error.AlreadyExists")
+
+	// ErrInvalidLength match org.bluez.Error.InvalidLength
+	ErrInvalidLength = errors.New("org.bluez.Error.InvalidLength")
+
+	// ErrDoesNotExist match org.bluez.Error.DoesNotExist
+	ErrDoesNotExist = errors.New("org.bluez.Error.DoesNotExist")
+
+	// ErrNotPermitted match org.bluez.Error.NotPermitted
+	ErrNotPermitted = errors.New("org.bluez.Error.NotPermitted")
+
+	// ErrRejected match org.bluez.Error.Rejected
+	ErrRejected = errors.New("org.bluez.Error.Rejected")
+
+	// ErrCanceled match org.bluez.Error.Canceled
+	ErrCanceled = errors.New("org.bluez.Error.Canceled")
+
+	// ErrInProgress match org.bluez.Error.InProgress
+	ErrInProgress = errors.New("org.bluez.Error.InProgress")
+
+	// ErrAlreadyConnected match org.bluez.Error.AlreadyConnected
+	ErrAlreadyConnected = errors.New("org.bluez.Error.AlreadyConnected")
+
+	// ErrNotConnected match org.bluez.Error.NotConnected
+	ErrNotConnected = errors.New("org.bluez.Error.NotConnected")
+
+	// ErrNotAvailable match org.bluez.Error.NotAvailable
+	ErrNotAvailable = errors.New("org.bluez.Error.NotAvailable")
+
+	// ErrInvalidOffset match org.bluez.Error.InvalidOffset
+	ErrInvalidOffset = errors.New("org.bluez.Error.InvalidOffset")
+
+	// ErrInvalidValueLength match org.bluez.Error.InvalidValueLength
+	ErrInvalidValueLength = errors.New("org.bluez.Error.InvalidValueLength")
+
+	// ErrNotFound match org.bluez.Error.NotFound
+	ErrNotFound = errors.New("org.bluez.Error.NotFound")
+
+	// ErrNotAllowed match org.bluez.Error.NotAllowed
+	ErrNotAllowed = errors.New("org.bluez.Error.NotAllowed")
+
+	// ErrOutOfRange match org.bluez.Error.OutOfRange
+	ErrOutOfRange = errors.New("org.bluez.Error.OutOfRange")
+
+	// ErrHealthError match org.bluez.Error.HealthError
+	ErrHealthError = errors.New("org.bluez.Error.HealthError")
+
+	// ErrNotAcquired match org.bluez.Error.NotAcquired
+	ErrNotAcquired = errors.New("org.bluez.Error.NotAcquired")
+
+)
+
+// errorsMap map a Bluez error name to the corresponding error
+var errorsMap = map[string]error{
+	"org.bluez.Error.NotReady": ErrNotReady,
+	"org.bluez.Error.Failed": ErrFailed,
+	"org.bluez.Error.NotAuthorized": ErrNotAuthorized,
+	"org.bluez.Error.InvalidArguments": ErrInvalidArguments,
+	"org.bluez.Error.NotSupported": ErrNotSupported,
+	"org.bluez.Error.AlreadyExists": ErrAlreadyExists,
+	"org.bluez.Error.InvalidLength": ErrInvalidLength,
+	"org.bluez.Error.DoesNotExist": ErrDoesNotExist,
+	"org.bluez.Error.NotPermitted": ErrNotPermitted,
+	"org.bluez.Error.Rejected": ErrRejected,
+	"org.bluez.Error.Canceled": ErrCanceled,
+	"org.bluez.Error.InProgress": ErrInProgress,
+	"org.bluez.Error.AlreadyConnected": ErrAlreadyConnected,
+	"org.bluez.Error.NotConnected": ErrNotConnected,
+	"org.bluez.Error.NotAvailable": ErrNotAvailable,
+	"org.bluez.Error.InvalidOffset": ErrInvalidOffset,
+	"org.bluez.Error.InvalidValueLength": ErrInvalidValueLength,
+	"org.bluez.Error.NotFound": ErrNotFound,
+	"org.bluez.Error.NotAllowed": ErrNotAllowed,
+	"org.bluez.Error.OutOfRange": ErrOutOfRange,
+	"org.bluez.Error.HealthError": ErrHealthError,
+	"org.bluez.Error.NotAcquired": ErrNotAcquired,
+}
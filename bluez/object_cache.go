@@ -0,0 +1,404 @@
+package bluez
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	adapterInterface        = "org.bluez.Adapter1"
+	deviceInterface         = "org.bluez.Device1"
+	gattServiceInterface    = "org.bluez.GattService1"
+	gattCharInterface       = "org.bluez.GattCharacteristic1"
+	gattDescriptorInterface = "org.bluez.GattDescriptor1"
+)
+
+// ObjectsProvider expose the tree of objects managed by Bluez,
+// it is implemented by ObjectManager and ObjectCache
+type ObjectsProvider interface {
+	GetManagedObjects() (map[dbus.ObjectPath]map[string]map[string]dbus.Variant, error)
+	GetManagedObject(objpath dbus.ObjectPath) (map[string]map[string]dbus.Variant, error)
+}
+
+// CachedObject is a snapshot of the properties of an object interface
+type CachedObject struct {
+	Path       dbus.ObjectPath
+	Interface  string
+	Properties map[string]dbus.Variant
+}
+
+var objectCache *ObjectCache
+var objectCacheLock sync.Mutex
+
+// GetObjectCache return the object cache for the shared connection,
+// the cache is created and loaded on first use
+func GetObjectCache() (*ObjectCache, error) {
+	objectCacheLock.Lock()
+	defer objectCacheLock.Unlock()
+
+	if objectCache != nil {
+		return objectCache, nil
+	}
+
+	om, err := GetObjectManager()
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := NewObjectCache(om)
+	if err != nil {
+		return nil, err
+	}
+
+	objectCache = cache
+	return cache, nil
+}
+
+// closeObjectCache close the object cache of the shared connection
+func closeObjectCache() {
+	objectCacheLock.Lock()
+	defer objectCacheLock.Unlock()
+
+	if objectCache != nil {
+		objectCache.Close()
+		objectCache = nil
+	}
+}
+
+// getObjectCacheWithConn return the object cache enabled for conn, nil if not available
+func getObjectCacheWithConn(conn *Conn) *ObjectCache {
+	if conn != nil {
+		conn.lock.Lock()
+		defer conn.lock.Unlock()
+		return conn.objectCache
+	}
+	objectCacheLock.Lock()
+	defer objectCacheLock.Unlock()
+	return objectCache
+}
+
+// GetObjectsProviderWithConn return the object cache if enabled for conn,
+// otherwise the object manager. A nil conn refers to the shared connection
+func GetObjectsProviderWithConn(conn *Conn) (ObjectsProvider, error) {
+	if cache := getObjectCacheWithConn(conn); cache != nil {
+		return cache, nil
+	}
+	return GetObjectManagerWithConn(conn)
+}
+
+// NewObjectCache create a cache of the objects exposed by the object manager.
+// The cache is loaded with GetManagedObjects and then updated from
+// InterfacesAdded, InterfacesRemoved and PropertiesChanged signals
+func NewObjectCache(om *ObjectManager) (*ObjectCache, error) {
+
+	c := &ObjectCache{
+		om:      om,
+		objects: make(map[dbus.ObjectPath]map[string]map[string]dbus.Variant),
+		quit:    make(chan struct{}),
+	}
+
+	err := c.start()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// ObjectCache keeps a local copy of the Bluez objects tree
+type ObjectCache struct {
+	om      *ObjectManager
+	lock    sync.RWMutex
+	objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+
+	// signal is set by start and not changed afterwards
+	signal    chan *dbus.Signal
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *ObjectCache) start() error {
+
+	// the subscription is lossless, Added and Removed signals can not be
+	// merged. The reader keeps consuming while the objects are loaded
+	signal, err := c.om.client.RegisterWithOptions(
+		SubscribeOptions{
+			Overflow: OverflowBlock,
+		},
+		SignalFilter{
+			Path:      c.om.client.Config.Path,
+			Interface: ObjectManagerInterface,
+		},
+		SignalFilter{
+			PathNamespace: OrgBluezPath,
+			Interface:     PropertiesInterface,
+			Member:        "PropertiesChanged",
+		},
+	)
+	if err != nil {
+		return err
+	}
+	c.signal = signal
+
+	load := make(chan map[dbus.ObjectPath]map[string]map[string]dbus.Variant)
+	loaded := make(chan struct{})
+	go c.watch(c.signal, c.quit, load, loaded)
+
+	objects, err := c.om.GetManagedObjects()
+	if err != nil {
+		c.om.client.Unregister(c.om.client.Config.Path, ObjectManagerInterface, c.signal)
+		close(c.quit)
+		c.signal = nil
+		return fmt.Errorf("GetManagedObjects: %s", err)
+	}
+
+	load <- objects
+	<-loaded
+
+	return nil
+}
+
+// watch apply the signals to the cache. The signals received before the
+// objects are sent on load are queued and applied once loaded is closed,
+// a nil load means the objects are already loaded
+func (c *ObjectCache) watch(ch chan *dbus.Signal, quit chan struct{}, load chan map[dbus.ObjectPath]map[string]map[string]dbus.Variant, loaded chan struct{}) {
+
+	pending := []*dbus.Signal{}
+
+	for {
+		select {
+		case sig := <-ch:
+			if sig == nil {
+				return
+			}
+			if load != nil {
+				pending = append(pending, sig)
+				continue
+			}
+			c.handleSignal(sig)
+		case objects := <-load:
+			c.load(objects)
+			for _, sig := range pending {
+				c.handleSignal(sig)
+			}
+			pending = nil
+			load = nil
+			close(loaded)
+		case <-quit:
+			return
+		}
+	}
+}
+
+// Close stop updating the cache, it is safe to call it more than once
+func (c *ObjectCache) Close() {
+	c.closeOnce.Do(func() {
+		if c.signal == nil {
+			return
+		}
+		// unsubscribe first, so no signal is delivered after the reader stops
+		c.om.client.Unregister(c.om.client.Config.Path, ObjectManagerInterface, c.signal)
+		close(c.quit)
+	})
+}
+
+func (c *ObjectCache) load(objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for path, ifaces := range objects {
+		c.objects[path] = ifaces
+	}
+}
+
+func (c *ObjectCache) handleSignal(sig *dbus.Signal) {
+
+	switch sig.Name {
+	case InterfacesAdded:
+		if len(sig.Body) < 2 {
+			return
+		}
+		path, ok := sig.Body[0].(dbus.ObjectPath)
+		if !ok {
+			return
+		}
+		ifaces, ok := sig.Body[1].(map[string]map[string]dbus.Variant)
+		if !ok {
+			return
+		}
+		c.lock.Lock()
+		if _, ok := c.objects[path]; !ok {
+			c.objects[path] = make(map[string]map[string]dbus.Variant)
+		}
+		for iface, props := range ifaces {
+			c.objects[path][iface] = props
+		}
+		c.lock.Unlock()
+
+	case InterfacesRemoved:
+		if len(sig.Body) < 2 {
+			return
+		}
+		path, ok := sig.Body[0].(dbus.ObjectPath)
+		if !ok {
+			return
+		}
+		ifaces, ok := sig.Body[1].([]string)
+		if !ok {
+			return
+		}
+		c.lock.Lock()
+		if obj, ok := c.objects[path]; ok {
+			for _, iface := range ifaces {
+				delete(obj, iface)
+			}
+			if len(obj) == 0 {
+				delete(c.objects, path)
+			}
+		}
+		c.lock.Unlock()
+
+	case PropertiesChanged:
+		if len(sig.Body) < 2 {
+			return
+		}
+		iface, ok := sig.Body[0].(string)
+		if !ok {
+			return
+		}
+		changed, ok := sig.Body[1].(map[string]dbus.Variant)
+		if !ok {
+			return
+		}
+		var invalidated []string
+		if len(sig.Body) > 2 {
+			invalidated, _ = sig.Body[2].([]string)
+		}
+
+		c.lock.Lock()
+		if obj, ok := c.objects[sig.Path]; ok {
+			if props, ok := obj[iface]; ok {
+				// copy on write, snapshots returned to callers are not modified
+				updated := make(map[string]dbus.Variant, len(props)+len(changed))
+				for k, v := range props {
+					updated[k] = v
+				}
+				for k, v := range changed {
+					updated[k] = v
+				}
+				for _, k := range invalidated {
+					delete(updated, k)
+				}
+				obj[iface] = updated
+			}
+		}
+		c.lock.Unlock()
+	}
+}
+
+// GetManagedObjects return a copy of all the cached objects
+func (c *ObjectCache) GetManagedObjects() (map[dbus.ObjectPath]map[string]map[string]dbus.Variant, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	objects := make(map[dbus.ObjectPath]map[string]map[string]dbus.Variant, len(c.objects))
+	for path, obj := range c.objects {
+		objects[path] = copyObject(obj)
+	}
+
+	return objects, nil
+}
+
+// GetManagedObject return a copy of a single cached object.
+// object is nil if the object path is not found
+func (c *ObjectCache) GetManagedObject(objpath dbus.ObjectPath) (map[string]map[string]dbus.Variant, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if obj, ok := c.objects[objpath]; ok {
+		return copyObject(obj), nil
+	}
+
+	return nil, nil
+}
+
+// Get return the properties of an object interface
+func (c *ObjectCache) Get(objpath dbus.ObjectPath, iface string) (map[string]dbus.Variant, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if obj, ok := c.objects[objpath]; ok {
+		if props, ok := obj[iface]; ok {
+			return props, true
+		}
+	}
+
+	return nil, false
+}
+
+// Find return the objects implementing iface below the parent path,
+// an empty parent matches all the objects. The list is sorted by path
+func (c *ObjectCache) Find(iface string, parent dbus.ObjectPath) []CachedObject {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	prefix := strings.TrimRight(string(parent), "/") + "/"
+
+	list := []CachedObject{}
+	for path, obj := range c.objects {
+		props, ok := obj[iface]
+		if !ok {
+			continue
+		}
+		if parent != "" && !strings.HasPrefix(string(path), prefix) {
+			continue
+		}
+		list = append(list, CachedObject{
+			Path:       path,
+			Interface:  iface,
+			Properties: props,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Path < list[j].Path
+	})
+
+	return list
+}
+
+// Adapters return the cached adapters
+func (c *ObjectCache) Adapters() []CachedObject {
+	return c.Find(adapterInterface, "")
+}
+
+// Devices return the cached devices of an adapter, an empty adapterPath return all the devices
+func (c *ObjectCache) Devices(adapterPath dbus.ObjectPath) []CachedObject {
+	return c.Find(deviceInterface, adapterPath)
+}
+
+// Services return the cached GATT services of a device
+func (c *ObjectCache) Services(devicePath dbus.ObjectPath) []CachedObject {
+	return c.Find(gattServiceInterface, devicePath)
+}
+
+// Characteristics return the cached GATT characteristics below a device or a service path
+func (c *ObjectCache) Characteristics(parent dbus.ObjectPath) []CachedObject {
+	return c.Find(gattCharInterface, parent)
+}
+
+// Descriptors return the cached GATT descriptors below a device, service or characteristic path
+func (c *ObjectCache) Descriptors(parent dbus.ObjectPath) []CachedObject {
+	return c.Find(gattDescriptorInterface, parent)
+}
+
+func copyObject(obj map[string]map[string]dbus.Variant) map[string]map[string]dbus.Variant {
+	res := make(map[string]map[string]dbus.Variant, len(obj))
+	for iface, props := range obj {
+		res[iface] = props
+	}
+	return res
+}
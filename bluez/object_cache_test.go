@@ -0,0 +1,158 @@
+package bluez
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestObjectCache() *ObjectCache {
+	c := &ObjectCache{
+		objects: make(map[dbus.ObjectPath]map[string]map[string]dbus.Variant),
+	}
+	c.load(map[dbus.ObjectPath]map[string]map[string]dbus.Variant{
+		"/org/bluez/hci0": {
+			adapterInterface: {"Address": dbus.MakeVariant("00:00:00:00:00:01")},
+		},
+		"/org/bluez/hci0/dev_00_00_00_00_00_02": {
+			deviceInterface: {"Connected": dbus.MakeVariant(false)},
+		},
+		"/org/bluez/hci0/dev_00_00_00_00_00_02/service0001": {
+			gattServiceInterface: {"UUID": dbus.MakeVariant("0000180f-0000-1000-8000-00805f9b34fb")},
+		},
+		"/org/bluez/hci0/dev_00_00_00_00_00_02/service0001/char0002": {
+			gattCharInterface: {"UUID": dbus.MakeVariant("00002a19-0000-1000-8000-00805f9b34fb")},
+		},
+	})
+	return c
+}
+
+func TestObjectCacheAccessors(t *testing.T) {
+	c := newTestObjectCache()
+
+	assert.Len(t, c.Adapters(), 1)
+	assert.Len(t, c.Devices("/org/bluez/hci0"), 1)
+	assert.Len(t, c.Devices("/org/bluez/hci1"), 0)
+	assert.Len(t, c.Services("/org/bluez/hci0/dev_00_00_00_00_00_02"), 1)
+	assert.Len(t, c.Characteristics("/org/bluez/hci0/dev_00_00_00_00_00_02"), 1)
+	assert.Len(t, c.Descriptors(""), 0)
+
+	obj, err := c.GetManagedObject("/org/bluez/hci0/dev_00_00_00_00_00_03")
+	assert.NoError(t, err)
+	assert.Nil(t, obj)
+}
+
+func TestObjectCacheSignals(t *testing.T) {
+	c := newTestObjectCache()
+
+	devPath := dbus.ObjectPath("/org/bluez/hci0/dev_00_00_00_00_00_03")
+
+	c.handleSignal(&dbus.Signal{
+		Path: "/",
+		Name: InterfacesAdded,
+		Body: []interface{}{
+			devPath,
+			map[string]map[string]dbus.Variant{
+				deviceInterface: {"RSSI": dbus.MakeVariant(int16(-50))},
+			},
+		},
+	})
+	assert.Len(t, c.Devices(""), 2)
+
+	snapshot, _ := c.Get(devPath, deviceInterface)
+
+	c.handleSignal(&dbus.Signal{
+		Path: devPath,
+		Name: PropertiesChanged,
+		Body: []interface{}{
+			deviceInterface,
+			map[string]dbus.Variant{"Connected": dbus.MakeVariant(true)},
+			[]string{"RSSI"},
+		},
+	})
+
+	props, ok := c.Get(devPath, deviceInterface)
+	assert.True(t, ok)
+	assert.Equal(t, true, props["Connected"].Value())
+	_, ok = props["RSSI"]
+	assert.False(t, ok)
+	// previous snapshots are not modified
+	assert.Equal(t, int16(-50), snapshot["RSSI"].Value())
+
+	c.handleSignal(&dbus.Signal{
+		Path: "/",
+		Name: InterfacesRemoved,
+		Body: []interface{}{devPath, []string{deviceInterface}},
+	})
+	assert.Len(t, c.Devices(""), 1)
+}
+
+func TestObjectCacheWatchQuit(t *testing.T) {
+	c := newTestObjectCache()
+	ch := make(chan *dbus.Signal)
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		c.watch(ch, quit, nil, nil)
+		close(done)
+	}()
+	close(quit)
+	<-done
+
+	// not started, Close is a no-op and can be repeated
+	c.Close()
+	c.Close()
+}
+
+func TestObjectCacheWatchLoad(t *testing.T) {
+	c := &ObjectCache{
+		objects: make(map[dbus.ObjectPath]map[string]map[string]dbus.Variant),
+	}
+	ch := make(chan *dbus.Signal)
+	quit := make(chan struct{})
+	defer close(quit)
+	load := make(chan map[dbus.ObjectPath]map[string]map[string]dbus.Variant)
+	loaded := make(chan struct{})
+	go c.watch(ch, quit, load, loaded)
+
+	devPath := dbus.ObjectPath("/org/bluez/hci0/dev_00_00_00_00_00_03")
+
+	// signals received while loading are all queued and applied in order
+	for i := 0; i < DefaultSignalBufferSize*2; i++ {
+		ch <- &dbus.Signal{
+			Path: "/",
+			Name: InterfacesAdded,
+			Body: []interface{}{
+				devPath,
+				map[string]map[string]dbus.Variant{
+					deviceInterface: {"RSSI": dbus.MakeVariant(int16(-i))},
+				},
+			},
+		}
+	}
+	ch <- &dbus.Signal{
+		Path: "/",
+		Name: InterfacesRemoved,
+		Body: []interface{}{dbus.ObjectPath("/org/bluez/hci0/dev_00_00_00_00_00_02"), []string{deviceInterface}},
+	}
+	assert.Len(t, c.Devices(""), 0)
+
+	load <- newTestObjectCache().objects
+	<-loaded
+
+	devices := c.Devices("")
+	assert.Len(t, devices, 1)
+	assert.Equal(t, devPath, devices[0].Path)
+	assert.Equal(t, int16(-(DefaultSignalBufferSize*2 - 1)), devices[0].Properties["RSSI"].Value())
+	assert.Len(t, c.Adapters(), 1)
+}
+
+func TestCloseConnectionsObjectCache(t *testing.T) {
+	objectCacheLock.Lock()
+	objectCache = newTestObjectCache()
+	objectCacheLock.Unlock()
+
+	assert.NoError(t, CloseConnections())
+	assert.Nil(t, getObjectCacheWithConn(nil))
+}
@@ -1,13 +1,20 @@
 package bluez
 
 import (
+	"sync"
+
 	"github.com/godbus/dbus/v5"
 )
 
 var objectManager *ObjectManager
+var objectManagerLock sync.Mutex
 
 // GetObjectManager return a client instance of the Bluez object manager
+// using the shared connection
 func GetObjectManager() (*ObjectManager, error) {
+	objectManagerLock.Lock()
+	defer objectManagerLock.Unlock()
+
 	if objectManager != nil {
 		return objectManager, nil
 	}
@@ -21,8 +28,23 @@ func GetObjectManager() (*ObjectManager, error) {
 	return om, nil
 }
 
+// GetObjectManagerWithConn return a client instance of the Bluez object manager
+// bound to conn. A nil conn returns the shared instance
+func GetObjectManagerWithConn(conn *Conn) (*ObjectManager, error) {
+	if conn == nil {
+		return GetObjectManager()
+	}
+	return conn.GetObjectManager()
+}
+
 // NewObjectManager create a new ObjectManager client
 func NewObjectManager(name string, path string) (*ObjectManager, error) {
+	return NewObjectManagerWithConn(nil, name, path)
+}
+
+// NewObjectManagerWithConn create a new ObjectManager client using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewObjectManagerWithConn(conn *Conn, name string, path string) (*ObjectManager, error) {
 	om := new(ObjectManager)
 	om.client = NewClient(
 		&Config{
@@ -30,6 +52,7 @@ func NewObjectManager(name string, path string) (*ObjectManager, error) {
 			Iface: "org.freedesktop.DBus.ObjectManager",
 			Path:  dbus.ObjectPath(path),
 			Bus:   SystemBus,
+			Conn:  conn,
 		},
 	)
 	return om, nil
@@ -42,8 +42,13 @@ func ParseAdapterID(path dbus.ObjectPath) (string, error) {
 
 // AdapterExists checks if an adapter is available
 func AdapterExists(adapterID string) (bool, error) {
+	return AdapterExistsWithConn(nil, adapterID)
+}
+
+// AdapterExistsWithConn checks if an adapter is available using the provided connection
+func AdapterExistsWithConn(conn *bluez.Conn, adapterID string) (bool, error) {
 
-	om, err := bluez.GetObjectManager()
+	om, err := bluez.GetObjectsProviderWithConn(conn)
 	if err != nil {
 		return false, err
 	}
@@ -65,15 +70,20 @@ func GetDefaultAdapter() (*Adapter1, error) {
 
 // GetAdapter return an adapter object instance
 func GetAdapter(adapterID string) (*Adapter1, error) {
+	return GetAdapterWithConn(nil, adapterID)
+}
+
+// GetAdapterWithConn return an adapter object instance using the provided connection
+func GetAdapterWithConn(conn *bluez.Conn, adapterID string) (*Adapter1, error) {
 
-	if exists, err := AdapterExists(adapterID); !exists {
+	if exists, err := AdapterExistsWithConn(conn, adapterID); !exists {
 		if err != nil {
 			return nil, fmt.Errorf("AdapterExists: %s", err)
 		}
 		return nil, fmt.Errorf("Adapter %s not found", adapterID)
 	}
 
-	return NewAdapter1FromAdapterID(adapterID)
+	return NewAdapter1FromAdapterIDWithConn(conn, adapterID)
 }
 
 // GetAdapterFromDevicePath Return an adapter based on a device path
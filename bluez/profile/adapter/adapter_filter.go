@@ -1,6 +1,9 @@
 package adapter
 
-import "github.com/dimonzozo/go-bluetooth/util"
+import (
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/util"
+)
 
 const (
 	DiscoveryFilterTransportAuto  = "auto"
@@ -68,7 +71,7 @@ type DiscoveryFilter struct {
 
 func (a *DiscoveryFilter) uuidExists(uuid string) bool {
 	for _, uiid1 := range a.UUIDs {
-		if uiid1 == uuid {
+		if bluez.EqualUUID(uiid1, uuid) {
 			return true
 		}
 	}
@@ -84,6 +87,13 @@ func (a *DiscoveryFilter) AddUUIDs(uuids ...string) {
 	}
 }
 
+// AddServiceUUIDs add typed UUIDs to filter if they do not exist
+func (a *DiscoveryFilter) AddServiceUUIDs(uuids ...bluez.UUID) {
+	for _, uuid := range uuids {
+		a.AddUUIDs(uuid.String())
+	}
+}
+
 // ToMap convert to a format compatible with SetDiscoveryFilter method call
 func (a *DiscoveryFilter) ToMap() map[string]interface{} {
 
@@ -99,6 +109,9 @@ func (a *DiscoveryFilter) ToMap() map[string]interface{} {
 	if a.Pathloss == 0 {
 		delete(m, "Pathloss")
 	}
+	if a.Transport == "" {
+		delete(m, "Transport")
+	}
 
 	return m
 }
@@ -111,3 +124,59 @@ func NewDiscoveryFilter() DiscoveryFilter {
 		Transport:     DiscoveryFilterTransportAuto,
 	}
 }
+
+// MergeDiscoveryFilters return a filter matching the union of the devices
+// matched by the filters, as Bluez support a single filter per client.
+// Thresholds are dropped if a filter does not set them
+func MergeDiscoveryFilters(filters ...DiscoveryFilter) DiscoveryFilter {
+
+	merged := DiscoveryFilter{}
+	if len(filters) == 0 {
+		return merged
+	}
+
+	anyUUID := false
+	anyRSSI := false
+	anyPathloss := false
+
+	for i, f := range filters {
+
+		if len(f.UUIDs) == 0 {
+			anyUUID = true
+		} else {
+			merged.AddUUIDs(f.UUIDs...)
+		}
+
+		if f.RSSI == 0 {
+			anyRSSI = true
+		} else if merged.RSSI == 0 || f.RSSI < merged.RSSI {
+			merged.RSSI = f.RSSI
+		}
+
+		if f.Pathloss == 0 {
+			anyPathloss = true
+		} else if f.Pathloss > merged.Pathloss {
+			merged.Pathloss = f.Pathloss
+		}
+
+		if i == 0 {
+			merged.Transport = f.Transport
+		} else if merged.Transport != f.Transport {
+			merged.Transport = DiscoveryFilterTransportAuto
+		}
+
+		merged.DuplicateData = merged.DuplicateData || f.DuplicateData
+	}
+
+	if anyUUID {
+		merged.UUIDs = nil
+	}
+	if anyRSSI {
+		merged.RSSI = 0
+	}
+	if anyPathloss {
+		merged.Pathloss = 0
+	}
+
+	return merged
+}
@@ -24,3 +24,40 @@ func TestDiscoveryFilter(t *testing.T) {
 	assert.EqualValues(t, m["Transport"].(string), f.Transport)
 
 }
+
+func TestMergeDiscoveryFilters(t *testing.T) {
+
+	f1 := NewDiscoveryFilter()
+	f1.AddUUIDs("AAAA")
+	f1.RSSI = -60
+	f1.DuplicateData = false
+	f1.Transport = DiscoveryFilterTransportLE
+
+	f2 := NewDiscoveryFilter()
+	f2.AddUUIDs("BBBB", "AAAA")
+	f2.RSSI = -80
+	f2.DuplicateData = false
+	f2.Transport = DiscoveryFilterTransportLE
+
+	m := MergeDiscoveryFilters(f1, f2)
+	assert.Equal(t, []string{"AAAA", "BBBB"}, m.UUIDs)
+	assert.Equal(t, int16(-80), m.RSSI)
+	assert.False(t, m.DuplicateData)
+	assert.Equal(t, DiscoveryFilterTransportLE, m.Transport)
+
+	// a filter without constraints matches any device
+	f3 := NewDiscoveryFilter()
+	m = MergeDiscoveryFilters(f1, f2, f3)
+	assert.Len(t, m.UUIDs, 0)
+	assert.Equal(t, int16(0), m.RSSI)
+	assert.True(t, m.DuplicateData)
+	assert.Equal(t, DiscoveryFilterTransportAuto, m.Transport)
+
+	f4 := NewDiscoveryFilter()
+	f4.Pathloss = 10
+	m = MergeDiscoveryFilters(f1, f4)
+	assert.Equal(t, int16(0), m.RSSI)
+	assert.Equal(t, uint16(0), m.Pathloss)
+
+	assert.Equal(t, f1, MergeDiscoveryFilters(f1))
+}
@@ -3,6 +3,7 @@ package adapter
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -19,6 +20,12 @@ var Adapter1Interface = "org.bluez.Adapter1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}
 func NewAdapter1(objectPath dbus.ObjectPath) (*Adapter1, error) {
+	return NewAdapter1WithConn(nil, objectPath)
+}
+
+// NewAdapter1WithConn create a new instance of Adapter1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewAdapter1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*Adapter1, error) {
 	a := new(Adapter1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -26,6 +33,7 @@ func NewAdapter1(objectPath dbus.ObjectPath) (*Adapter1, error) {
 			Iface: Adapter1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -42,6 +50,12 @@ func NewAdapter1(objectPath dbus.ObjectPath) (*Adapter1, error) {
 // NewAdapter1FromAdapterID create a new instance of Adapter1
 // adapterID: ID of an adapter eg. hci0
 func NewAdapter1FromAdapterID(adapterID string) (*Adapter1, error) {
+	return NewAdapter1FromAdapterIDWithConn(nil, adapterID)
+}
+
+// NewAdapter1FromAdapterIDWithConn create a new instance of Adapter1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewAdapter1FromAdapterIDWithConn(conn *bluez.Conn, adapterID string) (*Adapter1, error) {
 	a := new(Adapter1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -49,6 +63,7 @@ func NewAdapter1FromAdapterID(adapterID string) (*Adapter1, error) {
 			Iface: Adapter1Interface,
 			Path:  dbus.ObjectPath(fmt.Sprintf("/org/bluez/%s", adapterID)),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -469,7 +484,7 @@ func (a *Adapter1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error) {
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -558,6 +573,7 @@ func (a *Adapter1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *Adapter1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -592,8 +608,13 @@ StartDiscovery
 
 */
 func (a *Adapter1) StartDiscovery() error {
+	return a.StartDiscoveryContext(context.Background())
+}
+
+// StartDiscoveryContext call StartDiscovery, the call is aborted when ctx is done
+func (a *Adapter1) StartDiscoveryContext(ctx context.Context) error {
 	
-	return a.client.Call("StartDiscovery", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "StartDiscovery", 0, ).Store())
 	
 }
 
@@ -613,8 +634,13 @@ StopDiscovery
 
 */
 func (a *Adapter1) StopDiscovery() error {
+	return a.StopDiscoveryContext(context.Background())
+}
+
+// StopDiscoveryContext call StopDiscovery, the call is aborted when ctx is done
+func (a *Adapter1) StopDiscoveryContext(ctx context.Context) error {
 	
-	return a.client.Call("StopDiscovery", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "StopDiscovery", 0, ).Store())
 	
 }
 
@@ -629,8 +655,13 @@ RemoveDevice
 
 */
 func (a *Adapter1) RemoveDevice(device dbus.ObjectPath) error {
+	return a.RemoveDeviceContext(context.Background(), device)
+}
+
+// RemoveDeviceContext call RemoveDevice, the call is aborted when ctx is done
+func (a *Adapter1) RemoveDeviceContext(ctx context.Context, device dbus.ObjectPath) error {
 	
-	return a.client.Call("RemoveDevice", 0, device).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RemoveDevice", 0, device).Store())
 	
 }
 
@@ -730,8 +761,13 @@ SetDiscoveryFilter
 
 */
 func (a *Adapter1) SetDiscoveryFilter(filter map[string]interface{}) error {
+	return a.SetDiscoveryFilterContext(context.Background(), filter)
+}
+
+// SetDiscoveryFilterContext call SetDiscoveryFilter, the call is aborted when ctx is done
+func (a *Adapter1) SetDiscoveryFilterContext(ctx context.Context, filter map[string]interface{}) error {
 	
-	return a.client.Call("SetDiscoveryFilter", 0, filter).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "SetDiscoveryFilter", 0, filter).Store())
 	
 }
 
@@ -745,10 +781,15 @@ GetDiscoveryFilters
 
 */
 func (a *Adapter1) GetDiscoveryFilters() ([]string, error) {
+	return a.GetDiscoveryFiltersContext(context.Background())
+}
+
+// GetDiscoveryFiltersContext call GetDiscoveryFilters, the call is aborted when ctx is done
+func (a *Adapter1) GetDiscoveryFiltersContext(ctx context.Context) ([]string, error) {
 	
 	var val0 []string
-	err := a.client.Call("GetDiscoveryFilters", 0, ).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "GetDiscoveryFilters", 0, ).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -792,9 +833,14 @@ ConnectDevice
 
 */
 func (a *Adapter1) ConnectDevice(properties map[string]interface{}) (dbus.ObjectPath, error) {
+	return a.ConnectDeviceContext(context.Background(), properties)
+}
+
+// ConnectDeviceContext call ConnectDevice, the call is aborted when ctx is done
+func (a *Adapter1) ConnectDeviceContext(ctx context.Context, properties map[string]interface{}) (dbus.ObjectPath, error) {
 	
 	var val0 dbus.ObjectPath
-	err := a.client.Call("ConnectDevice", 0, properties).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "ConnectDevice", 0, properties).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
@@ -0,0 +1,222 @@
+package advertising
+
+import (
+	"fmt"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/godbus/dbus/v5"
+)
+
+// SecondaryChannel values, an advertisement with a secondary channel set
+// use extended advertising
+const (
+	SecondaryChannel1M    = "1M"
+	SecondaryChannel2M    = "2M"
+	SecondaryChannelCoded = "Coded"
+)
+
+// SupportedFeatures values
+const (
+	SupportedFeaturesCanSetTxPower   = "CanSetTxPower"
+	SupportedFeaturesHardwareOffload = "HardwareOffload"
+)
+
+const (
+	// MaxLegacyDataLength is the max advertising data length of legacy advertising
+	MaxLegacyDataLength = 31
+	// MaxExtendedDataLength is the max advertising data length of extended
+	// advertising accepted by the kernel in a single HCI command
+	MaxExtendedDataLength = 251
+)
+
+// TxPower range, in dBm
+const (
+	MinTxPower = -127
+	MaxTxPower = 20
+)
+
+// MinAdvertisingInterval is the lowest MinInterval and MaxInterval accepted by
+// Bluez, in milliseconds
+const MinAdvertisingInterval = 20
+
+// IsExtended return true if the advertisement use extended advertising
+func (a *LEAdvertisement1Properties) IsExtended() bool {
+	return a.SecondaryChannel != ""
+}
+
+// MaxDataLength return the max advertising data length for the advertisement
+func (a *LEAdvertisement1Properties) MaxDataLength() int {
+	if a.IsExtended() {
+		return MaxExtendedDataLength
+	}
+	return MaxLegacyDataLength
+}
+
+// DataLength return the length of the advertising data, as AD structures of
+// one byte length, one byte type and the value
+func (a *LEAdvertisement1Properties) DataLength() (int, error) {
+
+	size := 0
+
+	// flags are added for discoverable and connectable advertisements
+	if a.Discoverable || a.Type == AdvertisementTypePeripheral {
+		size += 3
+	}
+
+	for _, uuids := range [][]string{a.ServiceUUIDs, a.SolicitUUIDs} {
+		l, err := uuidListLength(uuids)
+		if err != nil {
+			return 0, err
+		}
+		size += l
+	}
+
+	for uuid, data := range a.ServiceData {
+		u, err := bluez.ParseUUID(uuid)
+		if err != nil {
+			return 0, err
+		}
+		b, err := dataBytes(data)
+		if err != nil {
+			return 0, fmt.Errorf("ServiceData %s: %s", uuid, err)
+		}
+		size += 2 + uuidLength(u) + len(b)
+	}
+
+	for id, data := range a.ManufacturerData {
+		b, err := dataBytes(data)
+		if err != nil {
+			return 0, fmt.Errorf("ManufacturerData %d: %s", id, err)
+		}
+		size += 4 + len(b)
+	}
+
+	for code, data := range a.Data {
+		b, err := dataBytes(data)
+		if err != nil {
+			return 0, fmt.Errorf("Data 0x%02x: %s", code, err)
+		}
+		size += 2 + len(b)
+	}
+
+	hasAppearance := a.Appearance != 0
+	for _, include := range a.Includes {
+		switch include {
+		case SupportedIncludesTxPower:
+			size += 3
+		case SupportedIncludesAppearance:
+			hasAppearance = true
+		}
+	}
+	if hasAppearance {
+		size += 4
+	}
+
+	if a.LocalName != "" {
+		size += 2 + len(a.LocalName)
+	}
+
+	return size, nil
+}
+
+// Validate check the advertisement properties and the advertising data
+// length against the legacy or extended limit. When manager is not nil the
+// secondary channel and the tx power are checked against the adapter
+// supported features
+func (a *LEAdvertisement1Properties) Validate(manager *LEAdvertisingManager1Properties) error {
+
+	switch a.SecondaryChannel {
+	case "", SecondaryChannel1M, SecondaryChannel2M, SecondaryChannelCoded:
+	default:
+		return fmt.Errorf("Invalid SecondaryChannel %s", a.SecondaryChannel)
+	}
+
+	if a.MinInterval != 0 && a.MinInterval < MinAdvertisingInterval {
+		return fmt.Errorf("MinInterval %dms is lower than %dms", a.MinInterval, MinAdvertisingInterval)
+	}
+
+	if a.MaxInterval != 0 && a.MaxInterval < MinAdvertisingInterval {
+		return fmt.Errorf("MaxInterval %dms is lower than %dms", a.MaxInterval, MinAdvertisingInterval)
+	}
+
+	if a.MinInterval != 0 && a.MaxInterval != 0 && a.MinInterval > a.MaxInterval {
+		return fmt.Errorf("MinInterval %dms is greater than MaxInterval %dms", a.MinInterval, a.MaxInterval)
+	}
+
+	if a.TxPower != nil && (*a.TxPower < MinTxPower || *a.TxPower > MaxTxPower) {
+		return fmt.Errorf("TxPower %d out of range [%d, %d]", *a.TxPower, MinTxPower, MaxTxPower)
+	}
+
+	if manager != nil {
+		if a.SecondaryChannel != "" && !contains(manager.SupportedSecondaryChannels, a.SecondaryChannel) {
+			return fmt.Errorf("SecondaryChannel %s not supported by the adapter", a.SecondaryChannel)
+		}
+		if a.TxPower != nil && !contains(manager.SupportedFeatures, SupportedFeaturesCanSetTxPower) {
+			return fmt.Errorf("TxPower not supported by the adapter")
+		}
+	}
+
+	size, err := a.DataLength()
+	if err != nil {
+		return err
+	}
+
+	max := a.MaxDataLength()
+	if size > max {
+		kind := "legacy"
+		if a.IsExtended() {
+			kind = "extended"
+		}
+		return fmt.Errorf("Advertising data is %d bytes, max %d for %s advertising", size, max, kind)
+	}
+
+	return nil
+}
+
+// uuidListLength return the length of the AD structures of a UUID list,
+// one for each UUID size
+func uuidListLength(uuids []string) (int, error) {
+	counts := map[int]int{}
+	for _, uuid := range uuids {
+		u, err := bluez.ParseUUID(uuid)
+		if err != nil {
+			return 0, err
+		}
+		counts[uuidLength(u)]++
+	}
+	size := 0
+	for l, count := range counts {
+		size += 2 + l*count
+	}
+	return size, nil
+}
+
+// uuidLength return the length of the shortest form of a UUID
+func uuidLength(u bluez.UUID) int {
+	if _, ok := u.Uint16(); ok {
+		return 2
+	}
+	if _, ok := u.Uint32(); ok {
+		return 4
+	}
+	return 16
+}
+
+func dataBytes(data interface{}) ([]byte, error) {
+	switch v := data.(type) {
+	case []byte:
+		return v, nil
+	case dbus.Variant:
+		return dataBytes(v.Value())
+	}
+	return nil, fmt.Errorf("unexpected value type %T", data)
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package advertising
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataLength(t *testing.T) {
+
+	props := &LEAdvertisement1Properties{
+		Type:      AdvertisementTypePeripheral,
+		LocalName: "test",
+	}
+	props.AddServiceUUID("180d", "180f", "12345678-1234-1234-1234-123456789abc")
+	props.AddManifacturerData(0x004c, []byte{0x02, 0x15})
+	props.AddServiceData("feaa", []byte{0x00, 0x01, 0x02})
+	props.Data = map[byte]interface{}{0x16: dbus.MakeVariant([]byte{0x01})}
+
+	size, err := props.DataLength()
+	assert.NoError(t, err)
+	// flags 3, 16 bit uuids 6, 128 bit uuid 18, manufacturer 6,
+	// service data 7, data 3, name 6
+	assert.Equal(t, 49, size)
+}
+
+func TestDataLengthInvalid(t *testing.T) {
+	props := &LEAdvertisement1Properties{}
+	props.AddServiceUUID("not-a-uuid")
+	_, err := props.DataLength()
+	assert.Error(t, err)
+
+	props = &LEAdvertisement1Properties{
+		Data: map[byte]interface{}{0x16: "string"},
+	}
+	_, err = props.DataLength()
+	assert.Error(t, err)
+}
+
+func TestValidateLegacyExtended(t *testing.T) {
+
+	props := &LEAdvertisement1Properties{
+		Type: AdvertisementTypeBroadcast,
+	}
+	props.AddManifacturerData(0xffff, make([]byte, 27))
+	assert.NoError(t, props.Validate(nil))
+
+	props.AddManifacturerData(0xffff, make([]byte, 28))
+	assert.Error(t, props.Validate(nil))
+
+	props.SecondaryChannel = SecondaryChannel2M
+	assert.NoError(t, props.Validate(nil))
+
+	props.AddManifacturerData(0xffff, make([]byte, 248))
+	assert.Error(t, props.Validate(nil))
+}
+
+func TestValidateProperties(t *testing.T) {
+
+	props := &LEAdvertisement1Properties{SecondaryChannel: "3M"}
+	assert.Error(t, props.Validate(nil))
+
+	props = &LEAdvertisement1Properties{MinInterval: 200, MaxInterval: 100}
+	assert.Error(t, props.Validate(nil))
+
+	props = &LEAdvertisement1Properties{MinInterval: 10, MaxInterval: 100}
+	assert.Error(t, props.Validate(nil))
+
+	props = &LEAdvertisement1Properties{MaxInterval: 19}
+	assert.Error(t, props.Validate(nil))
+
+	props = &LEAdvertisement1Properties{MinInterval: 20, MaxInterval: 20}
+	assert.NoError(t, props.Validate(nil))
+
+	txPower := int16(21)
+	props = &LEAdvertisement1Properties{TxPower: &txPower}
+	assert.Error(t, props.Validate(nil))
+}
+
+func TestTxPowerZero(t *testing.T) {
+
+	// 0 dBm is a valid tx power, not an unset one
+	txPower := int16(0)
+	props := &LEAdvertisement1Properties{TxPower: &txPower}
+
+	m, err := props.ToMap()
+	assert.NoError(t, err)
+	assert.Contains(t, m, "TxPower")
+
+	manager := &LEAdvertisingManager1Properties{}
+	assert.Error(t, props.Validate(manager))
+
+	props.TxPower = nil
+	m, err = props.ToMap()
+	assert.NoError(t, err)
+	assert.NotContains(t, m, "TxPower")
+	assert.NoError(t, props.Validate(manager))
+}
+
+func TestValidateManager(t *testing.T) {
+
+	props := &LEAdvertisement1Properties{
+		SecondaryChannel: SecondaryChannelCoded,
+	}
+	txPower := int16(-10)
+	props.TxPower = &txPower
+
+	manager := &LEAdvertisingManager1Properties{}
+	assert.Error(t, props.Validate(manager))
+
+	manager.SupportedSecondaryChannels = []string{SecondaryChannel1M, SecondaryChannelCoded}
+	assert.Error(t, props.Validate(manager))
+
+	manager.SupportedFeatures = []string{SupportedFeaturesCanSetTxPower}
+	assert.NoError(t, props.Validate(manager))
+}
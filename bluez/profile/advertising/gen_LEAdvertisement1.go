@@ -3,6 +3,7 @@ package advertising
 
 
 import (
+	"context"
 	"github.com/godbus/dbus/v5"
 	"github.com/dimonzozo/go-bluetooth/bluez"
 	"github.com/dimonzozo/go-bluetooth/props"
@@ -18,6 +19,12 @@ var LEAdvertisement1Interface = "org.bluez.LEAdvertisement1"
 // Args:
 // - objectPath: freely definable
 func NewLEAdvertisement1(objectPath dbus.ObjectPath) (*LEAdvertisement1, error) {
+	return NewLEAdvertisement1WithConn(nil, objectPath)
+}
+
+// NewLEAdvertisement1WithConn create a new instance of LEAdvertisement1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewLEAdvertisement1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*LEAdvertisement1, error) {
 	a := new(LEAdvertisement1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewLEAdvertisement1(objectPath dbus.ObjectPath) (*LEAdvertisement1, error)
 			Iface: LEAdvertisement1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -169,6 +177,26 @@ type LEAdvertisement1Properties struct {
 	*/
 	Discoverable bool
 
+	/*
+	MaxInterval 
+	*/
+	MaxInterval uint32 `dbus:"omitEmpty"`
+
+	/*
+	MinInterval 
+	*/
+	MinInterval uint32 `dbus:"omitEmpty"`
+
+	/*
+	SecondaryChannel 
+	*/
+	SecondaryChannel string `dbus:"omitEmpty"`
+
+	/*
+	TxPower 
+	*/
+	TxPower *int16 `dbus:"omitEmpty"`
+
 }
 
 //Lock access to properties
@@ -430,6 +458,82 @@ func (a *LEAdvertisement1) GetDiscoverable() (bool, error) {
 
 
 
+
+// SetMaxInterval set MaxInterval value
+func (a *LEAdvertisement1) SetMaxInterval(v uint32) error {
+	return a.SetProperty("MaxInterval", v)
+}
+
+
+
+// GetMaxInterval get MaxInterval value
+func (a *LEAdvertisement1) GetMaxInterval() (uint32, error) {
+	v, err := a.GetProperty("MaxInterval")
+	if err != nil {
+		return uint32(0), err
+	}
+	return v.Value().(uint32), nil
+}
+
+
+
+
+// SetMinInterval set MinInterval value
+func (a *LEAdvertisement1) SetMinInterval(v uint32) error {
+	return a.SetProperty("MinInterval", v)
+}
+
+
+
+// GetMinInterval get MinInterval value
+func (a *LEAdvertisement1) GetMinInterval() (uint32, error) {
+	v, err := a.GetProperty("MinInterval")
+	if err != nil {
+		return uint32(0), err
+	}
+	return v.Value().(uint32), nil
+}
+
+
+
+
+// SetSecondaryChannel set SecondaryChannel value
+func (a *LEAdvertisement1) SetSecondaryChannel(v string) error {
+	return a.SetProperty("SecondaryChannel", v)
+}
+
+
+
+// GetSecondaryChannel get SecondaryChannel value
+func (a *LEAdvertisement1) GetSecondaryChannel() (string, error) {
+	v, err := a.GetProperty("SecondaryChannel")
+	if err != nil {
+		return "", err
+	}
+	return v.Value().(string), nil
+}
+
+
+
+
+// SetTxPower set TxPower value
+func (a *LEAdvertisement1) SetTxPower(v int16) error {
+	return a.SetProperty("TxPower", v)
+}
+
+
+
+// GetTxPower get TxPower value
+func (a *LEAdvertisement1) GetTxPower() (int16, error) {
+	v, err := a.GetProperty("TxPower")
+	if err != nil {
+		return int16(0), err
+	}
+	return v.Value().(int16), nil
+}
+
+
+
 // Close the connection
 func (a *LEAdvertisement1) Close() {
 	
@@ -458,7 +562,7 @@ func (a *LEAdvertisement1) GetObjectManagerSignal() (chan *dbus.Signal, func(),
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -547,6 +651,7 @@ func (a *LEAdvertisement1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *LEAdvertisement1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -574,8 +679,13 @@ Release
 
 */
 func (a *LEAdvertisement1) Release() error {
+	return a.ReleaseContext(context.Background())
+}
+
+// ReleaseContext call Release, the call is aborted when ctx is done
+func (a *LEAdvertisement1) ReleaseContext(ctx context.Context) error {
 	
-	return a.client.Call("Release", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Release", 0, ).Store())
 	
 }
 
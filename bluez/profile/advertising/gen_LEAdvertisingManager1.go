@@ -3,6 +3,7 @@ package advertising
 
 
 import (
+	"context"
 	"fmt"
 	"github.com/godbus/dbus/v5"
 	"github.com/dimonzozo/go-bluetooth/bluez"
@@ -19,6 +20,12 @@ var LEAdvertisingManager1Interface = "org.bluez.LEAdvertisingManager1"
 // Args:
 // - objectPath: /org/bluez/{hci0,hci1,...}
 func NewLEAdvertisingManager1(objectPath dbus.ObjectPath) (*LEAdvertisingManager1, error) {
+	return NewLEAdvertisingManager1WithConn(nil, objectPath)
+}
+
+// NewLEAdvertisingManager1WithConn create a new instance of LEAdvertisingManager1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewLEAdvertisingManager1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*LEAdvertisingManager1, error) {
 	a := new(LEAdvertisingManager1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -26,6 +33,7 @@ func NewLEAdvertisingManager1(objectPath dbus.ObjectPath) (*LEAdvertisingManager
 			Iface: LEAdvertisingManager1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -42,6 +50,12 @@ func NewLEAdvertisingManager1(objectPath dbus.ObjectPath) (*LEAdvertisingManager
 // NewLEAdvertisingManager1FromAdapterID create a new instance of LEAdvertisingManager1
 // adapterID: ID of an adapter eg. hci0
 func NewLEAdvertisingManager1FromAdapterID(adapterID string) (*LEAdvertisingManager1, error) {
+	return NewLEAdvertisingManager1FromAdapterIDWithConn(nil, adapterID)
+}
+
+// NewLEAdvertisingManager1FromAdapterIDWithConn create a new instance of LEAdvertisingManager1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewLEAdvertisingManager1FromAdapterIDWithConn(conn *bluez.Conn, adapterID string) (*LEAdvertisingManager1, error) {
 	a := new(LEAdvertisingManager1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -49,6 +63,7 @@ func NewLEAdvertisingManager1FromAdapterID(adapterID string) (*LEAdvertisingMana
 			Iface: LEAdvertisingManager1Interface,
 			Path:  dbus.ObjectPath(fmt.Sprintf("/org/bluez/%s", adapterID)),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -102,6 +117,16 @@ type LEAdvertisingManager1Properties struct {
 	*/
 	SupportedIncludes []string
 
+	/*
+	SupportedFeatures 
+	*/
+	SupportedFeatures []string
+
+	/*
+	SupportedSecondaryChannels 
+	*/
+	SupportedSecondaryChannels []string
+
 }
 
 //Lock access to properties
@@ -173,6 +198,44 @@ func (a *LEAdvertisingManager1) GetSupportedIncludes() ([]string, error) {
 
 
 
+
+// SetSupportedFeatures set SupportedFeatures value
+func (a *LEAdvertisingManager1) SetSupportedFeatures(v []string) error {
+	return a.SetProperty("SupportedFeatures", v)
+}
+
+
+
+// GetSupportedFeatures get SupportedFeatures value
+func (a *LEAdvertisingManager1) GetSupportedFeatures() ([]string, error) {
+	v, err := a.GetProperty("SupportedFeatures")
+	if err != nil {
+		return []string{}, err
+	}
+	return v.Value().([]string), nil
+}
+
+
+
+
+// SetSupportedSecondaryChannels set SupportedSecondaryChannels value
+func (a *LEAdvertisingManager1) SetSupportedSecondaryChannels(v []string) error {
+	return a.SetProperty("SupportedSecondaryChannels", v)
+}
+
+
+
+// GetSupportedSecondaryChannels get SupportedSecondaryChannels value
+func (a *LEAdvertisingManager1) GetSupportedSecondaryChannels() ([]string, error) {
+	v, err := a.GetProperty("SupportedSecondaryChannels")
+	if err != nil {
+		return []string{}, err
+	}
+	return v.Value().([]string), nil
+}
+
+
+
 // Close the connection
 func (a *LEAdvertisingManager1) Close() {
 	
@@ -201,7 +264,7 @@ func (a *LEAdvertisingManager1) GetObjectManagerSignal() (chan *dbus.Signal, fun
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -290,6 +353,7 @@ func (a *LEAdvertisingManager1) GetPropertiesSignal() (chan *dbus.Signal, error)
 func (a *LEAdvertisingManager1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -333,8 +397,13 @@ RegisterAdvertisement
 
 */
 func (a *LEAdvertisingManager1) RegisterAdvertisement(advertisement dbus.ObjectPath, options map[string]interface{}) error {
+	return a.RegisterAdvertisementContext(context.Background(), advertisement, options)
+}
+
+// RegisterAdvertisementContext call RegisterAdvertisement, the call is aborted when ctx is done
+func (a *LEAdvertisingManager1) RegisterAdvertisementContext(ctx context.Context, advertisement dbus.ObjectPath, options map[string]interface{}) error {
 	
-	return a.client.Call("RegisterAdvertisement", 0, advertisement, options).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RegisterAdvertisement", 0, advertisement, options).Store())
 	
 }
 
@@ -350,8 +419,13 @@ UnregisterAdvertisement
 
 */
 func (a *LEAdvertisingManager1) UnregisterAdvertisement(advertisement dbus.ObjectPath) error {
+	return a.UnregisterAdvertisementContext(context.Background(), advertisement)
+}
+
+// UnregisterAdvertisementContext call UnregisterAdvertisement, the call is aborted when ctx is done
+func (a *LEAdvertisingManager1) UnregisterAdvertisementContext(ctx context.Context, advertisement dbus.ObjectPath) error {
 	
-	return a.client.Call("UnregisterAdvertisement", 0, advertisement).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "UnregisterAdvertisement", 0, advertisement).Store())
 	
 }
 
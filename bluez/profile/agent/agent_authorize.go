@@ -0,0 +1,400 @@
+package agent
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+	"github.com/godbus/dbus/v5"
+	log "github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Service rule actions
+const (
+	ServiceActionAllow  = "allow"
+	ServiceActionReject = "reject"
+)
+
+// ServiceRule match incoming service connections by device address, bonding
+// state and profile UUID. Empty fields match any value
+type ServiceRule struct {
+	// Address of the device, case insensitive. A trailing * match a prefix,
+	// eg. 00:11:22:*
+	Address string `yaml:"address"`
+	// Bonded match the device paired state when set
+	Bonded *bool `yaml:"bonded"`
+	// UUID of the profile in 16, 32 or 128 bit form
+	UUID string `yaml:"uuid"`
+	// Action is allow or reject
+	Action string `yaml:"action"`
+}
+
+// ServiceRules is a list of rules evaluated in order, the first matching
+// rule decide. Default is the action when no rule match, reject if empty
+type ServiceRules struct {
+	Default string        `yaml:"default"`
+	Rules   []ServiceRule `yaml:"rules"`
+}
+
+// Validate check actions and UUIDs of the rules
+func (r *ServiceRules) Validate() error {
+
+	if err := validateAction(r.Default, true); err != nil {
+		return fmt.Errorf("default: %s", err)
+	}
+
+	for i, rule := range r.Rules {
+		if err := validateAction(rule.Action, false); err != nil {
+			return fmt.Errorf("rule %d: %s", i, err)
+		}
+		if rule.UUID != "" {
+			if _, err := bluez.ParseUUID(rule.UUID); err != nil {
+				return fmt.Errorf("rule %d: %s", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateAction(action string, allowEmpty bool) error {
+	switch action {
+	case ServiceActionAllow, ServiceActionReject:
+		return nil
+	case "":
+		if allowEmpty {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid action \"%s\"", action)
+}
+
+// ParseServiceRules parse rules in YAML (or JSON) format
+func ParseServiceRules(b []byte) (*ServiceRules, error) {
+	rules := new(ServiceRules)
+	err := yaml.UnmarshalStrict(b, rules)
+	if err != nil {
+		return nil, err
+	}
+	err = rules.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// match return true if the rule match the connection, bonded is called only
+// when the rule has a bonding constraint
+func (rule *ServiceRule) match(address string, uuid string, bonded func() (bool, error)) (bool, error) {
+
+	if rule.Address != "" && rule.Address != "*" {
+		pattern := strings.ToUpper(rule.Address)
+		if strings.HasSuffix(pattern, "*") {
+			if !strings.HasPrefix(address, strings.TrimSuffix(pattern, "*")) {
+				return false, nil
+			}
+		} else if pattern != address {
+			return false, nil
+		}
+	}
+
+	if rule.UUID != "" && !bluez.EqualUUID(rule.UUID, uuid) {
+		return false, nil
+	}
+
+	if rule.Bonded != nil {
+		paired, err := bonded()
+		if err != nil {
+			return false, err
+		}
+		if *rule.Bonded != paired {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// AuditEntry record an AuthorizeService decision
+type AuditEntry struct {
+	Time    time.Time
+	Device  dbus.ObjectPath
+	Address string
+	UUID    string
+	// Bonded is the device paired state, false if it was not needed by the
+	// rules or could not be read
+	Bonded bool
+	Allow  bool
+	// Rule is the index of the matching rule, -1 for the default action
+	Rule int
+	// Err is set when the bonding state needed by Rule could not be read,
+	// the request is then rejected
+	Err error
+}
+
+func (e AuditEntry) String() string {
+	action := ServiceActionReject
+	if e.Allow {
+		action = ServiceActionAllow
+	}
+	rule := "default"
+	if e.Rule >= 0 {
+		rule = fmt.Sprintf("rule %d", e.Rule)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s %s service %s (bonded unknown: %s) by %s", action, e.Address, e.UUID, e.Err, rule)
+	}
+	return fmt.Sprintf("%s %s service %s (bonded %t) by %s", action, e.Address, e.UUID, e.Bonded, rule)
+}
+
+// NewServiceAuthorizer return an authorizer evaluating the rules
+func NewServiceAuthorizer(rules *ServiceRules) (*ServiceAuthorizer, error) {
+	a := &ServiceAuthorizer{
+		bonded: devicePaired,
+	}
+	err := a.SetRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// LoadServiceAuthorizer return an authorizer with the rules of a config file,
+// see Reload and Watch to update the rules when the file change
+func LoadServiceAuthorizer(filename string) (*ServiceAuthorizer, error) {
+	a := &ServiceAuthorizer{
+		bonded:   devicePaired,
+		filename: filename,
+	}
+	err := a.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ServiceAuthorizer decide AuthorizeService requests with a set of rules and
+// audit log every decision
+type ServiceAuthorizer struct {
+	lock     sync.RWMutex
+	rules    *ServiceRules
+	filename string
+	modTime  time.Time
+	bonded   func(device dbus.ObjectPath) (bool, error)
+	audit    func(entry AuditEntry)
+}
+
+// SetRules replace the rules
+func (a *ServiceAuthorizer) SetRules(rules *ServiceRules) error {
+	if rules == nil {
+		rules = new(ServiceRules)
+	}
+	err := rules.Validate()
+	if err != nil {
+		return err
+	}
+	a.lock.Lock()
+	a.rules = rules
+	a.lock.Unlock()
+	return nil
+}
+
+// Rules return the current rules
+func (a *ServiceAuthorizer) Rules() *ServiceRules {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.rules
+}
+
+// SetBondedFunc set the function returning the bonding state of a device,
+// by default the Paired property of the device
+func (a *ServiceAuthorizer) SetBondedFunc(fn func(device dbus.ObjectPath) (bool, error)) *ServiceAuthorizer {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.bonded = fn
+	return a
+}
+
+// OnAudit set a callback receiving every decision, in addition to the log
+func (a *ServiceAuthorizer) OnAudit(fn func(entry AuditEntry)) *ServiceAuthorizer {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.audit = fn
+	return a
+}
+
+// Reload the rules from the config file. On error the current rules are kept
+func (a *ServiceAuthorizer) Reload() error {
+
+	if a.filename == "" {
+		return fmt.Errorf("ServiceAuthorizer: no config file")
+	}
+
+	info, err := os.Stat(a.filename)
+	if err != nil {
+		return err
+	}
+
+	b, err := ioutil.ReadFile(a.filename)
+	if err != nil {
+		return err
+	}
+
+	rules, err := ParseServiceRules(b)
+	if err != nil {
+		return fmt.Errorf("%s: %s", a.filename, err)
+	}
+
+	a.lock.Lock()
+	a.rules = rules
+	a.modTime = info.ModTime()
+	a.lock.Unlock()
+
+	log.Infof("ServiceAuthorizer: loaded %d rules from %s", len(rules.Rules), a.filename)
+	return nil
+}
+
+// Watch reload the config file when its modification time change, checking
+// every interval. Call the returned function to stop watching
+func (a *ServiceAuthorizer) Watch(interval time.Duration) func() {
+
+	quit := make(chan bool)
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+				info, err := os.Stat(a.filename)
+				if err != nil {
+					log.Warnf("ServiceAuthorizer: %s", err)
+					continue
+				}
+				a.lock.RLock()
+				changed := !info.ModTime().Equal(a.modTime)
+				a.lock.RUnlock()
+				if !changed {
+					continue
+				}
+				err = a.Reload()
+				if err != nil {
+					log.Errorf("ServiceAuthorizer: reload failed, keeping previous rules: %s", err)
+					// do not retry until the file change again
+					a.lock.Lock()
+					a.modTime = info.ModTime()
+					a.lock.Unlock()
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(quit) })
+	}
+}
+
+// Authorize return nil if the rules allow the device to connect the service
+// UUID, bluez.ErrRejected otherwise. The request is rejected if a rule needs
+// the bonding state and it can not be read
+func (a *ServiceAuthorizer) Authorize(path dbus.ObjectPath, uuid string) error {
+
+	a.lock.RLock()
+	rules := a.rules
+	bondedFn := a.bonded
+	audit := a.audit
+	a.lock.RUnlock()
+
+	entry := AuditEntry{
+		Time:   time.Now(),
+		Device: path,
+		UUID:   bluez.ExpandUUID(uuid),
+		Rule:   -1,
+	}
+
+	address, err := device.ParseDeviceAddress(path)
+	if err != nil {
+		log.Warnf("ServiceAuthorizer: %s", err)
+	}
+	entry.Address = address
+
+	bondedLoaded := false
+	bonded := func() (bool, error) {
+		if !bondedLoaded {
+			bondedLoaded = true
+			entry.Bonded, entry.Err = bondedFn(path)
+			if entry.Err != nil {
+				entry.Bonded = false
+				log.Warnf("ServiceAuthorizer: bonding state of %s: %s", path, entry.Err)
+			}
+		}
+		return entry.Bonded, entry.Err
+	}
+
+	action := rules.Default
+	for i, rule := range rules.Rules {
+		matched, err := rule.match(address, uuid, bonded)
+		if err != nil {
+			// fail closed, the rule can not be evaluated
+			action = ServiceActionReject
+			entry.Rule = i
+			break
+		}
+		if matched {
+			action = rule.Action
+			entry.Rule = i
+			break
+		}
+	}
+	entry.Allow = action == ServiceActionAllow
+
+	log.Infof("AuthorizeService: %s", entry)
+	if audit != nil {
+		audit(entry)
+	}
+
+	if !entry.Allow {
+		return bluez.ErrRejected
+	}
+	return nil
+}
+
+func devicePaired(path dbus.ObjectPath) (bool, error) {
+	dev, err := device.NewDevice1(path)
+	if err != nil {
+		return false, err
+	}
+	defer dev.Close()
+	return dev.GetPaired()
+}
+
+// WithServiceAuthorizer wrap an agent, deciding AuthorizeService with the
+// authorizer. Other requests are handled by the agent
+func WithServiceAuthorizer(ag Agent1Client, authorizer *ServiceAuthorizer) Agent1Client {
+	return &serviceAuthorizerAgent{
+		Agent1Client: ag,
+		authorizer:   authorizer,
+	}
+}
+
+type serviceAuthorizerAgent struct {
+	Agent1Client
+	authorizer *ServiceAuthorizer
+}
+
+func (self *serviceAuthorizerAgent) AuthorizeService(device dbus.ObjectPath, uuid string) *dbus.Error {
+	err := self.authorizer.Authorize(device, uuid)
+	if err != nil {
+		return policyError("AuthorizeService", device, err)
+	}
+	return nil
+}
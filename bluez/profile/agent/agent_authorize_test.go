@@ -0,0 +1,178 @@
+package agent
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRules = `
+default: allow
+rules:
+  # HID only from bonded devices
+  - uuid: "1124"
+    bonded: true
+    action: allow
+  - uuid: "1124"
+    action: reject
+  # OBEX object push only from known devices
+  - uuid: "1105"
+    address: "00:11:22:*"
+    action: allow
+  - uuid: "1105"
+    action: reject
+`
+
+func testAuthorizer(t *testing.T, rules string) (*ServiceAuthorizer, *[]AuditEntry) {
+	r, err := ParseServiceRules([]byte(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewServiceAuthorizer(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.SetBondedFunc(func(path dbus.ObjectPath) (bool, error) {
+		return path == testDevice, nil
+	})
+	entries := []AuditEntry{}
+	a.OnAudit(func(entry AuditEntry) {
+		entries = append(entries, entry)
+	})
+	return a, &entries
+}
+
+func TestServiceAuthorizer(t *testing.T) {
+
+	a, entries := testAuthorizer(t, testRules)
+
+	assert.NoError(t, a.Authorize(testDevice, "00001124-0000-1000-8000-00805f9b34fb"))
+	assert.Equal(t, bluez.ErrRejected, a.Authorize(testOtherDevice, "1124"))
+
+	assert.NoError(t, a.Authorize(testOtherDevice, "1105"))
+	assert.Error(t, a.Authorize("/org/bluez/hci0/dev_AA_11_22_33_44_55", "1105"))
+
+	assert.NoError(t, a.Authorize(testOtherDevice, "180d"))
+
+	assert.Equal(t, 5, len(*entries))
+
+	e := (*entries)[1]
+	assert.Equal(t, "00:11:22:33:44:66", e.Address)
+	assert.Equal(t, "00001124-0000-1000-8000-00805f9b34fb", e.UUID)
+	assert.False(t, e.Allow)
+	assert.False(t, e.Bonded)
+	assert.Equal(t, 1, e.Rule)
+
+	e = (*entries)[4]
+	assert.True(t, e.Allow)
+	assert.Equal(t, -1, e.Rule)
+}
+
+func TestServiceAuthorizerBondedError(t *testing.T) {
+
+	a, entries := testAuthorizer(t, testRules)
+	a.SetBondedFunc(func(path dbus.ObjectPath) (bool, error) {
+		return false, errors.New("device not found")
+	})
+
+	// the bonded rule can not be evaluated, the request is rejected
+	// although the default allow
+	assert.Equal(t, bluez.ErrRejected, a.Authorize(testDevice, "1124"))
+	e := (*entries)[0]
+	assert.False(t, e.Allow)
+	assert.Equal(t, 0, e.Rule)
+	assert.EqualError(t, e.Err, "device not found")
+	assert.Contains(t, e.String(), "bonded unknown")
+
+	// rules not depending on the bonding state are still applied
+	assert.NoError(t, a.Authorize(testDevice, "180d"))
+	assert.NoError(t, (*entries)[1].Err)
+}
+
+func TestServiceRulesValidate(t *testing.T) {
+
+	_, err := ParseServiceRules([]byte("rules:\n  - uuid: \"1124\"\n"))
+	assert.Error(t, err)
+
+	_, err = ParseServiceRules([]byte("rules:\n  - uuid: zz\n    action: allow\n"))
+	assert.Error(t, err)
+
+	_, err = ParseServiceRules([]byte("default: maybe\n"))
+	assert.Error(t, err)
+
+	_, err = ParseServiceRules([]byte("unknown: true\n"))
+	assert.Error(t, err)
+
+	// reject by default
+	a, _ := testAuthorizer(t, "rules: []\n")
+	assert.Equal(t, bluez.ErrRejected, a.Authorize(testDevice, "1124"))
+}
+
+func TestServiceAuthorizerReload(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "authorizer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "rules.yml")
+	err = ioutil.WriteFile(filename, []byte("default: allow\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := LoadServiceAuthorizer(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.SetBondedFunc(func(path dbus.ObjectPath) (bool, error) {
+		return false, nil
+	})
+	assert.NoError(t, a.Authorize(testDevice, "1124"))
+
+	cancel := a.Watch(5 * time.Millisecond)
+	defer cancel()
+
+	err = ioutil.WriteFile(filename, []byte("default: reject\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ensure the modification time change
+	later := time.Now().Add(time.Second)
+	os.Chtimes(filename, later, later)
+
+	for i := 0; i < 100 && a.Rules().Default != ServiceActionReject; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, bluez.ErrRejected, a.Authorize(testDevice, "1124"))
+
+	// invalid rules are not loaded
+	err = ioutil.WriteFile(filename, []byte("default: invalid\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, a.Reload())
+	assert.Equal(t, ServiceActionReject, a.Rules().Default)
+}
+
+func TestWithServiceAuthorizer(t *testing.T) {
+
+	a, _ := testAuthorizer(t, testRules)
+	ag := WithServiceAuthorizer(NewDefaultSimpleAgent(), a)
+
+	assert.Nil(t, ag.AuthorizeService(testDevice, "1124"))
+
+	err := ag.AuthorizeService(testOtherDevice, "1124")
+	assert.NotNil(t, err)
+	assert.Equal(t, bluez.ErrRejected.Error(), err.Name)
+
+	assert.Nil(t, ag.Cancel())
+}
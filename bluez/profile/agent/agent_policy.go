@@ -0,0 +1,163 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/adapter"
+	"github.com/godbus/dbus/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const policyAgentBasePath = "/org/bluez/agent/policy%d"
+
+// Policy decide the pairing and authorization requests of a PolicyAgent.
+// Returning bluez.ErrCanceled cancel the request, any other error reject it
+type Policy interface {
+	RequestPinCode(device dbus.ObjectPath) (string, error)
+	DisplayPinCode(device dbus.ObjectPath, pincode string) error
+	RequestPasskey(device dbus.ObjectPath) (uint32, error)
+	DisplayPasskey(device dbus.ObjectPath, passkey uint32, entered uint16) error
+	RequestConfirmation(device dbus.ObjectPath, passkey uint32) error
+	RequestAuthorization(device dbus.ObjectPath) error
+	AuthorizeService(device dbus.ObjectPath, uuid string) error
+	// Cancel the pending request, Bluez does not wait for a reply anymore
+	Cancel()
+	// Release is called when the agent is unregistered by Bluez
+	Release()
+}
+
+// NewPolicyAgent return an agent delegating every request to a policy
+func NewPolicyAgent(policy Policy) *PolicyAgent {
+	p := dbus.ObjectPath(fmt.Sprintf(policyAgentBasePath, agentInstances))
+	agentInstances += 1
+	return &PolicyAgent{
+		path:   p,
+		policy: policy,
+	}
+}
+
+// PolicyAgent implement interface Agent1Client delegating to a Policy
+type PolicyAgent struct {
+	path   dbus.ObjectPath
+	policy Policy
+	trust  bool
+}
+
+// SetTrust set devices as trusted once a pin code, passkey or confirmation
+// request is accepted. Disabled by default
+func (self *PolicyAgent) SetTrust(trust bool) *PolicyAgent {
+	self.trust = trust
+	return self
+}
+
+// Policy return the policy of the agent
+func (self *PolicyAgent) Policy() Policy {
+	return self.policy
+}
+
+func (self *PolicyAgent) Path() dbus.ObjectPath {
+	return self.path
+}
+
+func (self *PolicyAgent) Interface() string {
+	return Agent1Interface
+}
+
+func (self *PolicyAgent) Release() *dbus.Error {
+	log.Debugf("PolicyAgent: Release")
+	self.policy.Release()
+	return nil
+}
+
+func (self *PolicyAgent) RequestPinCode(device dbus.ObjectPath) (string, *dbus.Error) {
+	log.Debugf("PolicyAgent: RequestPinCode %s", device)
+	pincode, err := self.policy.RequestPinCode(device)
+	if err != nil {
+		return "", policyError("RequestPinCode", device, err)
+	}
+	return pincode, self.setTrusted(device)
+}
+
+func (self *PolicyAgent) DisplayPinCode(device dbus.ObjectPath, pincode string) *dbus.Error {
+	log.Debugf("PolicyAgent: DisplayPinCode %s", device)
+	return policyError("DisplayPinCode", device, self.policy.DisplayPinCode(device, pincode))
+}
+
+func (self *PolicyAgent) RequestPasskey(device dbus.ObjectPath) (uint32, *dbus.Error) {
+	log.Debugf("PolicyAgent: RequestPasskey %s", device)
+	passkey, err := self.policy.RequestPasskey(device)
+	if err != nil {
+		return 0, policyError("RequestPasskey", device, err)
+	}
+	return passkey, self.setTrusted(device)
+}
+
+func (self *PolicyAgent) DisplayPasskey(device dbus.ObjectPath, passkey uint32, entered uint16) *dbus.Error {
+	log.Debugf("PolicyAgent: DisplayPasskey %s entered %d", device, entered)
+	return policyError("DisplayPasskey", device, self.policy.DisplayPasskey(device, passkey, entered))
+}
+
+func (self *PolicyAgent) RequestConfirmation(device dbus.ObjectPath, passkey uint32) *dbus.Error {
+	log.Debugf("PolicyAgent: RequestConfirmation %s", device)
+	err := self.policy.RequestConfirmation(device, passkey)
+	if err != nil {
+		return policyError("RequestConfirmation", device, err)
+	}
+	return self.setTrusted(device)
+}
+
+func (self *PolicyAgent) RequestAuthorization(device dbus.ObjectPath) *dbus.Error {
+	log.Debugf("PolicyAgent: RequestAuthorization %s", device)
+	return policyError("RequestAuthorization", device, self.policy.RequestAuthorization(device))
+}
+
+func (self *PolicyAgent) AuthorizeService(device dbus.ObjectPath, uuid string) *dbus.Error {
+	log.Debugf("PolicyAgent: AuthorizeService %s %s", device, uuid)
+	return policyError("AuthorizeService", device, self.policy.AuthorizeService(device, uuid))
+}
+
+func (self *PolicyAgent) Cancel() *dbus.Error {
+	log.Debugf("PolicyAgent: Cancel")
+	self.policy.Cancel()
+	return nil
+}
+
+func (self *PolicyAgent) setTrusted(device dbus.ObjectPath) *dbus.Error {
+
+	if !self.trust {
+		return nil
+	}
+
+	adapterID, err := adapter.ParseAdapterID(device)
+	if err != nil {
+		log.Warnf("PolicyAgent: Failed to load adapter %s", err)
+		return dbus.MakeFailedError(err)
+	}
+
+	err = SetTrusted(adapterID, device)
+	if err != nil {
+		log.Warnf("PolicyAgent: Failed to set trust for %s: %s", device, err)
+		return dbus.MakeFailedError(err)
+	}
+
+	return nil
+}
+
+// policyError convert a policy error to org.bluez.Error.Canceled or
+// org.bluez.Error.Rejected
+func policyError(method string, device dbus.ObjectPath, err error) *dbus.Error {
+
+	if err == nil {
+		return nil
+	}
+
+	name := bluez.ErrRejected.Error()
+	if errors.Is(err, bluez.ErrCanceled) {
+		name = bluez.ErrCanceled.Error()
+	}
+
+	log.Debugf("PolicyAgent: %s %s: %s", method, device, err)
+	return dbus.NewError(name, []interface{}{err.Error()})
+}
@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+	"github.com/godbus/dbus/v5"
+)
+
+// NewAllowListPolicy return a policy accepting only the listed device addresses
+func NewAllowListPolicy(addresses ...string) *AllowListPolicy {
+	p := &AllowListPolicy{
+		addresses: map[string]bool{},
+	}
+	p.Allow(addresses...)
+	return p
+}
+
+// AllowListPolicy accept confirmation, authorization and service requests
+// from the allowed addresses. Pin code and passkey requests are accepted only
+// when a pin code or passkey is set
+type AllowListPolicy struct {
+	lock       sync.RWMutex
+	addresses  map[string]bool
+	pinCode    string
+	passkey    uint32
+	hasPasskey bool
+}
+
+// Allow add addresses to the allow list
+func (p *AllowListPolicy) Allow(addresses ...string) *AllowListPolicy {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, address := range addresses {
+		p.addresses[strings.ToUpper(address)] = true
+	}
+	return p
+}
+
+// Remove addresses from the allow list
+func (p *AllowListPolicy) Remove(addresses ...string) *AllowListPolicy {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, address := range addresses {
+		delete(p.addresses, strings.ToUpper(address))
+	}
+	return p
+}
+
+// Allowed return true if the address is in the allow list
+func (p *AllowListPolicy) Allowed(address string) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.addresses[strings.ToUpper(address)]
+}
+
+// SetPinCode set the pin code returned to the allowed devices
+func (p *AllowListPolicy) SetPinCode(pinCode string) *AllowListPolicy {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.pinCode = pinCode
+	return p
+}
+
+// SetPasskey set the passkey returned to the allowed devices
+func (p *AllowListPolicy) SetPasskey(passkey uint32) *AllowListPolicy {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.passkey = passkey
+	p.hasPasskey = true
+	return p
+}
+
+func (p *AllowListPolicy) check(path dbus.ObjectPath) error {
+	address, err := device.ParseDeviceAddress(path)
+	if err != nil {
+		return err
+	}
+	if !p.Allowed(address) {
+		return fmt.Errorf("%s not allowed", address)
+	}
+	return nil
+}
+
+func (p *AllowListPolicy) RequestPinCode(path dbus.ObjectPath) (string, error) {
+	err := p.check(path)
+	if err != nil {
+		return "", err
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if p.pinCode == "" {
+		return "", bluez.ErrRejected
+	}
+	return p.pinCode, nil
+}
+
+func (p *AllowListPolicy) DisplayPinCode(path dbus.ObjectPath, pincode string) error {
+	return p.check(path)
+}
+
+func (p *AllowListPolicy) RequestPasskey(path dbus.ObjectPath) (uint32, error) {
+	err := p.check(path)
+	if err != nil {
+		return 0, err
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if !p.hasPasskey {
+		return 0, bluez.ErrRejected
+	}
+	return p.passkey, nil
+}
+
+func (p *AllowListPolicy) DisplayPasskey(path dbus.ObjectPath, passkey uint32, entered uint16) error {
+	return p.check(path)
+}
+
+func (p *AllowListPolicy) RequestConfirmation(path dbus.ObjectPath, passkey uint32) error {
+	return p.check(path)
+}
+
+func (p *AllowListPolicy) RequestAuthorization(path dbus.ObjectPath) error {
+	return p.check(path)
+}
+
+func (p *AllowListPolicy) AuthorizeService(path dbus.ObjectPath, uuid string) error {
+	return p.check(path)
+}
+
+func (p *AllowListPolicy) Cancel() {}
+
+func (p *AllowListPolicy) Release() {}
@@ -0,0 +1,208 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+	"github.com/godbus/dbus/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultRequestTimeout is the time a ChanPolicy wait for a request to be answered
+const DefaultRequestTimeout = 30 * time.Second
+
+// RequestType is the agent method of a Request
+type RequestType string
+
+const (
+	RequestTypePinCode        RequestType = "RequestPinCode"
+	RequestTypeDisplayPinCode RequestType = "DisplayPinCode"
+	RequestTypePasskey        RequestType = "RequestPasskey"
+	RequestTypeDisplayPasskey RequestType = "DisplayPasskey"
+	RequestTypeConfirmation   RequestType = "RequestConfirmation"
+	RequestTypeAuthorization  RequestType = "RequestAuthorization"
+	RequestTypeService        RequestType = "AuthorizeService"
+)
+
+// Request is an agent request to be answered by the application. Display
+// requests are informative, they do not need an answer and are dropped if
+// the application is not receiving
+type Request struct {
+	Type    RequestType
+	Device  dbus.ObjectPath
+	Address string
+	PinCode string
+	Passkey uint32
+	Entered uint16
+	UUID    string
+
+	reply chan requestReply
+	done  chan struct{}
+}
+
+type requestReply struct {
+	pinCode string
+	passkey uint32
+	err     error
+}
+
+func (r *Request) answer(reply requestReply) {
+	select {
+	case r.reply <- reply:
+	default:
+		// already answered
+	}
+}
+
+// Accept a confirmation or authorization request
+func (r *Request) Accept() {
+	r.answer(requestReply{})
+}
+
+// AcceptPinCode answer a pin code request
+func (r *Request) AcceptPinCode(pinCode string) {
+	r.answer(requestReply{pinCode: pinCode})
+}
+
+// AcceptPasskey answer a passkey request
+func (r *Request) AcceptPasskey(passkey uint32) {
+	r.answer(requestReply{passkey: passkey})
+}
+
+// Reject the request
+func (r *Request) Reject() {
+	r.answer(requestReply{err: bluez.ErrRejected})
+}
+
+// Done is closed when the request is answered, canceled or timed out
+func (r *Request) Done() <-chan struct{} {
+	return r.done
+}
+
+// NewChanPolicy return a policy sending the requests on a channel, a zero
+// timeout use DefaultRequestTimeout
+func NewChanPolicy(timeout time.Duration) *ChanPolicy {
+	if timeout == 0 {
+		timeout = DefaultRequestTimeout
+	}
+	return &ChanPolicy{
+		requests: make(chan *Request),
+		timeout:  timeout,
+		canceled: make(chan struct{}),
+	}
+}
+
+// ChanPolicy send each request to the application, which answer it
+// asynchronously. Requests not received or not answered within the timeout,
+// or canceled by Bluez, are canceled
+type ChanPolicy struct {
+	requests chan *Request
+	timeout  time.Duration
+	lock     sync.Mutex
+	canceled chan struct{}
+}
+
+// Requests return the channel of the requests to answer
+func (p *ChanPolicy) Requests() <-chan *Request {
+	return p.requests
+}
+
+// send deliver a request and wait for the answer
+func (p *ChanPolicy) send(req *Request) (requestReply, error) {
+
+	p.lock.Lock()
+	canceled := p.canceled
+	p.lock.Unlock()
+
+	req.Address, _ = device.ParseDeviceAddress(req.Device)
+	req.reply = make(chan requestReply, 1)
+	req.done = make(chan struct{})
+	defer close(req.done)
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+
+	select {
+	case p.requests <- req:
+	case <-timer.C:
+		log.Debugf("ChanPolicy: %s %s not received in %s", req.Type, req.Device, p.timeout)
+		return requestReply{}, bluez.ErrCanceled
+	case <-canceled:
+		return requestReply{}, bluez.ErrCanceled
+	}
+
+	select {
+	case reply := <-req.reply:
+		return reply, reply.err
+	case <-timer.C:
+		log.Debugf("ChanPolicy: %s %s not answered in %s", req.Type, req.Device, p.timeout)
+		return requestReply{}, bluez.ErrCanceled
+	case <-canceled:
+		return requestReply{}, bluez.ErrCanceled
+	}
+}
+
+// notify deliver a display request without blocking the agent
+func (p *ChanPolicy) notify(req *Request) {
+
+	req.Address, _ = device.ParseDeviceAddress(req.Device)
+	req.reply = make(chan requestReply, 1)
+	req.done = make(chan struct{})
+	close(req.done)
+
+	select {
+	case p.requests <- req:
+	default:
+		log.Debugf("ChanPolicy: %s %s dropped, no receiver", req.Type, req.Device)
+	}
+}
+
+func (p *ChanPolicy) RequestPinCode(path dbus.ObjectPath) (string, error) {
+	reply, err := p.send(&Request{Type: RequestTypePinCode, Device: path})
+	return reply.pinCode, err
+}
+
+func (p *ChanPolicy) DisplayPinCode(path dbus.ObjectPath, pincode string) error {
+	p.notify(&Request{Type: RequestTypeDisplayPinCode, Device: path, PinCode: pincode})
+	return nil
+}
+
+func (p *ChanPolicy) RequestPasskey(path dbus.ObjectPath) (uint32, error) {
+	reply, err := p.send(&Request{Type: RequestTypePasskey, Device: path})
+	return reply.passkey, err
+}
+
+func (p *ChanPolicy) DisplayPasskey(path dbus.ObjectPath, passkey uint32, entered uint16) error {
+	p.notify(&Request{Type: RequestTypeDisplayPasskey, Device: path, Passkey: passkey, Entered: entered})
+	return nil
+}
+
+func (p *ChanPolicy) RequestConfirmation(path dbus.ObjectPath, passkey uint32) error {
+	_, err := p.send(&Request{Type: RequestTypeConfirmation, Device: path, Passkey: passkey})
+	return err
+}
+
+func (p *ChanPolicy) RequestAuthorization(path dbus.ObjectPath) error {
+	_, err := p.send(&Request{Type: RequestTypeAuthorization, Device: path})
+	return err
+}
+
+func (p *ChanPolicy) AuthorizeService(path dbus.ObjectPath, uuid string) error {
+	_, err := p.send(&Request{Type: RequestTypeService, Device: path, UUID: uuid})
+	return err
+}
+
+// Cancel the pending requests
+func (p *ChanPolicy) Cancel() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	close(p.canceled)
+	p.canceled = make(chan struct{})
+}
+
+// Release cancel the pending requests
+func (p *ChanPolicy) Release() {
+	p.Cancel()
+}
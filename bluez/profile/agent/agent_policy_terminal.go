@@ -0,0 +1,196 @@
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
+	"github.com/godbus/dbus/v5"
+)
+
+// MaxPasskey is the max value of a six digits passkey
+const MaxPasskey = 999999
+
+// NewTerminalPolicy return a policy prompting the user on a terminal, nil
+// in and out default to stdin and stdout
+func NewTerminalPolicy(in io.Reader, out io.Writer) *TerminalPolicy {
+	if in == nil {
+		in = os.Stdin
+	}
+	if out == nil {
+		out = os.Stdout
+	}
+	return &TerminalPolicy{
+		in:       bufio.NewReader(in),
+		out:      out,
+		lines:    make(chan terminalLine),
+		canceled: make(chan struct{}, 1),
+	}
+}
+
+// TerminalPolicy ask the user to enter pin codes and passkeys and to confirm
+// requests, answering yes or no. Bluez can cancel a pending prompt
+type TerminalPolicy struct {
+	in  *bufio.Reader
+	out io.Writer
+	// outLock serialize the writes, it is not held while reading
+	outLock sync.Mutex
+	// promptLock serialize the prompts
+	promptLock sync.Mutex
+	readOnce   sync.Once
+	lines      chan terminalLine
+	// canceled hold a pending cancel of the current prompt
+	canceled chan struct{}
+	// canceledAt is the time the last prompt was canceled
+	canceledAt time.Time
+}
+
+type terminalLine struct {
+	line string
+	err  error
+	at   time.Time
+}
+
+// read the input lines, the channel is closed on error
+func (p *TerminalPolicy) read() {
+	defer close(p.lines)
+	for {
+		line, err := p.in.ReadString('\n')
+		p.lines <- terminalLine{line, err, time.Now()}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// prompt print a message and read a line, the prompt is aborted by Cancel
+func (p *TerminalPolicy) prompt(format string, args ...interface{}) (string, error) {
+
+	p.promptLock.Lock()
+	defer p.promptLock.Unlock()
+
+	p.readOnce.Do(func() {
+		go p.read()
+	})
+
+	// drop a cancel received while no prompt was pending
+	select {
+	case <-p.canceled:
+	default:
+	}
+
+	start := time.Now()
+	p.print(format, args...)
+
+	for {
+		select {
+		case l, ok := <-p.lines:
+			if !ok || (l.err != nil && (l.err != io.EOF || l.line == "")) {
+				return "", bluez.ErrCanceled
+			}
+			// drop the answer to a canceled prompt
+			if l.at.After(p.canceledAt) && l.at.Before(start) && !p.canceledAt.IsZero() {
+				continue
+			}
+			return strings.TrimSpace(l.line), nil
+		case <-p.canceled:
+			p.canceledAt = time.Now()
+			p.print("Request canceled\n")
+			return "", bluez.ErrCanceled
+		}
+	}
+}
+
+// confirm ask a yes or no question
+func (p *TerminalPolicy) confirm(format string, args ...interface{}) error {
+	answer, err := p.prompt(format+" (yes/no): ", args...)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return nil
+	}
+	return bluez.ErrRejected
+}
+
+func (p *TerminalPolicy) print(format string, args ...interface{}) {
+	p.outLock.Lock()
+	defer p.outLock.Unlock()
+	fmt.Fprintf(p.out, format, args...)
+}
+
+func terminalName(path dbus.ObjectPath) string {
+	address, err := device.ParseDeviceAddress(path)
+	if err != nil {
+		return string(path)
+	}
+	return address
+}
+
+func (p *TerminalPolicy) RequestPinCode(path dbus.ObjectPath) (string, error) {
+	pincode, err := p.prompt("Enter PIN code for %s: ", terminalName(path))
+	if err != nil {
+		return "", err
+	}
+	if pincode == "" {
+		return "", bluez.ErrRejected
+	}
+	return pincode, nil
+}
+
+func (p *TerminalPolicy) DisplayPinCode(path dbus.ObjectPath, pincode string) error {
+	p.print("PIN code for %s: %s\n", terminalName(path), pincode)
+	return nil
+}
+
+func (p *TerminalPolicy) RequestPasskey(path dbus.ObjectPath) (uint32, error) {
+	answer, err := p.prompt("Enter passkey for %s: ", terminalName(path))
+	if err != nil {
+		return 0, err
+	}
+	passkey, err := strconv.ParseUint(answer, 10, 32)
+	if err != nil || passkey > MaxPasskey {
+		return 0, fmt.Errorf("Invalid passkey %s", answer)
+	}
+	return uint32(passkey), nil
+}
+
+func (p *TerminalPolicy) DisplayPasskey(path dbus.ObjectPath, passkey uint32, entered uint16) error {
+	p.print("Passkey for %s: %06d (%d entered)\n", terminalName(path), passkey, entered)
+	return nil
+}
+
+func (p *TerminalPolicy) RequestConfirmation(path dbus.ObjectPath, passkey uint32) error {
+	return p.confirm("Confirm passkey %06d for %s?", passkey, terminalName(path))
+}
+
+func (p *TerminalPolicy) RequestAuthorization(path dbus.ObjectPath) error {
+	return p.confirm("Authorize pairing with %s?", terminalName(path))
+}
+
+func (p *TerminalPolicy) AuthorizeService(path dbus.ObjectPath, uuid string) error {
+	name := bluez.ExpandUUID(uuid)
+	if u, err := bluez.ParseUUID(uuid); err == nil && u.Name() != "" {
+		name = fmt.Sprintf("%s (%s)", u.Name(), name)
+	}
+	return p.confirm("Authorize service %s for %s?", name, terminalName(path))
+}
+
+// Cancel abort the pending prompt
+func (p *TerminalPolicy) Cancel() {
+	select {
+	case p.canceled <- struct{}{}:
+	default:
+		// already canceled
+	}
+}
+
+func (p *TerminalPolicy) Release() {}
@@ -0,0 +1,191 @@
+package agent
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDevice = dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55")
+const testOtherDevice = dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_66")
+
+var _ Agent1Client = &PolicyAgent{}
+
+func TestPolicyAgentErrors(t *testing.T) {
+
+	ag := NewPolicyAgent(NewAllowListPolicy("00:11:22:33:44:55"))
+	assert.Equal(t, Agent1Interface, ag.Interface())
+
+	assert.Nil(t, ag.RequestConfirmation(testDevice, 123456))
+
+	err := ag.RequestConfirmation(testOtherDevice, 123456)
+	assert.NotNil(t, err)
+	assert.Equal(t, bluez.ErrRejected.Error(), err.Name)
+
+	policy := NewChanPolicy(10 * time.Millisecond)
+	ag = NewPolicyAgent(policy)
+	err = ag.AuthorizeService(testDevice, "1124")
+	assert.NotNil(t, err)
+	assert.Equal(t, bluez.ErrCanceled.Error(), err.Name)
+}
+
+func TestAllowListPolicy(t *testing.T) {
+
+	p := NewAllowListPolicy("00:11:22:33:44:55")
+
+	assert.NoError(t, p.AuthorizeService(testDevice, "1124"))
+	assert.Error(t, p.AuthorizeService(testOtherDevice, "1124"))
+
+	_, err := p.RequestPinCode(testDevice)
+	assert.Error(t, err)
+	_, err = p.RequestPasskey(testDevice)
+	assert.Error(t, err)
+
+	p.SetPinCode("1234").SetPasskey(0)
+	pin, err := p.RequestPinCode(testDevice)
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", pin)
+	passkey, err := p.RequestPasskey(testDevice)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), passkey)
+
+	_, err = p.RequestPinCode(testOtherDevice)
+	assert.Error(t, err)
+
+	p.Allow("00:11:22:33:44:66").Remove("00:11:22:33:44:55")
+	assert.NoError(t, p.RequestAuthorization(testOtherDevice))
+	assert.Error(t, p.RequestAuthorization(testDevice))
+}
+
+func TestTerminalPolicy(t *testing.T) {
+
+	in := strings.NewReader("yes\nno\n1234\n654321\nabc\n")
+	out := new(bytes.Buffer)
+	p := NewTerminalPolicy(in, out)
+
+	assert.NoError(t, p.RequestConfirmation(testDevice, 123))
+	assert.Contains(t, out.String(), "Confirm passkey 000123 for 00:11:22:33:44:55?")
+
+	err := p.AuthorizeService(testDevice, "180d")
+	assert.Equal(t, bluez.ErrRejected, err)
+	assert.Contains(t, out.String(), "Heart Rate")
+
+	pin, err := p.RequestPinCode(testDevice)
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", pin)
+
+	passkey, err := p.RequestPasskey(testDevice)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(654321), passkey)
+
+	_, err = p.RequestPasskey(testDevice)
+	assert.Error(t, err)
+
+	// input closed
+	err = p.RequestAuthorization(testDevice)
+	assert.Equal(t, bluez.ErrCanceled, err)
+
+	assert.NoError(t, p.DisplayPasskey(testDevice, 42, 2))
+	assert.Contains(t, out.String(), "Passkey for 00:11:22:33:44:55: 000042 (2 entered)")
+}
+
+func TestTerminalPolicyCancel(t *testing.T) {
+
+	in, w := io.Pipe()
+	defer w.Close()
+	out := new(bytes.Buffer)
+	p := NewTerminalPolicy(in, out)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p.Cancel()
+	}()
+	assert.Equal(t, bluez.ErrCanceled, p.RequestAuthorization(testDevice))
+
+	// the line typed for the canceled prompt is not an answer
+	_, err := w.Write([]byte("yes\n"))
+	assert.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte("no\n"))
+	}()
+	assert.Equal(t, bluez.ErrRejected, p.RequestAuthorization(testDevice))
+}
+
+func TestChanPolicy(t *testing.T) {
+
+	p := NewChanPolicy(time.Second)
+
+	go func() {
+		for req := range p.Requests() {
+			switch req.Type {
+			case RequestTypePasskey:
+				req.AcceptPasskey(123456)
+			case RequestTypePinCode:
+				req.AcceptPinCode("0000")
+			case RequestTypeService:
+				if req.UUID == "1124" && req.Address == "00:11:22:33:44:55" {
+					req.Accept()
+				} else {
+					req.Reject()
+				}
+			case RequestTypeConfirmation:
+				// never answered, canceled below
+			}
+		}
+	}()
+
+	passkey, err := p.RequestPasskey(testDevice)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(123456), passkey)
+
+	pin, err := p.RequestPinCode(testDevice)
+	assert.NoError(t, err)
+	assert.Equal(t, "0000", pin)
+
+	assert.NoError(t, p.AuthorizeService(testDevice, "1124"))
+	assert.Equal(t, bluez.ErrRejected, p.AuthorizeService(testOtherDevice, "1124"))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p.Cancel()
+	}()
+	assert.Equal(t, bluez.ErrCanceled, p.RequestConfirmation(testDevice, 1))
+}
+
+func TestChanPolicyDisplay(t *testing.T) {
+
+	p := NewChanPolicy(time.Second)
+
+	// no receiver, the display request is dropped
+	start := time.Now()
+	assert.NoError(t, p.DisplayPasskey(testDevice, 123456, 0))
+	assert.NoError(t, p.DisplayPinCode(testDevice, "0000"))
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+}
+
+func TestChanPolicyTimeout(t *testing.T) {
+
+	p := NewChanPolicy(10 * time.Millisecond)
+
+	received := make(chan *Request, 1)
+	go func() {
+		received <- <-p.Requests()
+	}()
+
+	err := p.RequestAuthorization(testDevice)
+	assert.Equal(t, bluez.ErrCanceled, err)
+
+	req := <-received
+	<-req.Done()
+	// answering late is ignored
+	req.Accept()
+}
@@ -3,6 +3,7 @@ package agent
 
 
 import (
+	"context"
 	"github.com/godbus/dbus/v5"
 	"github.com/dimonzozo/go-bluetooth/bluez"
 	"sync"
@@ -17,6 +18,12 @@ var Agent1Interface = "org.bluez.Agent1"
 // - servicePath: unique name
 // - objectPath: freely definable
 func NewAgent1(servicePath string, objectPath dbus.ObjectPath) (*Agent1, error) {
+	return NewAgent1WithConn(nil, servicePath, objectPath)
+}
+
+// NewAgent1WithConn create a new instance of Agent1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewAgent1WithConn(conn *bluez.Conn, servicePath string, objectPath dbus.ObjectPath) (*Agent1, error) {
 	a := new(Agent1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -24,6 +31,7 @@ func NewAgent1(servicePath string, objectPath dbus.ObjectPath) (*Agent1, error)
 			Iface: Agent1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -87,7 +95,7 @@ func (a *Agent1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error) {
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -127,8 +135,13 @@ Release
 
 */
 func (a *Agent1) Release() error {
+	return a.ReleaseContext(context.Background())
+}
+
+// ReleaseContext call Release, the call is aborted when ctx is done
+func (a *Agent1) ReleaseContext(ctx context.Context) error {
 	
-	return a.client.Call("Release", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Release", 0, ).Store())
 	
 }
 
@@ -146,10 +159,15 @@ RequestPinCode
 
 */
 func (a *Agent1) RequestPinCode(device dbus.ObjectPath) (string, error) {
+	return a.RequestPinCodeContext(context.Background(), device)
+}
+
+// RequestPinCodeContext call RequestPinCode, the call is aborted when ctx is done
+func (a *Agent1) RequestPinCodeContext(ctx context.Context, device dbus.ObjectPath) (string, error) {
 	
 	var val0 string
-	err := a.client.Call("RequestPinCode", 0, device).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "RequestPinCode", 0, device).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -179,8 +197,13 @@ DisplayPinCode
 
 */
 func (a *Agent1) DisplayPinCode(device dbus.ObjectPath, pincode string) error {
+	return a.DisplayPinCodeContext(context.Background(), device, pincode)
+}
+
+// DisplayPinCodeContext call DisplayPinCode, the call is aborted when ctx is done
+func (a *Agent1) DisplayPinCodeContext(ctx context.Context, device dbus.ObjectPath, pincode string) error {
 	
-	return a.client.Call("DisplayPinCode", 0, device, pincode).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "DisplayPinCode", 0, device, pincode).Store())
 	
 }
 
@@ -198,10 +221,15 @@ RequestPasskey
 
 */
 func (a *Agent1) RequestPasskey(device dbus.ObjectPath) (uint32, error) {
+	return a.RequestPasskeyContext(context.Background(), device)
+}
+
+// RequestPasskeyContext call RequestPasskey, the call is aborted when ctx is done
+func (a *Agent1) RequestPasskeyContext(ctx context.Context, device dbus.ObjectPath) (uint32, error) {
 	
 	var val0 uint32
-	err := a.client.Call("RequestPasskey", 0, device).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "RequestPasskey", 0, device).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -226,8 +254,13 @@ DisplayPasskey
 
 */
 func (a *Agent1) DisplayPasskey(device dbus.ObjectPath, passkey uint32, entered uint16) error {
+	return a.DisplayPasskeyContext(context.Background(), device, passkey, entered)
+}
+
+// DisplayPasskeyContext call DisplayPasskey, the call is aborted when ctx is done
+func (a *Agent1) DisplayPasskeyContext(ctx context.Context, device dbus.ObjectPath, passkey uint32, entered uint16) error {
 	
-	return a.client.Call("DisplayPasskey", 0, device, passkey, entered).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "DisplayPasskey", 0, device, passkey, entered).Store())
 	
 }
 
@@ -249,8 +282,13 @@ RequestConfirmation
 
 */
 func (a *Agent1) RequestConfirmation(device dbus.ObjectPath, passkey uint32) error {
+	return a.RequestConfirmationContext(context.Background(), device, passkey)
+}
+
+// RequestConfirmationContext call RequestConfirmation, the call is aborted when ctx is done
+func (a *Agent1) RequestConfirmationContext(ctx context.Context, device dbus.ObjectPath, passkey uint32) error {
 	
-	return a.client.Call("RequestConfirmation", 0, device, passkey).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RequestConfirmation", 0, device, passkey).Store())
 	
 }
 
@@ -270,8 +308,13 @@ RequestAuthorization
 
 */
 func (a *Agent1) RequestAuthorization(device dbus.ObjectPath) error {
+	return a.RequestAuthorizationContext(context.Background(), device)
+}
+
+// RequestAuthorizationContext call RequestAuthorization, the call is aborted when ctx is done
+func (a *Agent1) RequestAuthorizationContext(ctx context.Context, device dbus.ObjectPath) error {
 	
-	return a.client.Call("RequestAuthorization", 0, device).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RequestAuthorization", 0, device).Store())
 	
 }
 
@@ -286,8 +329,13 @@ AuthorizeService
 
 */
 func (a *Agent1) AuthorizeService(device dbus.ObjectPath, uuid string) error {
+	return a.AuthorizeServiceContext(context.Background(), device, uuid)
+}
+
+// AuthorizeServiceContext call AuthorizeService, the call is aborted when ctx is done
+func (a *Agent1) AuthorizeServiceContext(ctx context.Context, device dbus.ObjectPath, uuid string) error {
 	
-	return a.client.Call("AuthorizeService", 0, device, uuid).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "AuthorizeService", 0, device, uuid).Store())
 	
 }
 
@@ -298,8 +346,13 @@ Cancel
 
 */
 func (a *Agent1) Cancel() error {
+	return a.CancelContext(context.Background())
+}
+
+// CancelContext call Cancel, the call is aborted when ctx is done
+func (a *Agent1) CancelContext(ctx context.Context) error {
 	
-	return a.client.Call("Cancel", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Cancel", 0, ).Store())
 	
 }
 
@@ -3,6 +3,7 @@ package agent
 
 
 import (
+	"context"
 	"github.com/godbus/dbus/v5"
 	"github.com/dimonzozo/go-bluetooth/bluez"
 	"sync"
@@ -16,6 +17,12 @@ var AgentManager1Interface = "org.bluez.AgentManager1"
 // Args:
 
 func NewAgentManager1() (*AgentManager1, error) {
+	return NewAgentManager1WithConn(nil)
+}
+
+// NewAgentManager1WithConn create a new instance of AgentManager1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewAgentManager1WithConn(conn *bluez.Conn) (*AgentManager1, error) {
 	a := new(AgentManager1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -23,6 +30,7 @@ func NewAgentManager1() (*AgentManager1, error) {
 			Iface: AgentManager1Interface,
 			Path:  dbus.ObjectPath("/org/bluez"),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -86,7 +94,7 @@ func (a *AgentManager1) GetObjectManagerSignal() (chan *dbus.Signal, func(), err
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -151,8 +159,13 @@ RegisterAgent
 
 */
 func (a *AgentManager1) RegisterAgent(agent dbus.ObjectPath, capability string) error {
+	return a.RegisterAgentContext(context.Background(), agent, capability)
+}
+
+// RegisterAgentContext call RegisterAgent, the call is aborted when ctx is done
+func (a *AgentManager1) RegisterAgentContext(ctx context.Context, agent dbus.ObjectPath, capability string) error {
 	
-	return a.client.Call("RegisterAgent", 0, agent, capability).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RegisterAgent", 0, agent, capability).Store())
 	
 }
 
@@ -167,8 +180,13 @@ UnregisterAgent
 
 */
 func (a *AgentManager1) UnregisterAgent(agent dbus.ObjectPath) error {
+	return a.UnregisterAgentContext(context.Background(), agent)
+}
+
+// UnregisterAgentContext call UnregisterAgent, the call is aborted when ctx is done
+func (a *AgentManager1) UnregisterAgentContext(ctx context.Context, agent dbus.ObjectPath) error {
 	
-	return a.client.Call("UnregisterAgent", 0, agent).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "UnregisterAgent", 0, agent).Store())
 	
 }
 
@@ -187,8 +205,13 @@ RequestDefaultAgent
 
 */
 func (a *AgentManager1) RequestDefaultAgent(agent dbus.ObjectPath) error {
+	return a.RequestDefaultAgentContext(context.Background(), agent)
+}
+
+// RequestDefaultAgentContext call RequestDefaultAgent, the call is aborted when ctx is done
+func (a *AgentManager1) RequestDefaultAgentContext(ctx context.Context, agent dbus.ObjectPath) error {
 	
-	return a.client.Call("RequestDefaultAgent", 0, agent).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RequestDefaultAgent", 0, agent).Store())
 	
 }
 
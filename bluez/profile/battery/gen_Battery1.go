@@ -18,6 +18,12 @@ var Battery1Interface = "org.bluez.Battery1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX
 func NewBattery1(objectPath dbus.ObjectPath) (*Battery1, error) {
+	return NewBattery1WithConn(nil, objectPath)
+}
+
+// NewBattery1WithConn create a new instance of Battery1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewBattery1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*Battery1, error) {
 	a := new(Battery1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +31,7 @@ func NewBattery1(objectPath dbus.ObjectPath) (*Battery1, error) {
 			Iface: Battery1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -116,7 +123,7 @@ func (a *Battery1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error) {
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -205,6 +212,7 @@ func (a *Battery1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *Battery1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -17,100 +17,37 @@ func NewDevice(adapterID string, address string) (*Device1, error) {
 
 // GetCharacteristicsList return device characteristics object path list
 func (d *Device1) GetCharacteristicsList() ([]dbus.ObjectPath, error) {
-
-	var chars []dbus.ObjectPath
-
-	om, err := bluez.GetObjectManager()
-	if err != nil {
-		return nil, err
-	}
-
-	list, err := om.GetManagedObjects()
+	objects, err := d.getManagedObjects()
 	if err != nil {
 		return nil, err
 	}
-
-	for path := range list {
-
-		spath := string(path)
-
-		if !strings.HasPrefix(spath, string(d.Path())) {
-			continue
-		}
-
-		charPos := strings.Index(spath, "char")
-		if charPos == -1 {
-			continue
-		}
-
-		if strings.Index(spath[charPos:], "desc") > -1 {
-			continue
-		}
-
-		chars = append(chars, path)
-	}
-
-	return chars, nil
+	return gattObjects(objects, gatt.GattCharacteristic1Interface, d.Path()), nil
 }
 
 // GetDescriptorList returns all descriptors
 func (d *Device1) GetDescriptorList() ([]dbus.ObjectPath, error) {
-	var descr []dbus.ObjectPath
-
-	om, err := bluez.GetObjectManager()
+	objects, err := d.getManagedObjects()
 	if err != nil {
 		return nil, err
 	}
-
-	list, err := om.GetManagedObjects()
-	if err != nil {
-		return nil, err
-	}
-	for path := range list {
-
-		spath := string(path)
-
-		if !strings.HasPrefix(spath, string(d.Path())) {
-			continue
-		}
-
-		charPos := strings.Index(spath, "char")
-		if charPos == -1 {
-			continue
-		}
-
-		if strings.Index(spath[charPos:], "desc") == -1 {
-			continue
-		}
-
-		descr = append(descr, path)
-	}
-
-	return descr, nil
+	return gattObjects(objects, gatt.GattDescriptor1Interface, d.Path()), nil
 }
 
 //GetDescriptors returns all descriptors for a given characteristic
 func (d *Device1) GetDescriptors(char *gatt.GattCharacteristic1) ([]*gatt.GattDescriptor1, error) {
 
-	descrPaths, err := d.GetDescriptorList()
+	objects, err := d.getManagedObjects()
 	if err != nil {
 		return nil, err
 	}
 
 	descrFound := []*gatt.GattDescriptor1{}
-	for _, path := range descrPaths {
-
-		descr, err := gatt.NewGattDescriptor1(path)
+	for _, path := range gattChildren(objects, gatt.GattDescriptor1Interface, "Characteristic", char.Path()) {
+		descr, err := gatt.NewGattDescriptor1WithConn(d.client.Conn(), path)
 		if err != nil {
 			return nil, err
 		}
-
-		charPath := fmt.Sprintf("%s/%s", char.Properties.Service, strings.Replace(char.Properties.UUID, "-", "_", -1))
-
-		if dbus.ObjectPath(charPath) == descr.Properties.Characteristic {
-			descrFound = append(descrFound, descr)
-		}
-
+		descrFound = append(descrFound, descr)
 	}
 
 	if len(descrFound) == 0 {
@@ -131,7 +68,7 @@ func (d *Device1) GetCharacteristics() ([]*gatt.GattCharacteristic1, error) {
 	chars := []*gatt.GattCharacteristic1{}
 	for _, path := range list {
 
-		char, err := gatt.NewGattCharacteristic1(path)
+		char, err := gatt.NewGattCharacteristic1WithConn(d.client.Conn(), path)
 		if err != nil {
 			return nil, err
 		}
@@ -155,7 +92,7 @@ func (d *Device1) GetAllServicesAndUUID() ([]string, error) {
 	var uuidAndService string
 	for _, path := range list {
 
-		char, err := gatt.NewGattCharacteristic1(path)
+		char, err := gatt.NewGattCharacteristic1WithConn(d.client.Conn(), path)
 		if err != nil {
 			return nil, err
 		}
@@ -181,9 +118,9 @@ func (d *Device1) GetCharByUUID(uuid string) (*gatt.GattCharacteristic1, error)
 	return nil, err
 }
 
-// GetCharsByUUID returns all characteristics that match the given UUID.
+// GetCharsByUUID returns all characteristics that match the given UUID in
+// 16, 32 or 128 bit form.
 func (d *Device1) GetCharsByUUID(uuid string) ([]*gatt.GattCharacteristic1, error) {
-	uuid = strings.ToUpper(uuid)
 
 	list, err := d.GetCharacteristicsList()
 	if err != nil {
@@ -194,14 +131,12 @@ func (d *Device1) GetCharsByUUID(uuid string) ([]*gatt.GattCharacteristic1, erro
 
 	for _, path := range list {
 
-		char, err := gatt.NewGattCharacteristic1(path)
+		char, err := gatt.NewGattCharacteristic1WithConn(d.client.Conn(), path)
 		if err != nil {
 			return nil, err
 		}
 
-		cuuid := strings.ToUpper(char.Properties.UUID)
-
-		if cuuid == uuid {
+		if bluez.EqualUUID(char.Properties.UUID, uuid) {
 			charsFound = append(charsFound, char)
 		}
 	}
@@ -212,3 +147,19 @@ func (d *Device1) GetCharsByUUID(uuid string) ([]*gatt.GattCharacteristic1, erro
 
 	return charsFound, nil
 }
+
+// ParseDeviceAddress return the device address from a device object path,
+// eg. /org/bluez/hci0/dev_00_11_22_33_44_55 return 00:11:22:33:44:55
+func ParseDeviceAddress(path dbus.ObjectPath) (string, error) {
+	parts := strings.Split(string(path), "/")
+	for _, part := range parts {
+		if strings.HasPrefix(part, "dev_") {
+			address := strings.Replace(part[4:], "_", ":", -1)
+			if len(address) != 17 {
+				return "", fmt.Errorf("Failed to parse device address from %s", path)
+			}
+			return strings.ToUpper(address), nil
+		}
+	}
+	return "", fmt.Errorf("Failed to parse device address from %s", path)
+}
@@ -0,0 +1,203 @@
+package device
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+)
+
+type managedObjects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+
+// Service is a remote GATT service with its characteristics
+type Service struct {
+	*gatt.GattService1
+	Characteristics []*Characteristic
+}
+
+// Characteristic is a remote GATT characteristic with its descriptors
+type Characteristic struct {
+	*gatt.GattCharacteristic1
+	Descriptors []*gatt.GattDescriptor1
+}
+
+// GetCharacteristic return the first characteristic matching the UUID, nil if not found
+func (s *Service) GetCharacteristic(uuid bluez.UUID) *Characteristic {
+	for _, char := range s.Characteristics {
+		if matchUUID(char.Properties.UUID, uuid) {
+			return char
+		}
+	}
+	return nil
+}
+
+// GetDescriptor return the first descriptor matching the UUID, nil if not found
+func (c *Characteristic) GetDescriptor(uuid bluez.UUID) *gatt.GattDescriptor1 {
+	for _, descr := range c.Descriptors {
+		if matchUUID(descr.Properties.UUID, uuid) {
+			return descr
+		}
+	}
+	return nil
+}
+
+// GetServices return the resolved GATT services of the device with their
+// characteristics and descriptors. Wait for ServicesResolved before calling it
+func (d *Device1) GetServices() ([]*Service, error) {
+
+	objects, err := d.getManagedObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	return newServices(d.client.Conn(), objects, d.Path())
+}
+
+// newServices build the GATT tree of the device from the managed objects
+// properties, without querying each object
+func newServices(conn *bluez.Conn, objects managedObjects, device dbus.ObjectPath) ([]*Service, error) {
+
+	services := []*Service{}
+	for _, path := range gattChildren(objects, gatt.GattService1Interface, "Device", device) {
+
+		s, err := gatt.NewGattService1FromProperties(conn, path, objects[path][gatt.GattService1Interface])
+		if err != nil {
+			return nil, err
+		}
+		service := &Service{GattService1: s, Characteristics: []*Characteristic{}}
+
+		for _, charPath := range gattChildren(objects, gatt.GattCharacteristic1Interface, "Service", path) {
+
+			c, err := gatt.NewGattCharacteristic1FromProperties(conn, charPath, objects[charPath][gatt.GattCharacteristic1Interface])
+			if err != nil {
+				return nil, err
+			}
+			char := &Characteristic{GattCharacteristic1: c, Descriptors: []*gatt.GattDescriptor1{}}
+
+			for _, descrPath := range gattChildren(objects, gatt.GattDescriptor1Interface, "Characteristic", charPath) {
+				descr, err := gatt.NewGattDescriptor1FromProperties(conn, descrPath, objects[descrPath][gatt.GattDescriptor1Interface])
+				if err != nil {
+					return nil, err
+				}
+				char.Descriptors = append(char.Descriptors, descr)
+			}
+
+			service.Characteristics = append(service.Characteristics, char)
+		}
+
+		services = append(services, service)
+	}
+
+	return services, nil
+}
+
+// GetServiceByUUID return the first service matching the UUID
+func (d *Device1) GetServiceByUUID(uuid bluez.UUID) (*Service, error) {
+
+	services, err := d.GetServices()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range services {
+		if matchUUID(service.Properties.UUID, uuid) {
+			return service, nil
+		}
+	}
+
+	return nil, errors.New("service not found")
+}
+
+func matchUUID(value string, uuid bluez.UUID) bool {
+	u, err := bluez.ParseUUID(value)
+	return err == nil && u == uuid
+}
+
+func (d *Device1) getManagedObjects() (managedObjects, error) {
+
+	om, err := d.client.GetObjectsProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := om.GetManagedObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	return managedObjects(list), nil
+}
+
+// gattChildren return the sorted paths of the objects implementing iface
+// whose parent property references parent
+func gattChildren(objects managedObjects, iface string, parentProp string, parent dbus.ObjectPath) []dbus.ObjectPath {
+
+	paths := []dbus.ObjectPath{}
+	for path, ifaces := range objects {
+		props, ok := ifaces[iface]
+		if !ok {
+			continue
+		}
+		variant, ok := props[parentProp]
+		if !ok {
+			continue
+		}
+		if p, ok := variant.Value().(dbus.ObjectPath); ok && p == parent {
+			paths = append(paths, path)
+		}
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i] < paths[j]
+	})
+
+	return paths
+}
+
+// gattObjects return the sorted paths of the objects implementing iface
+// which belong to the device
+func gattObjects(objects managedObjects, iface string, device dbus.ObjectPath) []dbus.ObjectPath {
+
+	services := map[dbus.ObjectPath]bool{}
+	for _, path := range gattChildren(objects, gatt.GattService1Interface, "Device", device) {
+		services[path] = true
+	}
+
+	chars := map[dbus.ObjectPath]bool{}
+	for path, ifaces := range objects {
+		if props, ok := ifaces[gatt.GattCharacteristic1Interface]; ok {
+			if service, ok := props["Service"].Value().(dbus.ObjectPath); ok && services[service] {
+				chars[path] = true
+			}
+		}
+	}
+
+	paths := []dbus.ObjectPath{}
+	for path, ifaces := range objects {
+		if _, ok := ifaces[iface]; !ok {
+			continue
+		}
+		switch iface {
+		case gatt.GattService1Interface:
+			if services[path] {
+				paths = append(paths, path)
+			}
+		case gatt.GattCharacteristic1Interface:
+			if chars[path] {
+				paths = append(paths, path)
+			}
+		case gatt.GattDescriptor1Interface:
+			if char, ok := ifaces[iface]["Characteristic"].Value().(dbus.ObjectPath); ok && chars[char] {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i] < paths[j]
+	})
+
+	return paths
+}
@@ -0,0 +1,112 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func testGattObjects() managedObjects {
+
+	dev := dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55")
+	other := dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_66")
+
+	object := func(iface string, prop string, parent dbus.ObjectPath) map[string]map[string]dbus.Variant {
+		return map[string]map[string]dbus.Variant{
+			iface: {
+				prop: dbus.MakeVariant(parent),
+			},
+		}
+	}
+
+	return managedObjects{
+		dev + "/service000a":                       object(gatt.GattService1Interface, "Device", dev),
+		dev + "/service000a/char000b":              object(gatt.GattCharacteristic1Interface, "Service", dev+"/service000a"),
+		dev + "/service000a/char000b/desc000d":     object(gatt.GattDescriptor1Interface, "Characteristic", dev+"/service000a/char000b"),
+		dev + "/service000a/char000e":              object(gatt.GattCharacteristic1Interface, "Service", dev+"/service000a"),
+		dev + "/service0010":                       object(gatt.GattService1Interface, "Device", dev),
+		dev + "/service0010/char0011":              object(gatt.GattCharacteristic1Interface, "Service", dev+"/service0010"),
+		dev + "/service0010/char0011/desc0013":     object(gatt.GattDescriptor1Interface, "Characteristic", dev+"/service0010/char0011"),
+		other + "/service000a":                     object(gatt.GattService1Interface, "Device", other),
+		other + "/service000a/char000b":            object(gatt.GattCharacteristic1Interface, "Service", other+"/service000a"),
+		other + "/service000a/char000b/desc000d":   object(gatt.GattDescriptor1Interface, "Characteristic", other+"/service000a/char000b"),
+		"/org/bluez/hci0/dev_00_11_22_33_44_55_ff": object(Device1Interface, "Adapter", "/org/bluez/hci0"),
+	}
+}
+
+func TestGattChildren(t *testing.T) {
+
+	dev := dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55")
+	objects := testGattObjects()
+
+	assert.Equal(t,
+		[]dbus.ObjectPath{dev + "/service000a", dev + "/service0010"},
+		gattChildren(objects, gatt.GattService1Interface, "Device", dev))
+
+	assert.Equal(t,
+		[]dbus.ObjectPath{dev + "/service000a/char000b", dev + "/service000a/char000e"},
+		gattChildren(objects, gatt.GattCharacteristic1Interface, "Service", dev+"/service000a"))
+
+	assert.Equal(t,
+		[]dbus.ObjectPath{dev + "/service000a/char000b/desc000d"},
+		gattChildren(objects, gatt.GattDescriptor1Interface, "Characteristic", dev+"/service000a/char000b"))
+
+	assert.Empty(t, gattChildren(objects, gatt.GattDescriptor1Interface, "Characteristic", dev+"/service000a/char000e"))
+}
+
+func TestGattObjects(t *testing.T) {
+
+	dev := dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55")
+	objects := testGattObjects()
+
+	assert.Equal(t,
+		[]dbus.ObjectPath{
+			dev + "/service000a/char000b",
+			dev + "/service000a/char000e",
+			dev + "/service0010/char0011",
+		},
+		gattObjects(objects, gatt.GattCharacteristic1Interface, dev))
+
+	assert.Equal(t,
+		[]dbus.ObjectPath{
+			dev + "/service000a/char000b/desc000d",
+			dev + "/service0010/char0011/desc0013",
+		},
+		gattObjects(objects, gatt.GattDescriptor1Interface, dev))
+}
+
+func TestNewServices(t *testing.T) {
+
+	dev := dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_33_44_55")
+	objects := testGattObjects()
+	objects[dev+"/service0010"][gatt.GattService1Interface]["UUID"] = dbus.MakeVariant("0000180d-0000-1000-8000-00805f9b34fb")
+	objects[dev+"/service0010/char0011"][gatt.GattCharacteristic1Interface]["UUID"] = dbus.MakeVariant("00002a37-0000-1000-8000-00805f9b34fb")
+	objects[dev+"/service0010/char0011"][gatt.GattCharacteristic1Interface]["Flags"] = dbus.MakeVariant([]string{"notify"})
+
+	services, err := newServices(nil, objects, dev)
+	assert.NoError(t, err)
+	assert.Len(t, services, 2)
+
+	assert.Equal(t, dev+"/service000a", services[0].Path())
+	assert.Equal(t, dev, services[0].Properties.Device)
+	assert.Len(t, services[0].Characteristics, 2)
+	assert.Len(t, services[0].Characteristics[0].Descriptors, 1)
+	assert.Empty(t, services[0].Characteristics[1].Descriptors)
+
+	service, err := bluez.ParseUUID("180d")
+	assert.NoError(t, err)
+	char, err := bluez.ParseUUID("2a37")
+	assert.NoError(t, err)
+
+	hr := services[1]
+	assert.Equal(t, "0000180d-0000-1000-8000-00805f9b34fb", hr.Properties.UUID)
+	c := hr.GetCharacteristic(char)
+	assert.NotNil(t, c)
+	assert.Equal(t, dev+"/service0010/char0011", c.Path())
+	assert.Equal(t, []string{"notify"}, c.Properties.Flags)
+	assert.Equal(t, dev+"/service0010", c.Properties.Service)
+	assert.True(t, matchUUID(hr.Properties.UUID, service))
+}
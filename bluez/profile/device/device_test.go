@@ -0,0 +1,25 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDeviceAddress(t *testing.T) {
+
+	address, err := ParseDeviceAddress(dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_aa_bb_cc"))
+	assert.NoError(t, err)
+	assert.Equal(t, "00:11:22:AA:BB:CC", address)
+
+	address, err = ParseDeviceAddress(dbus.ObjectPath("/org/bluez/hci0/dev_00_11_22_AA_BB_CC/service000a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "00:11:22:AA:BB:CC", address)
+
+	_, err = ParseDeviceAddress(dbus.ObjectPath("/org/bluez/hci0"))
+	assert.Error(t, err)
+
+	_, err = ParseDeviceAddress(dbus.ObjectPath("/org/bluez/hci0/dev_00_11"))
+	assert.Error(t, err)
+}
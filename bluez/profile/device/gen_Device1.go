@@ -3,6 +3,7 @@ package device
 
 
 import (
+	"context"
 	"github.com/godbus/dbus/v5"
 	"github.com/dimonzozo/go-bluetooth/bluez"
 	"github.com/dimonzozo/go-bluetooth/props"
@@ -18,6 +19,12 @@ var Device1Interface = "org.bluez.Device1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX
 func NewDevice1(objectPath dbus.ObjectPath) (*Device1, error) {
+	return NewDevice1WithConn(nil, objectPath)
+}
+
+// NewDevice1WithConn create a new instance of Device1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewDevice1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*Device1, error) {
 	a := new(Device1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewDevice1(objectPath dbus.ObjectPath) (*Device1, error) {
 			Iface: Device1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -590,7 +598,7 @@ func (a *Device1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error) {
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -679,6 +687,7 @@ func (a *Device1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *Device1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -729,8 +738,13 @@ Connect
 
 */
 func (a *Device1) Connect() error {
+	return a.ConnectContext(context.Background())
+}
+
+// ConnectContext call Connect, the call is aborted when ctx is done
+func (a *Device1) ConnectContext(ctx context.Context) error {
 	
-	return a.client.Call("Connect", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Connect", 0, ).Store())
 	
 }
 
@@ -755,8 +769,13 @@ Disconnect
 
 */
 func (a *Device1) Disconnect() error {
+	return a.DisconnectContext(context.Background())
+}
+
+// DisconnectContext call Disconnect, the call is aborted when ctx is done
+func (a *Device1) DisconnectContext(ctx context.Context) error {
 	
-	return a.client.Call("Disconnect", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Disconnect", 0, ).Store())
 	
 }
 
@@ -775,8 +794,13 @@ ConnectProfile
 
 */
 func (a *Device1) ConnectProfile(uuid string) error {
+	return a.ConnectProfileContext(context.Background(), uuid)
+}
+
+// ConnectProfileContext call ConnectProfile, the call is aborted when ctx is done
+func (a *Device1) ConnectProfileContext(ctx context.Context, uuid string) error {
 	
-	return a.client.Call("ConnectProfile", 0, uuid).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "ConnectProfile", 0, uuid).Store())
 	
 }
 
@@ -798,8 +822,13 @@ DisconnectProfile
 
 */
 func (a *Device1) DisconnectProfile(uuid string) error {
+	return a.DisconnectProfileContext(context.Background(), uuid)
+}
+
+// DisconnectProfileContext call DisconnectProfile, the call is aborted when ctx is done
+func (a *Device1) DisconnectProfileContext(ctx context.Context, uuid string) error {
 	
-	return a.client.Call("DisconnectProfile", 0, uuid).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "DisconnectProfile", 0, uuid).Store())
 	
 }
 
@@ -809,8 +838,18 @@ Pair
 
 */
 func (a *Device1) Pair() error {
+	return a.PairContext(context.Background())
+}
+
+// PairContext call Pair, the call is aborted when ctx is done
+// and CancelPairing is called to stop the ongoing operation
+func (a *Device1) PairContext(ctx context.Context) error {
 	
-	return a.client.Call("Pair", 0, ).Store()
+	err := a.client.CallWithContext(ctx, "Pair", 0, ).Store()
+	if err != nil && ctx.Err() != nil {
+		a.client.Call("CancelPairing", 0).Store()
+	}
+	return bluez.ParseError(err)
 	
 }
 
@@ -825,8 +864,13 @@ CancelPairing
 
 */
 func (a *Device1) CancelPairing() error {
+	return a.CancelPairingContext(context.Background())
+}
+
+// CancelPairingContext call CancelPairing, the call is aborted when ctx is done
+func (a *Device1) CancelPairingContext(ctx context.Context) error {
 	
-	return a.client.Call("CancelPairing", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "CancelPairing", 0, ).Store())
 	
 }
 
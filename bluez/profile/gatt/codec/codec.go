@@ -0,0 +1,104 @@
+// Package codec marshal and unmarshal the values of the standard GATT
+// characteristics, keyed by their SIG assigned UUID
+package codec
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
+)
+
+// Value is a characteristic value with a GATT binary representation
+type Value interface {
+	MarshalGATT() ([]byte, error)
+	UnmarshalGATT(b []byte) error
+}
+
+var registry = map[bluez.UUID]func() Value{}
+var registryLock sync.RWMutex
+
+// Register the constructor of the value of a characteristic UUID,
+// an existing registration is replaced
+func Register(uuid bluez.UUID, fn func() Value) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry[uuid] = fn
+}
+
+// New return an empty value for a characteristic UUID
+func New(uuid bluez.UUID) (Value, error) {
+	registryLock.RLock()
+	fn, ok := registry[uuid]
+	registryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("No codec for %s", uuid)
+	}
+	return fn(), nil
+}
+
+// Unmarshal decode the value of a characteristic UUID
+func Unmarshal(uuid bluez.UUID, b []byte) (Value, error) {
+	v, err := New(uuid)
+	if err != nil {
+		return nil, err
+	}
+	err = v.UnmarshalGATT(b)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Read read and decode the value of a remote characteristic
+func Read(char *gatt.GattCharacteristic1, options map[string]interface{}) (Value, error) {
+
+	uuid, err := bluez.ParseUUID(char.Properties.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := New(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ReadInto(char, v, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// ReadInto read the value of a remote characteristic and decode it in v
+func ReadInto(char *gatt.GattCharacteristic1, v Value, options map[string]interface{}) error {
+	if options == nil {
+		options = map[string]interface{}{}
+	}
+	b, err := char.ReadValue(options)
+	if err != nil {
+		return err
+	}
+	return v.UnmarshalGATT(b)
+}
+
+// Write encode v and write it to a remote characteristic
+func Write(char *gatt.GattCharacteristic1, v Value, options map[string]interface{}) error {
+	b, err := v.MarshalGATT()
+	if err != nil {
+		return err
+	}
+	if options == nil {
+		options = map[string]interface{}{}
+	}
+	return char.WriteValue(b, options)
+}
+
+func checkLength(name string, b []byte, min int) error {
+	if len(b) < min {
+		return fmt.Errorf("%s: expected at least %d bytes, got %d", name, min, len(b))
+	}
+	return nil
+}
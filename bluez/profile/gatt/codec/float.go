@@ -0,0 +1,146 @@
+package codec
+
+import (
+	"fmt"
+	"math"
+)
+
+// IEEE-11073 16 bit SFLOAT special values
+const (
+	SFloatNaN         uint16 = 0x07FF
+	SFloatNRes        uint16 = 0x0800
+	SFloatPosInfinity uint16 = 0x07FE
+	SFloatNegInfinity uint16 = 0x0802
+	SFloatReserved    uint16 = 0x0801
+)
+
+// IEEE-11073 32 bit FLOAT special values
+const (
+	FloatNaN         uint32 = 0x007FFFFF
+	FloatNRes        uint32 = 0x00800000
+	FloatPosInfinity uint32 = 0x007FFFFE
+	FloatNegInfinity uint32 = 0x00800002
+	FloatReserved    uint32 = 0x00800001
+)
+
+// DecodeSFloat convert an IEEE-11073 SFLOAT, a 4 bit exponent and a
+// 12 bit mantissa. NaN, NRes and Reserved decode to NaN
+func DecodeSFloat(raw uint16) float64 {
+
+	mantissa := int32(raw & 0x0FFF)
+	switch uint16(mantissa) {
+	case SFloatNaN, SFloatNRes, SFloatReserved:
+		return math.NaN()
+	case SFloatPosInfinity:
+		return math.Inf(1)
+	case SFloatNegInfinity & 0x0FFF:
+		return math.Inf(-1)
+	}
+
+	if mantissa >= 0x0800 {
+		mantissa -= 0x1000
+	}
+	exponent := int32(raw >> 12)
+	if exponent >= 0x8 {
+		exponent -= 0x10
+	}
+
+	return scale(mantissa, exponent)
+}
+
+// EncodeSFloat convert a value to an IEEE-11073 SFLOAT with the best
+// precision available
+func EncodeSFloat(v float64) (uint16, error) {
+
+	switch {
+	case math.IsNaN(v):
+		return SFloatNaN, nil
+	case math.IsInf(v, 1):
+		return SFloatPosInfinity, nil
+	case math.IsInf(v, -1):
+		return SFloatNegInfinity, nil
+	}
+
+	mantissa, exponent, err := encodeFloat(v, -2046, 2045, -8, 7)
+	if err != nil {
+		return 0, fmt.Errorf("SFLOAT: %s", err)
+	}
+
+	return uint16(exponent&0xF)<<12 | uint16(mantissa&0x0FFF), nil
+}
+
+// DecodeFloat convert an IEEE-11073 FLOAT, an 8 bit exponent and a
+// 24 bit mantissa. NaN, NRes and Reserved decode to NaN
+func DecodeFloat(raw uint32) float64 {
+
+	mantissa := int32(raw & 0x00FFFFFF)
+	switch uint32(mantissa) {
+	case FloatNaN, FloatNRes, FloatReserved:
+		return math.NaN()
+	case FloatPosInfinity:
+		return math.Inf(1)
+	case FloatNegInfinity:
+		return math.Inf(-1)
+	}
+
+	if mantissa >= 0x00800000 {
+		mantissa -= 0x01000000
+	}
+	exponent := int32(int8(raw >> 24))
+
+	return scale(mantissa, exponent)
+}
+
+// EncodeFloat convert a value to an IEEE-11073 FLOAT with the best
+// precision available
+func EncodeFloat(v float64) (uint32, error) {
+
+	switch {
+	case math.IsNaN(v):
+		return FloatNaN, nil
+	case math.IsInf(v, 1):
+		return FloatPosInfinity, nil
+	case math.IsInf(v, -1):
+		return FloatNegInfinity, nil
+	}
+
+	mantissa, exponent, err := encodeFloat(v, -8388606, 8388605, -128, 127)
+	if err != nil {
+		return 0, fmt.Errorf("FLOAT: %s", err)
+	}
+
+	return uint32(exponent&0xFF)<<24 | uint32(mantissa&0x00FFFFFF), nil
+}
+
+// scale return mantissa * 10^exponent, dividing for negative exponents
+// to get the closest float64, eg. 36.6 instead of 36.600000000000001
+func scale(mantissa int32, exponent int32) float64 {
+	if exponent < 0 {
+		return float64(mantissa) / math.Pow10(int(-exponent))
+	}
+	return float64(mantissa) * math.Pow10(int(exponent))
+}
+
+// encodeFloat find the smallest exponent whose mantissa fits the range
+func encodeFloat(v float64, minMantissa, maxMantissa int64, minExponent, maxExponent int) (int64, int, error) {
+
+	if v == 0 {
+		return 0, 0, nil
+	}
+
+	for exponent := minExponent; exponent <= maxExponent; exponent++ {
+		mantissa := math.Round(v / math.Pow10(exponent))
+		if mantissa < float64(minMantissa) || mantissa > float64(maxMantissa) {
+			continue
+		}
+		// drop the trailing zeros, eg. 100 is encoded as 1e2
+		m := int64(mantissa)
+		for m != 0 && m%10 == 0 && exponent < maxExponent {
+			m /= 10
+			exponent++
+		}
+		return m, exponent, nil
+	}
+
+	return 0, 0, fmt.Errorf("%g out of range", v)
+}
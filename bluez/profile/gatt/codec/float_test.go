@@ -0,0 +1,62 @@
+package codec
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSFloat(t *testing.T) {
+
+	assert.Equal(t, 36.6, DecodeSFloat(0xF16E))
+	assert.Equal(t, -1.0, DecodeSFloat(0x0FFF))
+	assert.Equal(t, 1200.0, DecodeSFloat(0x1078))
+	assert.True(t, math.IsNaN(DecodeSFloat(SFloatNaN)))
+	assert.True(t, math.IsNaN(DecodeSFloat(SFloatNRes)))
+	assert.True(t, math.IsInf(DecodeSFloat(SFloatPosInfinity), 1))
+	assert.True(t, math.IsInf(DecodeSFloat(SFloatNegInfinity), -1))
+
+	raw, err := EncodeSFloat(36.6)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0xF16E), raw)
+
+	raw, err = EncodeSFloat(1200)
+	assert.NoError(t, err)
+	assert.Equal(t, 1200.0, DecodeSFloat(raw))
+
+	raw, err = EncodeSFloat(-0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, -0.5, DecodeSFloat(raw))
+
+	raw, err = EncodeSFloat(math.NaN())
+	assert.NoError(t, err)
+	assert.Equal(t, SFloatNaN, raw)
+
+	_, err = EncodeSFloat(1e12)
+	assert.Error(t, err)
+}
+
+func TestFloat(t *testing.T) {
+
+	assert.Equal(t, 36.6, DecodeFloat(0xFF00016E))
+	assert.Equal(t, -273.15, DecodeFloat(0xFEFF954D))
+	assert.True(t, math.IsNaN(DecodeFloat(FloatNaN)))
+	assert.True(t, math.IsInf(DecodeFloat(FloatNegInfinity), -1))
+
+	raw, err := EncodeFloat(36.6)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0xFF00016E), raw)
+
+	raw, err = EncodeFloat(-273.15)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0xFEFF954D), raw)
+
+	raw, err = EncodeFloat(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, DecodeFloat(raw))
+
+	raw, err = EncodeFloat(98.6123)
+	assert.NoError(t, err)
+	assert.InDelta(t, 98.6123, DecodeFloat(raw), 1e-9)
+}
@@ -0,0 +1,426 @@
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+)
+
+// SIG characteristic UUIDs with a registered codec
+var (
+	DeviceNameUUID              = bluez.UUID16(0x2A00)
+	BatteryLevelUUID            = bluez.UUID16(0x2A19)
+	TemperatureMeasurementUUID  = bluez.UUID16(0x2A1C)
+	IntermediateTemperatureUUID = bluez.UUID16(0x2A1E)
+	SystemIDUUID                = bluez.UUID16(0x2A23)
+	ModelNumberStringUUID       = bluez.UUID16(0x2A24)
+	SerialNumberStringUUID      = bluez.UUID16(0x2A25)
+	FirmwareRevisionStringUUID  = bluez.UUID16(0x2A26)
+	HardwareRevisionStringUUID  = bluez.UUID16(0x2A27)
+	SoftwareRevisionStringUUID  = bluez.UUID16(0x2A28)
+	ManufacturerNameStringUUID  = bluez.UUID16(0x2A29)
+	CurrentTimeUUID             = bluez.UUID16(0x2A2B)
+	HeartRateMeasurementUUID    = bluez.UUID16(0x2A37)
+	PnPIDUUID                   = bluez.UUID16(0x2A50)
+)
+
+func init() {
+	newString := func() Value { return new(String) }
+	for _, uuid := range []bluez.UUID{
+		DeviceNameUUID,
+		ModelNumberStringUUID,
+		SerialNumberStringUUID,
+		FirmwareRevisionStringUUID,
+		HardwareRevisionStringUUID,
+		SoftwareRevisionStringUUID,
+		ManufacturerNameStringUUID,
+	} {
+		Register(uuid, newString)
+	}
+
+	newTemperature := func() Value { return new(TemperatureMeasurement) }
+	Register(TemperatureMeasurementUUID, newTemperature)
+	Register(IntermediateTemperatureUUID, newTemperature)
+
+	Register(BatteryLevelUUID, func() Value { return new(BatteryLevel) })
+	Register(SystemIDUUID, func() Value { return new(SystemID) })
+	Register(CurrentTimeUUID, func() Value { return new(CurrentTime) })
+	Register(HeartRateMeasurementUUID, func() Value { return new(HeartRateMeasurement) })
+	Register(PnPIDUUID, func() Value { return new(PnPID) })
+}
+
+// String is an UTF-8 string value, eg. Device Name or Manufacturer Name String
+type String string
+
+// MarshalGATT encode the value
+func (v *String) MarshalGATT() ([]byte, error) {
+	return []byte(*v), nil
+}
+
+// UnmarshalGATT decode the value, trailing NUL bytes are removed
+func (v *String) UnmarshalGATT(b []byte) error {
+	for len(b) > 0 && b[len(b)-1] == 0 {
+		b = b[:len(b)-1]
+	}
+	*v = String(b)
+	return nil
+}
+
+// BatteryLevel is the battery charge in percent
+type BatteryLevel uint8
+
+// MarshalGATT encode the value
+func (v *BatteryLevel) MarshalGATT() ([]byte, error) {
+	if *v > 100 {
+		return nil, fmt.Errorf("Battery Level: %d out of range", *v)
+	}
+	return []byte{byte(*v)}, nil
+}
+
+// UnmarshalGATT decode the value
+func (v *BatteryLevel) UnmarshalGATT(b []byte) error {
+	if err := checkLength("Battery Level", b, 1); err != nil {
+		return err
+	}
+	*v = BatteryLevel(b[0])
+	return nil
+}
+
+// Heart Rate Measurement flags
+const (
+	heartRateFormatUint16     = 0x01
+	heartRateContactDetected  = 0x02
+	heartRateContactSupported = 0x04
+	heartRateEnergyExpended   = 0x08
+	heartRateRRInterval       = 0x10
+)
+
+// HeartRateMeasurement is the value of the Heart Rate Measurement characteristic
+type HeartRateMeasurement struct {
+	// Value is the heart rate in beats per minute
+	Value uint16
+	// SensorContactSupported is set if the sensor reports the skin contact
+	SensorContactSupported bool
+	SensorContactDetected  bool
+	// EnergyExpended in kilo Joules, valid only if HasEnergyExpended is set
+	EnergyExpended    uint16
+	HasEnergyExpended bool
+	// RRIntervals in 1/1024 seconds resolution
+	RRIntervals []time.Duration
+}
+
+// MarshalGATT encode the value, the heart rate uses 8 bits when possible
+func (v *HeartRateMeasurement) MarshalGATT() ([]byte, error) {
+
+	flags := byte(0)
+	b := []byte{0}
+
+	if v.Value > 0xFF {
+		flags |= heartRateFormatUint16
+		b = appendUint16(b, v.Value)
+	} else {
+		b = append(b, byte(v.Value))
+	}
+
+	if v.SensorContactSupported {
+		flags |= heartRateContactSupported
+		if v.SensorContactDetected {
+			flags |= heartRateContactDetected
+		}
+	}
+
+	if v.HasEnergyExpended {
+		flags |= heartRateEnergyExpended
+		b = appendUint16(b, v.EnergyExpended)
+	}
+
+	if len(v.RRIntervals) > 0 {
+		flags |= heartRateRRInterval
+		for _, rr := range v.RRIntervals {
+			ticks := math.Round(rr.Seconds() * 1024)
+			if ticks < 0 || ticks > math.MaxUint16 {
+				return nil, fmt.Errorf("Heart Rate Measurement: RR interval %s out of range", rr)
+			}
+			b = appendUint16(b, uint16(ticks))
+		}
+	}
+
+	b[0] = flags
+	return b, nil
+}
+
+// UnmarshalGATT decode the value
+func (v *HeartRateMeasurement) UnmarshalGATT(b []byte) error {
+
+	const name = "Heart Rate Measurement"
+	if err := checkLength(name, b, 2); err != nil {
+		return err
+	}
+
+	flags := b[0]
+	b = b[1:]
+	*v = HeartRateMeasurement{}
+
+	if flags&heartRateFormatUint16 != 0 {
+		if err := checkLength(name, b, 2); err != nil {
+			return err
+		}
+		v.Value = binary.LittleEndian.Uint16(b)
+		b = b[2:]
+	} else {
+		v.Value = uint16(b[0])
+		b = b[1:]
+	}
+
+	v.SensorContactSupported = flags&heartRateContactSupported != 0
+	v.SensorContactDetected = v.SensorContactSupported && flags&heartRateContactDetected != 0
+
+	if flags&heartRateEnergyExpended != 0 {
+		if err := checkLength(name, b, 2); err != nil {
+			return err
+		}
+		v.EnergyExpended = binary.LittleEndian.Uint16(b)
+		v.HasEnergyExpended = true
+		b = b[2:]
+	}
+
+	if flags&heartRateRRInterval != 0 {
+		for len(b) >= 2 {
+			ticks := binary.LittleEndian.Uint16(b)
+			v.RRIntervals = append(v.RRIntervals, time.Duration(ticks)*time.Second/1024)
+			b = b[2:]
+		}
+	}
+
+	return nil
+}
+
+// Temperature Measurement flags
+const (
+	temperatureFahrenheit = 0x01
+	temperatureTimestamp  = 0x02
+	temperatureType       = 0x04
+)
+
+// TemperatureMeasurement is the value of the Temperature Measurement and
+// Intermediate Temperature characteristics
+type TemperatureMeasurement struct {
+	// Value is in Celsius degrees, or Fahrenheit if Fahrenheit is set
+	Value      float64
+	Fahrenheit bool
+	// Timestamp is valid only if HasTimestamp is set
+	Timestamp    time.Time
+	HasTimestamp bool
+	// Type is the location of the measurement, valid only if HasType is set
+	Type    uint8
+	HasType bool
+}
+
+// MarshalGATT encode the value
+func (v *TemperatureMeasurement) MarshalGATT() ([]byte, error) {
+
+	raw, err := EncodeFloat(v.Value)
+	if err != nil {
+		return nil, fmt.Errorf("Temperature Measurement: %s", err)
+	}
+
+	flags := byte(0)
+	if v.Fahrenheit {
+		flags |= temperatureFahrenheit
+	}
+
+	b := []byte{0}
+	b = appendUint32(b, raw)
+
+	if v.HasTimestamp {
+		flags |= temperatureTimestamp
+		b = append(b, marshalDateTime(v.Timestamp)...)
+	}
+
+	if v.HasType {
+		flags |= temperatureType
+		b = append(b, v.Type)
+	}
+
+	b[0] = flags
+	return b, nil
+}
+
+// UnmarshalGATT decode the value
+func (v *TemperatureMeasurement) UnmarshalGATT(b []byte) error {
+
+	const name = "Temperature Measurement"
+	if err := checkLength(name, b, 5); err != nil {
+		return err
+	}
+
+	flags := b[0]
+	*v = TemperatureMeasurement{
+		Value:      DecodeFloat(binary.LittleEndian.Uint32(b[1:5])),
+		Fahrenheit: flags&temperatureFahrenheit != 0,
+	}
+	b = b[5:]
+
+	if flags&temperatureTimestamp != 0 {
+		if err := checkLength(name, b, dateTimeLength); err != nil {
+			return err
+		}
+		v.Timestamp = unmarshalDateTime(b)
+		v.HasTimestamp = true
+		b = b[dateTimeLength:]
+	}
+
+	if flags&temperatureType != 0 {
+		if err := checkLength(name, b, 1); err != nil {
+			return err
+		}
+		v.Type = b[0]
+		v.HasType = true
+	}
+
+	return nil
+}
+
+// SystemID is the value of the System ID characteristic
+type SystemID struct {
+	// ManufacturerID is a 40 bit identifier
+	ManufacturerID uint64
+	// OUI is the 24 bit Organizationally Unique Identifier
+	OUI uint32
+}
+
+// MarshalGATT encode the value
+func (v *SystemID) MarshalGATT() ([]byte, error) {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v.ManufacturerID&0xFFFFFFFFFF|uint64(v.OUI&0xFFFFFF)<<40)
+	return b, nil
+}
+
+// UnmarshalGATT decode the value
+func (v *SystemID) UnmarshalGATT(b []byte) error {
+	if err := checkLength("System ID", b, 8); err != nil {
+		return err
+	}
+	raw := binary.LittleEndian.Uint64(b)
+	v.ManufacturerID = raw & 0xFFFFFFFFFF
+	v.OUI = uint32(raw >> 40)
+	return nil
+}
+
+// PnPID is the value of the PnP ID characteristic
+type PnPID struct {
+	// VendorIDSource is 1 for a SIG company identifier, 2 for an USB vendor ID
+	VendorIDSource uint8
+	VendorID       uint16
+	ProductID      uint16
+	ProductVersion uint16
+}
+
+// MarshalGATT encode the value
+func (v *PnPID) MarshalGATT() ([]byte, error) {
+	b := []byte{v.VendorIDSource}
+	b = appendUint16(b, v.VendorID)
+	b = appendUint16(b, v.ProductID)
+	b = appendUint16(b, v.ProductVersion)
+	return b, nil
+}
+
+// UnmarshalGATT decode the value
+func (v *PnPID) UnmarshalGATT(b []byte) error {
+	if err := checkLength("PnP ID", b, 7); err != nil {
+		return err
+	}
+	v.VendorIDSource = b[0]
+	v.VendorID = binary.LittleEndian.Uint16(b[1:])
+	v.ProductID = binary.LittleEndian.Uint16(b[3:])
+	v.ProductVersion = binary.LittleEndian.Uint16(b[5:])
+	return nil
+}
+
+// Current Time adjust reasons
+const (
+	AdjustManualTimeUpdate        = 0x01
+	AdjustExternalReferenceUpdate = 0x02
+	AdjustTimeZoneChange          = 0x04
+	AdjustDSTChange               = 0x08
+)
+
+// CurrentTime is the value of the Current Time characteristic
+type CurrentTime struct {
+	// Time is the local time, with 1/256 seconds resolution
+	Time time.Time
+	// AdjustReason is a mask of the Adjust constants
+	AdjustReason uint8
+}
+
+// MarshalGATT encode the value
+func (v *CurrentTime) MarshalGATT() ([]byte, error) {
+	b := marshalDateTime(v.Time)
+	b = append(b, dayOfWeek(v.Time))
+	// Nanosecond()*256 overflows a 32 bit int
+	b = append(b, byte(v.Time.Nanosecond()/(int(time.Second)/256)))
+	b = append(b, v.AdjustReason)
+	return b, nil
+}
+
+// UnmarshalGATT decode the value
+func (v *CurrentTime) UnmarshalGATT(b []byte) error {
+	if err := checkLength("Current Time", b, dateTimeLength+3); err != nil {
+		return err
+	}
+	v.Time = unmarshalDateTime(b)
+	if !v.Time.IsZero() {
+		v.Time = v.Time.Add(time.Duration(b[dateTimeLength+1]) * time.Second / 256)
+	}
+	v.AdjustReason = b[dateTimeLength+2]
+	return nil
+}
+
+const dateTimeLength = 7
+
+// marshalDateTime encode a Date Time, the year is 0 if t is zero
+func marshalDateTime(t time.Time) []byte {
+	b := make([]byte, dateTimeLength)
+	if t.IsZero() {
+		return b
+	}
+	binary.LittleEndian.PutUint16(b, uint16(t.Year()))
+	b[2] = byte(t.Month())
+	b[3] = byte(t.Day())
+	b[4] = byte(t.Hour())
+	b[5] = byte(t.Minute())
+	b[6] = byte(t.Second())
+	return b
+}
+
+// unmarshalDateTime decode a Date Time in the local time zone,
+// unknown year, month or day decode to a zero time
+func unmarshalDateTime(b []byte) time.Time {
+	year := int(binary.LittleEndian.Uint16(b))
+	if year == 0 || b[2] == 0 || b[3] == 0 {
+		return time.Time{}
+	}
+	return time.Date(year, time.Month(b[2]), int(b[3]), int(b[4]), int(b[5]), int(b[6]), 0, time.Local)
+}
+
+// dayOfWeek return the GATT day of week, Monday is 1 and Sunday is 7
+func dayOfWeek(t time.Time) byte {
+	if t.IsZero() {
+		return 0
+	}
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return byte(t.Weekday())
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
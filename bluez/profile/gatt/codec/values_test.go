@@ -0,0 +1,125 @@
+package codec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalByUUID(t *testing.T) {
+
+	v, err := Unmarshal(bluez.MustParseUUID("2a19"), []byte{64})
+	assert.NoError(t, err)
+	assert.Equal(t, BatteryLevel(64), *v.(*BatteryLevel))
+
+	v, err = Unmarshal(ManufacturerNameStringUUID, []byte("ACME\x00"))
+	assert.NoError(t, err)
+	assert.Equal(t, String("ACME"), *v.(*String))
+
+	_, err = Unmarshal(bluez.UUID16(0x2a19), []byte{})
+	assert.Error(t, err)
+
+	_, err = Unmarshal(bluez.UUID16(0xffff), []byte{1})
+	assert.Error(t, err)
+}
+
+func TestHeartRateMeasurement(t *testing.T) {
+
+	v := HeartRateMeasurement{}
+	assert.NoError(t, v.UnmarshalGATT([]byte{0x16, 72, 0x00, 0x04, 0x00, 0x02}))
+	assert.Equal(t, HeartRateMeasurement{
+		Value:                  72,
+		SensorContactSupported: true,
+		SensorContactDetected:  true,
+		RRIntervals:            []time.Duration{time.Second, 500 * time.Millisecond},
+	}, v)
+
+	b, err := v.MarshalGATT()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x16, 72, 0x00, 0x04, 0x00, 0x02}, b)
+
+	v = HeartRateMeasurement{Value: 300, HasEnergyExpended: true, EnergyExpended: 1000}
+	b, err = v.MarshalGATT()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x09, 0x2c, 0x01, 0xe8, 0x03}, b)
+
+	decoded := HeartRateMeasurement{}
+	assert.NoError(t, decoded.UnmarshalGATT(b))
+	assert.Equal(t, v, decoded)
+
+	assert.Error(t, decoded.UnmarshalGATT([]byte{0x01, 0x2c}))
+}
+
+func TestTemperatureMeasurement(t *testing.T) {
+
+	ts := time.Date(2019, time.November, 3, 14, 25, 30, 0, time.Local)
+	v := TemperatureMeasurement{
+		Value:        36.6,
+		Timestamp:    ts,
+		HasTimestamp: true,
+		Type:         2,
+		HasType:      true,
+	}
+
+	b, err := v.MarshalGATT()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x06, 0x6e, 0x01, 0x00, 0xff, 0xe3, 0x07, 11, 3, 14, 25, 30, 2}, b)
+
+	decoded := TemperatureMeasurement{}
+	assert.NoError(t, decoded.UnmarshalGATT(b))
+	assert.Equal(t, 36.6, decoded.Value)
+	assert.True(t, decoded.Timestamp.Equal(ts))
+	assert.Equal(t, uint8(2), decoded.Type)
+	assert.False(t, decoded.Fahrenheit)
+
+	assert.NoError(t, decoded.UnmarshalGATT([]byte{0x01, 0x6e, 0x01, 0x00, 0xff}))
+	assert.True(t, decoded.Fahrenheit)
+	assert.False(t, decoded.HasTimestamp)
+
+	assert.Error(t, decoded.UnmarshalGATT([]byte{0x02, 0x6e, 0x01, 0x00, 0xff, 0xe3}))
+}
+
+func TestCurrentTime(t *testing.T) {
+
+	// a Sunday
+	ts := time.Date(2019, time.November, 3, 14, 25, 30, int(time.Second/2), time.Local)
+	v := CurrentTime{Time: ts, AdjustReason: AdjustManualTimeUpdate}
+
+	b, err := v.MarshalGATT()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xe3, 0x07, 11, 3, 14, 25, 30, 7, 128, 1}, b)
+
+	decoded := CurrentTime{}
+	assert.NoError(t, decoded.UnmarshalGATT(b))
+	assert.True(t, decoded.Time.Equal(ts))
+	assert.Equal(t, uint8(AdjustManualTimeUpdate), decoded.AdjustReason)
+
+	// a fraction close to 1s is the last 1/256 step
+	v.Time = ts.Add(time.Second/2 - 1)
+	b, err = v.MarshalGATT()
+	assert.NoError(t, err)
+	assert.Equal(t, byte(255), b[8])
+}
+
+func TestDeviceInformation(t *testing.T) {
+
+	pnp := PnPID{VendorIDSource: 1, VendorID: 0x004c, ProductID: 0x1234, ProductVersion: 0x0100}
+	b, err := pnp.MarshalGATT()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 0x4c, 0, 0x34, 0x12, 0, 1}, b)
+
+	decodedPnP := PnPID{}
+	assert.NoError(t, decodedPnP.UnmarshalGATT(b))
+	assert.Equal(t, pnp, decodedPnP)
+
+	id := SystemID{ManufacturerID: 0x0102030405, OUI: 0xaabbcc}
+	b, err = id.MarshalGATT()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x05, 0x04, 0x03, 0x02, 0x01, 0xcc, 0xbb, 0xaa}, b)
+
+	decodedID := SystemID{}
+	assert.NoError(t, decodedID.UnmarshalGATT(b))
+	assert.Equal(t, id, decodedID)
+}
@@ -0,0 +1,29 @@
+package gatt
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// AcquireWriter acquire a socket to write the characteristic value without a
+// DBus round trip per write. mtu is the maximum size of a single write,
+// close the writer to release the socket
+func (a *GattCharacteristic1) AcquireWriter(options map[string]interface{}) (io.WriteCloser, uint16, error) {
+	fd, mtu, err := a.AcquireWrite(options)
+	if err != nil {
+		return nil, 0, err
+	}
+	return os.NewFile(uintptr(fd), fmt.Sprintf("%s:write", a.Path())), mtu, nil
+}
+
+// AcquireNotifyReader acquire a socket to receive the characteristic notifications.
+// Each Read returns a single notification, use a buffer of at least mtu bytes.
+// Close the reader to stop notifications
+func (a *GattCharacteristic1) AcquireNotifyReader(options map[string]interface{}) (io.ReadCloser, uint16, error) {
+	fd, mtu, err := a.AcquireNotify(options)
+	if err != nil {
+		return nil, 0, err
+	}
+	return os.NewFile(uintptr(fd), fmt.Sprintf("%s:notify", a.Path())), mtu, nil
+}
@@ -0,0 +1,57 @@
+package gatt
+
+import (
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/godbus/dbus/v5"
+)
+
+func newGattClient(conn *bluez.Conn, iface string, objectPath dbus.ObjectPath) *bluez.Client {
+	return bluez.NewClient(
+		&bluez.Config{
+			Name:  "org.bluez",
+			Iface: iface,
+			Path:  objectPath,
+			Bus:   bluez.SystemBus,
+			Conn:  conn,
+		},
+	)
+}
+
+// NewGattService1FromProperties create a GattService1 from properties already
+// known, eg. returned by GetManagedObjects, without querying Bluez
+func NewGattService1FromProperties(conn *bluez.Conn, objectPath dbus.ObjectPath, props map[string]dbus.Variant) (*GattService1, error) {
+	properties, err := new(GattService1Properties).FromDBusMap(props)
+	if err != nil {
+		return nil, err
+	}
+	a := new(GattService1)
+	a.client = newGattClient(conn, GattService1Interface, objectPath)
+	a.Properties = properties
+	return a, nil
+}
+
+// NewGattCharacteristic1FromProperties create a GattCharacteristic1 from
+// properties already known, without querying Bluez
+func NewGattCharacteristic1FromProperties(conn *bluez.Conn, objectPath dbus.ObjectPath, props map[string]dbus.Variant) (*GattCharacteristic1, error) {
+	properties, err := new(GattCharacteristic1Properties).FromDBusMap(props)
+	if err != nil {
+		return nil, err
+	}
+	a := new(GattCharacteristic1)
+	a.client = newGattClient(conn, GattCharacteristic1Interface, objectPath)
+	a.Properties = properties
+	return a, nil
+}
+
+// NewGattDescriptor1FromProperties create a GattDescriptor1 from properties
+// already known, without querying Bluez
+func NewGattDescriptor1FromProperties(conn *bluez.Conn, objectPath dbus.ObjectPath, props map[string]dbus.Variant) (*GattDescriptor1, error) {
+	properties, err := new(GattDescriptor1Properties).FromDBusMap(props)
+	if err != nil {
+		return nil, err
+	}
+	a := new(GattDescriptor1)
+	a.client = newGattClient(conn, GattDescriptor1Interface, objectPath)
+	a.Properties = properties
+	return a, nil
+}
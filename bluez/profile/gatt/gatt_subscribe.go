@@ -0,0 +1,243 @@
+package gatt
+
+import (
+	"context"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+)
+
+// NotifyBufferSize is the number of notifications queued for each subscriber
+var NotifyBufferSize = 100
+
+type notifyKey struct {
+	conn *bluez.Conn
+	path dbus.ObjectPath
+}
+
+var notifySessions = map[notifyKey]*notifySession{}
+var notifyLocks = map[notifyKey]*notifyLock{}
+
+// notifySessionsLock guard the maps, it is not held during D-Bus calls
+var notifySessionsLock sync.Mutex
+
+// notifyLock serialize the start and stop of the session of a characteristic
+type notifyLock struct {
+	sync.Mutex
+	refs int
+}
+
+func lockNotify(key notifyKey) *notifyLock {
+
+	notifySessionsLock.Lock()
+	l, ok := notifyLocks[key]
+	if !ok {
+		l = new(notifyLock)
+		notifyLocks[key] = l
+	}
+	l.refs++
+	notifySessionsLock.Unlock()
+
+	l.Lock()
+	return l
+}
+
+func unlockNotify(key notifyKey, l *notifyLock) {
+
+	l.Unlock()
+
+	notifySessionsLock.Lock()
+	l.refs--
+	if l.refs == 0 {
+		delete(notifyLocks, key)
+	}
+	notifySessionsLock.Unlock()
+}
+
+// notifySession share a single StartNotify between the subscribers of a characteristic
+type notifySession struct {
+	key    notifyKey
+	char   *GattCharacteristic1
+	signal chan *dbus.Signal
+	quit   chan struct{}
+
+	lock        sync.Mutex
+	subscribers map[*notifySubscriber]bool
+}
+
+type notifySubscriber struct {
+	ch   chan []byte
+	done chan struct{}
+}
+
+// Subscribe enable notifications and return a channel receiving the
+// characteristic values in order. Subscribers of the same characteristic
+// share a notification session, StopNotify is called when the last one
+// leaves. The channel is closed when ctx is done.
+// No value is dropped, a subscriber not consuming its channel delays the
+// other subscribers and the signals of the connection
+func (a *GattCharacteristic1) Subscribe(ctx context.Context) (chan []byte, error) {
+
+	key := notifyKey{a.client.Conn(), a.Path()}
+	l := lockNotify(key)
+	defer unlockNotify(key, l)
+
+	notifySessionsLock.Lock()
+	session, ok := notifySessions[key]
+	notifySessionsLock.Unlock()
+
+	if !ok {
+		var err error
+		session, err = startNotifySession(ctx, key, a)
+		if err != nil {
+			return nil, err
+		}
+		notifySessionsLock.Lock()
+		notifySessions[key] = session
+		notifySessionsLock.Unlock()
+	}
+
+	sub := &notifySubscriber{
+		ch:   make(chan []byte, NotifyBufferSize),
+		done: make(chan struct{}),
+	}
+
+	session.lock.Lock()
+	session.subscribers[sub] = true
+	session.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		session.unsubscribe(sub)
+	}()
+
+	return sub.ch, nil
+}
+
+// notifySignalOptions is the subscription of a notification session, the
+// Value signals are never merged nor dropped
+func notifySignalOptions() bluez.SubscribeOptions {
+	return bluez.SubscribeOptions{
+		BufferSize: NotifyBufferSize,
+		Overflow:   bluez.OverflowBlock,
+	}
+}
+
+func startNotifySession(ctx context.Context, key notifyKey, char *GattCharacteristic1) (*notifySession, error) {
+
+	signal, err := char.client.RegisterWithOptions(notifySignalOptions(), bluez.SignalFilter{
+		Path:      key.path,
+		Interface: bluez.PropertiesInterface,
+		Member:    "PropertiesChanged",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = char.StartNotifyContext(ctx)
+	if err != nil {
+		char.client.Unregister(key.path, bluez.PropertiesInterface, signal)
+		return nil, err
+	}
+
+	session := &notifySession{
+		key:         key,
+		char:        char,
+		signal:      signal,
+		quit:        make(chan struct{}),
+		subscribers: make(map[*notifySubscriber]bool),
+	}
+
+	go session.dispatch()
+
+	return session, nil
+}
+
+func (s *notifySession) dispatch() {
+	for {
+		select {
+		case sig := <-s.signal:
+			value, ok := notificationValue(sig)
+			if !ok {
+				continue
+			}
+			s.deliver(value)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *notifySession) deliver(value []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for sub := range s.subscribers {
+		select {
+		case sub.ch <- value:
+		case <-sub.done:
+		}
+	}
+}
+
+// notificationValue extract the Value of a GattCharacteristic1 PropertiesChanged signal
+func notificationValue(sig *dbus.Signal) ([]byte, bool) {
+
+	if sig == nil || len(sig.Body) < 2 {
+		return nil, false
+	}
+
+	iface, ok := sig.Body[0].(string)
+	if !ok || iface != GattCharacteristic1Interface {
+		return nil, false
+	}
+
+	changed, ok := sig.Body[1].(map[string]dbus.Variant)
+	if !ok {
+		return nil, false
+	}
+
+	variant, ok := changed["Value"]
+	if !ok {
+		return nil, false
+	}
+
+	value, ok := variant.Value().([]byte)
+	return value, ok
+}
+
+// unsubscribe remove a subscriber and stop notifications when none are left
+func (s *notifySession) unsubscribe(sub *notifySubscriber) {
+
+	// unblock a pending delivery before taking the session lock
+	close(sub.done)
+
+	l := lockNotify(s.key)
+	defer unlockNotify(s.key, l)
+
+	s.lock.Lock()
+	delete(s.subscribers, sub)
+	close(sub.ch)
+	left := len(s.subscribers)
+	s.lock.Unlock()
+
+	if left > 0 {
+		return
+	}
+
+	notifySessionsLock.Lock()
+	current := notifySessions[s.key] == s
+	if current {
+		delete(notifySessions, s.key)
+	}
+	notifySessionsLock.Unlock()
+
+	if !current {
+		return
+	}
+
+	close(s.quit)
+
+	s.char.client.Unregister(s.key.path, bluez.PropertiesInterface, s.signal)
+	s.char.StopNotify()
+}
@@ -0,0 +1,134 @@
+package gatt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/bluez"
+	"github.com/stretchr/testify/assert"
+)
+
+func notifySignal(iface string, props map[string]dbus.Variant) *dbus.Signal {
+	return &dbus.Signal{
+		Path: "/org/bluez/hci0/dev_00_11_22_33_44_55/service000a/char000b",
+		Name: bluez.PropertiesInterface + ".PropertiesChanged",
+		Body: []interface{}{iface, props, []string{}},
+	}
+}
+
+func TestNotificationValue(t *testing.T) {
+
+	value, ok := notificationValue(notifySignal(GattCharacteristic1Interface, map[string]dbus.Variant{
+		"Value": dbus.MakeVariant([]byte{1, 2}),
+	}))
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1, 2}, value)
+
+	_, ok = notificationValue(notifySignal(GattCharacteristic1Interface, map[string]dbus.Variant{
+		"Notifying": dbus.MakeVariant(true),
+	}))
+	assert.False(t, ok)
+
+	_, ok = notificationValue(notifySignal(GattDescriptor1Interface, map[string]dbus.Variant{
+		"Value": dbus.MakeVariant([]byte{1, 2}),
+	}))
+	assert.False(t, ok)
+
+	_, ok = notificationValue(nil)
+	assert.False(t, ok)
+}
+
+func TestNotifySessionDeliver(t *testing.T) {
+
+	session := &notifySession{
+		subscribers: make(map[*notifySubscriber]bool),
+	}
+
+	sub1 := &notifySubscriber{ch: make(chan []byte, 10), done: make(chan struct{})}
+	sub2 := &notifySubscriber{ch: make(chan []byte, 10), done: make(chan struct{})}
+	session.subscribers[sub1] = true
+	session.subscribers[sub2] = true
+
+	session.deliver([]byte{1})
+	session.deliver([]byte{2})
+
+	for _, sub := range []*notifySubscriber{sub1, sub2} {
+		assert.Equal(t, []byte{1}, <-sub.ch)
+		assert.Equal(t, []byte{2}, <-sub.ch)
+	}
+
+	// a full subscriber which left does not block the others
+	full := &notifySubscriber{ch: make(chan []byte), done: make(chan struct{})}
+	session.subscribers[full] = true
+	close(full.done)
+
+	session.deliver([]byte{3})
+	assert.Equal(t, []byte{3}, <-sub1.ch)
+	assert.Equal(t, []byte{3}, <-sub2.ch)
+}
+
+func TestNotifySessionBurst(t *testing.T) {
+
+	assert.Equal(t, bluez.OverflowBlock, notifySignalOptions().Overflow)
+
+	session := &notifySession{
+		signal:      make(chan *dbus.Signal),
+		quit:        make(chan struct{}),
+		subscribers: make(map[*notifySubscriber]bool),
+	}
+	defer close(session.quit)
+
+	sub := &notifySubscriber{ch: make(chan []byte, 2), done: make(chan struct{})}
+	session.subscribers[sub] = true
+	go session.dispatch()
+
+	count := NotifyBufferSize * 3
+	go func() {
+		for i := 0; i < count; i++ {
+			session.signal <- notifySignal(GattCharacteristic1Interface, map[string]dbus.Variant{
+				"Value": dbus.MakeVariant([]byte{byte(i)}),
+			})
+		}
+	}()
+
+	// a slow subscriber receives every value, in order
+	for i := 0; i < count; i++ {
+		if i%50 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		assert.Equal(t, []byte{byte(i)}, <-sub.ch)
+	}
+}
+
+func TestNotifyLock(t *testing.T) {
+
+	key1 := notifyKey{path: "/org/bluez/hci0/dev_00_11_22_33_44_55/service0001/char0002"}
+	key2 := notifyKey{path: "/org/bluez/hci0/dev_00_11_22_33_44_55/service0001/char0004"}
+
+	l1 := lockNotify(key1)
+
+	// another characteristic is not blocked
+	l2 := lockNotify(key2)
+	unlockNotify(key2, l2)
+
+	locked := make(chan bool)
+	go func() {
+		l := lockNotify(key1)
+		unlockNotify(key1, l)
+		locked <- true
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("same characteristic not serialized")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	unlockNotify(key1, l1)
+	<-locked
+
+	notifySessionsLock.Lock()
+	assert.Empty(t, notifyLocks)
+	notifySessionsLock.Unlock()
+}
@@ -3,6 +3,7 @@ package gatt
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var GattCharacteristic1Interface = "org.bluez.GattCharacteristic1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX/serviceXX/charYYYY
 func NewGattCharacteristic1(objectPath dbus.ObjectPath) (*GattCharacteristic1, error) {
+	return NewGattCharacteristic1WithConn(nil, objectPath)
+}
+
+// NewGattCharacteristic1WithConn create a new instance of GattCharacteristic1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewGattCharacteristic1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*GattCharacteristic1, error) {
 	a := new(GattCharacteristic1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewGattCharacteristic1(objectPath dbus.ObjectPath) (*GattCharacteristic1, e
 			Iface: GattCharacteristic1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -331,7 +339,7 @@ func (a *GattCharacteristic1) GetObjectManagerSignal() (chan *dbus.Signal, func(
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -420,6 +428,7 @@ func (a *GattCharacteristic1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *GattCharacteristic1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -455,10 +464,15 @@ ReadValue
 
 */
 func (a *GattCharacteristic1) ReadValue(options map[string]interface{}) ([]byte, error) {
+	return a.ReadValueContext(context.Background(), options)
+}
+
+// ReadValueContext call ReadValue, the call is aborted when ctx is done
+func (a *GattCharacteristic1) ReadValueContext(ctx context.Context, options map[string]interface{}) ([]byte, error) {
 	
 	var val0 []byte
-	err := a.client.Call("ReadValue", 0, options).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "ReadValue", 0, options).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -483,8 +497,13 @@ WriteValue
 
 */
 func (a *GattCharacteristic1) WriteValue(value []byte, options map[string]interface{}) error {
+	return a.WriteValueContext(context.Background(), value, options)
+}
+
+// WriteValueContext call WriteValue, the call is aborted when ctx is done
+func (a *GattCharacteristic1) WriteValueContext(ctx context.Context, value []byte, options map[string]interface{}) error {
 	
-	return a.client.Call("WriteValue", 0, value, options).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "WriteValue", 0, value, options).Store())
 	
 }
 
@@ -521,11 +540,16 @@ AcquireWrite
 
 */
 func (a *GattCharacteristic1) AcquireWrite(options map[string]interface{}) (dbus.UnixFD, uint16, error) {
+	return a.AcquireWriteContext(context.Background(), options)
+}
+
+// AcquireWriteContext call AcquireWrite, the call is aborted when ctx is done
+func (a *GattCharacteristic1) AcquireWriteContext(ctx context.Context, options map[string]interface{}) (dbus.UnixFD, uint16, error) {
 	
 	var val0 dbus.UnixFD
   var val1 uint16
-	err := a.client.Call("AcquireWrite", 0, options).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "AcquireWrite", 0, options).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
 /*
@@ -567,11 +591,16 @@ AcquireNotify
 
 */
 func (a *GattCharacteristic1) AcquireNotify(options map[string]interface{}) (dbus.UnixFD, uint16, error) {
+	return a.AcquireNotifyContext(context.Background(), options)
+}
+
+// AcquireNotifyContext call AcquireNotify, the call is aborted when ctx is done
+func (a *GattCharacteristic1) AcquireNotifyContext(ctx context.Context, options map[string]interface{}) (dbus.UnixFD, uint16, error) {
 	
 	var val0 dbus.UnixFD
   var val1 uint16
-	err := a.client.Call("AcquireNotify", 0, options).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "AcquireNotify", 0, options).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
 /*
@@ -587,8 +616,13 @@ StartNotify
 
 */
 func (a *GattCharacteristic1) StartNotify() error {
+	return a.StartNotifyContext(context.Background())
+}
+
+// StartNotifyContext call StartNotify, the call is aborted when ctx is done
+func (a *GattCharacteristic1) StartNotifyContext(ctx context.Context) error {
 	
-	return a.client.Call("StartNotify", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "StartNotify", 0, ).Store())
 	
 }
 
@@ -604,8 +638,13 @@ StopNotify
 
 */
 func (a *GattCharacteristic1) StopNotify() error {
+	return a.StopNotifyContext(context.Background())
+}
+
+// StopNotifyContext call StopNotify, the call is aborted when ctx is done
+func (a *GattCharacteristic1) StopNotifyContext(ctx context.Context) error {
 	
-	return a.client.Call("StopNotify", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "StopNotify", 0, ).Store())
 	
 }
 
@@ -619,8 +658,13 @@ Confirm
 
 */
 func (a *GattCharacteristic1) Confirm() error {
+	return a.ConfirmContext(context.Background())
+}
+
+// ConfirmContext call Confirm, the call is aborted when ctx is done
+func (a *GattCharacteristic1) ConfirmContext(ctx context.Context) error {
 	
-	return a.client.Call("Confirm", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Confirm", 0, ).Store())
 	
 }
 
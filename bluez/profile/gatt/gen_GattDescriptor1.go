@@ -3,6 +3,7 @@ package gatt
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var GattDescriptor1Interface = "org.bluez.GattDescriptor1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX/serviceXX/charYYYY/descriptorZZZ
 func NewGattDescriptor1(objectPath dbus.ObjectPath) (*GattDescriptor1, error) {
+	return NewGattDescriptor1WithConn(nil, objectPath)
+}
+
+// NewGattDescriptor1WithConn create a new instance of GattDescriptor1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewGattDescriptor1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*GattDescriptor1, error) {
 	a := new(GattDescriptor1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewGattDescriptor1(objectPath dbus.ObjectPath) (*GattDescriptor1, error) {
 			Iface: GattDescriptor1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -210,7 +218,7 @@ func (a *GattDescriptor1) GetObjectManagerSignal() (chan *dbus.Signal, func(), e
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -299,6 +307,7 @@ func (a *GattDescriptor1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *GattDescriptor1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -334,10 +343,15 @@ ReadValue
 
 */
 func (a *GattDescriptor1) ReadValue(flags map[string]interface{}) ([]byte, error) {
+	return a.ReadValueContext(context.Background(), flags)
+}
+
+// ReadValueContext call ReadValue, the call is aborted when ctx is done
+func (a *GattDescriptor1) ReadValueContext(ctx context.Context, flags map[string]interface{}) ([]byte, error) {
 	
 	var val0 []byte
-	err := a.client.Call("ReadValue", 0, flags).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "ReadValue", 0, flags).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -362,8 +376,13 @@ WriteValue
 
 */
 func (a *GattDescriptor1) WriteValue(value []byte, flags map[string]interface{}) error {
+	return a.WriteValueContext(context.Background(), value, flags)
+}
+
+// WriteValueContext call WriteValue, the call is aborted when ctx is done
+func (a *GattDescriptor1) WriteValueContext(ctx context.Context, value []byte, flags map[string]interface{}) error {
 	
-	return a.client.Call("WriteValue", 0, value, flags).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "WriteValue", 0, value, flags).Store())
 	
 }
 
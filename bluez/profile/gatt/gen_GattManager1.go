@@ -3,6 +3,7 @@ package gatt
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -19,6 +20,12 @@ var GattManager1Interface = "org.bluez.GattManager1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}
 func NewGattManager1(objectPath dbus.ObjectPath) (*GattManager1, error) {
+	return NewGattManager1WithConn(nil, objectPath)
+}
+
+// NewGattManager1WithConn create a new instance of GattManager1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewGattManager1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*GattManager1, error) {
 	a := new(GattManager1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -26,6 +33,7 @@ func NewGattManager1(objectPath dbus.ObjectPath) (*GattManager1, error) {
 			Iface: GattManager1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -42,6 +50,12 @@ func NewGattManager1(objectPath dbus.ObjectPath) (*GattManager1, error) {
 // NewGattManager1FromAdapterID create a new instance of GattManager1
 // adapterID: ID of an adapter eg. hci0
 func NewGattManager1FromAdapterID(adapterID string) (*GattManager1, error) {
+	return NewGattManager1FromAdapterIDWithConn(nil, adapterID)
+}
+
+// NewGattManager1FromAdapterIDWithConn create a new instance of GattManager1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewGattManager1FromAdapterIDWithConn(conn *bluez.Conn, adapterID string) (*GattManager1, error) {
 	a := new(GattManager1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -49,6 +63,7 @@ func NewGattManager1FromAdapterID(adapterID string) (*GattManager1, error) {
 			Iface: GattManager1Interface,
 			Path:  dbus.ObjectPath(fmt.Sprintf("/org/bluez/%s", adapterID)),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -187,7 +202,7 @@ func (a *GattManager1) GetObjectManagerSignal() (chan *dbus.Signal, func(), erro
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -276,6 +291,7 @@ func (a *GattManager1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *GattManager1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -308,8 +324,13 @@ RegisterApplication
 
 */
 func (a *GattManager1) RegisterApplication(application dbus.ObjectPath, options map[string]interface{}) error {
+	return a.RegisterApplicationContext(context.Background(), application, options)
+}
+
+// RegisterApplicationContext call RegisterApplication, the call is aborted when ctx is done
+func (a *GattManager1) RegisterApplicationContext(ctx context.Context, application dbus.ObjectPath, options map[string]interface{}) error {
 	
-	return a.client.Call("RegisterApplication", 0, application, options).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RegisterApplication", 0, application, options).Store())
 	
 }
 
@@ -325,8 +346,13 @@ UnregisterApplication
 
 */
 func (a *GattManager1) UnregisterApplication(application dbus.ObjectPath) error {
+	return a.UnregisterApplicationContext(context.Background(), application)
+}
+
+// UnregisterApplicationContext call UnregisterApplication, the call is aborted when ctx is done
+func (a *GattManager1) UnregisterApplicationContext(ctx context.Context, application dbus.ObjectPath) error {
 	
-	return a.client.Call("UnregisterApplication", 0, application).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "UnregisterApplication", 0, application).Store())
 	
 }
 
@@ -3,6 +3,7 @@ package gatt
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -19,6 +20,12 @@ var GattProfile1Interface = "org.bluez.GattProfile1"
 // - servicePath: <application dependent>
 // - objectPath: <application dependent>
 func NewGattProfile1(servicePath string, objectPath dbus.ObjectPath) (*GattProfile1, error) {
+	return NewGattProfile1WithConn(nil, servicePath, objectPath)
+}
+
+// NewGattProfile1WithConn create a new instance of GattProfile1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewGattProfile1WithConn(conn *bluez.Conn, servicePath string, objectPath dbus.ObjectPath) (*GattProfile1, error) {
 	a := new(GattProfile1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -26,6 +33,7 @@ func NewGattProfile1(servicePath string, objectPath dbus.ObjectPath) (*GattProfi
 			Iface: GattProfile1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -127,7 +135,7 @@ func (a *GattProfile1) GetObjectManagerSignal() (chan *dbus.Signal, func(), erro
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -216,6 +224,7 @@ func (a *GattProfile1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *GattProfile1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -243,8 +252,13 @@ Release
 
 */
 func (a *GattProfile1) Release() error {
+	return a.ReleaseContext(context.Background())
+}
+
+// ReleaseContext call Release, the call is aborted when ctx is done
+func (a *GattProfile1) ReleaseContext(ctx context.Context) error {
 	
-	return a.client.Call("Release", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Release", 0, ).Store())
 	
 }
 
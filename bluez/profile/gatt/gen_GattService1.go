@@ -18,6 +18,12 @@ var GattService1Interface = "org.bluez.GattService1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX/serviceXX
 func NewGattService1(objectPath dbus.ObjectPath) (*GattService1, error) {
+	return NewGattService1WithConn(nil, objectPath)
+}
+
+// NewGattService1WithConn create a new instance of GattService1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewGattService1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*GattService1, error) {
 	a := new(GattService1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +31,7 @@ func NewGattService1(objectPath dbus.ObjectPath) (*GattService1, error) {
 			Iface: GattService1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -78,7 +85,7 @@ type GattService1Properties struct {
 			belongs to. Only present on services from remote
 			devices.
 	*/
-	Device []dbus.ObjectPath `dbus:"ignore=IsService"`
+	Device dbus.ObjectPath `dbus:"ignore=IsService"`
 
 	/*
 	Includes Array of object paths representing the included
@@ -252,7 +259,7 @@ func (a *GattService1) GetObjectManagerSignal() (chan *dbus.Signal, func(), erro
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -341,6 +348,7 @@ func (a *GattService1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *GattService1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
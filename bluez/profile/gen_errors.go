@@ -13,22 +13,40 @@ var (
 		Body: []interface{}{"NotReady"},
 	}
 
+	// Failed map to org.bluez.Error.Failed
+	ErrFailed = dbus.Error{
+		Name: "org.bluez.Error.Failed",
+		Body: []interface{}{"Failed"},
+	}
+
+	// NotAuthorized map to org.bluez.Error.NotAuthorized
+	ErrNotAuthorized = dbus.Error{
+		Name: "org.bluez.Error.NotAuthorized",
+		Body: []interface{}{"NotAuthorized"},
+	}
+
 	// InvalidArguments map to org.bluez.Error.InvalidArguments
 	ErrInvalidArguments = dbus.Error{
 		Name: "org.bluez.Error.InvalidArguments",
 		Body: []interface{}{"InvalidArguments"},
 	}
 
-	// Failed map to org.bluez.Error.Failed
-	ErrFailed = dbus.Error{
-		Name: "org.bluez.Error.Failed",
-		Body: []interface{}{"Failed"},
+	// NotSupported map to org.bluez.Error.NotSupported
+	ErrNotSupported = dbus.Error{
+		Name: "org.bluez.Error.NotSupported",
+		Body: []interface{}{"NotSupported"},
 	}
 
-	// NotPermitted map to org.bluez.Error.NotPermitted
-	ErrNotPermitted = dbus.Error{
-		Name: "org.bluez.Error.NotPermitted",
-		Body: []interface{}{"NotPermitted"},
+	// AlreadyExists map to org.bluez.Error.AlreadyExists
+	ErrAlreadyExists = dbus.Error{
+		Name: "org.bluez.Error.AlreadyExists",
+		Body: []interface{}{"AlreadyExists"},
+	}
+
+	// InvalidLength map to org.bluez.Error.InvalidLength
+	ErrInvalidLength = dbus.Error{
+		Name: "org.bluez.Error.InvalidLength",
+		Body: []interface{}{"InvalidLength"},
 	}
 
 	// DoesNotExist map to org.bluez.Error.DoesNotExist
@@ -37,34 +55,40 @@ var (
 		Body: []interface{}{"DoesNotExist"},
 	}
 
+	// NotPermitted map to org.bluez.Error.NotPermitted
+	ErrNotPermitted = dbus.Error{
+		Name: "org.bluez.Error.NotPermitted",
+		Body: []interface{}{"NotPermitted"},
+	}
+
 	// Rejected map to org.bluez.Error.Rejected
 	ErrRejected = dbus.Error{
 		Name: "org.bluez.Error.Rejected",
 		Body: []interface{}{"Rejected"},
 	}
 
-	// NotConnected map to org.bluez.Error.NotConnected
-	ErrNotConnected = dbus.Error{
-		Name: "org.bluez.Error.NotConnected",
-		Body: []interface{}{"NotConnected"},
+	// Canceled map to org.bluez.Error.Canceled
+	ErrCanceled = dbus.Error{
+		Name: "org.bluez.Error.Canceled",
+		Body: []interface{}{"Canceled"},
 	}
 
-	// NotAcquired map to org.bluez.Error.NotAcquired
-	ErrNotAcquired = dbus.Error{
-		Name: "org.bluez.Error.NotAcquired",
-		Body: []interface{}{"NotAcquired"},
+	// InProgress map to org.bluez.Error.InProgress
+	ErrInProgress = dbus.Error{
+		Name: "org.bluez.Error.InProgress",
+		Body: []interface{}{"InProgress"},
 	}
 
-	// NotSupported map to org.bluez.Error.NotSupported
-	ErrNotSupported = dbus.Error{
-		Name: "org.bluez.Error.NotSupported",
-		Body: []interface{}{"NotSupported"},
+	// AlreadyConnected map to org.bluez.Error.AlreadyConnected
+	ErrAlreadyConnected = dbus.Error{
+		Name: "org.bluez.Error.AlreadyConnected",
+		Body: []interface{}{"AlreadyConnected"},
 	}
 
-	// NotAuthorized map to org.bluez.Error.NotAuthorized
-	ErrNotAuthorized = dbus.Error{
-		Name: "org.bluez.Error.NotAuthorized",
-		Body: []interface{}{"NotAuthorized"},
+	// NotConnected map to org.bluez.Error.NotConnected
+	ErrNotConnected = dbus.Error{
+		Name: "org.bluez.Error.NotConnected",
+		Body: []interface{}{"NotConnected"},
 	}
 
 	// NotAvailable map to org.bluez.Error.NotAvailable
@@ -73,4 +97,46 @@ var (
 		Body: []interface{}{"NotAvailable"},
 	}
 
+	// InvalidOffset map to org.bluez.Error.InvalidOffset
+	ErrInvalidOffset = dbus.Error{
+		Name: "org.bluez.Error.InvalidOffset",
+		Body: []interface{}{"InvalidOffset"},
+	}
+
+	// InvalidValueLength map to org.bluez.Error.InvalidValueLength
+	ErrInvalidValueLength = dbus.Error{
+		Name: "org.bluez.Error.InvalidValueLength",
+		Body: []interface{}{"InvalidValueLength"},
+	}
+
+	// NotFound map to org.bluez.Error.NotFound
+	ErrNotFound = dbus.Error{
+		Name: "org.bluez.Error.NotFound",
+		Body: []interface{}{"NotFound"},
+	}
+
+	// NotAllowed map to org.bluez.Error.NotAllowed
+	ErrNotAllowed = dbus.Error{
+		Name: "org.bluez.Error.NotAllowed",
+		Body: []interface{}{"NotAllowed"},
+	}
+
+	// OutOfRange map to org.bluez.Error.OutOfRange
+	ErrOutOfRange = dbus.Error{
+		Name: "org.bluez.Error.OutOfRange",
+		Body: []interface{}{"OutOfRange"},
+	}
+
+	// HealthError map to org.bluez.Error.HealthError
+	ErrHealthError = dbus.Error{
+		Name: "org.bluez.Error.HealthError",
+		Body: []interface{}{"HealthError"},
+	}
+
+	// NotAcquired map to org.bluez.Error.NotAcquired
+	ErrNotAcquired = dbus.Error{
+		Name: "org.bluez.Error.NotAcquired",
+		Body: []interface{}{"NotAcquired"},
+	}
+
 )
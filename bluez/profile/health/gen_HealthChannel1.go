@@ -3,6 +3,7 @@ package health
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var HealthChannel1Interface = "org.bluez.HealthChannel1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX/chanZZZ
 func NewHealthChannel1(objectPath dbus.ObjectPath) (*HealthChannel1, error) {
+	return NewHealthChannel1WithConn(nil, objectPath)
+}
+
+// NewHealthChannel1WithConn create a new instance of HealthChannel1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewHealthChannel1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*HealthChannel1, error) {
 	a := new(HealthChannel1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewHealthChannel1(objectPath dbus.ObjectPath) (*HealthChannel1, error) {
 			Iface: HealthChannel1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -158,7 +166,7 @@ func (a *HealthChannel1) GetObjectManagerSignal() (chan *dbus.Signal, func(), er
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -247,6 +255,7 @@ func (a *HealthChannel1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *HealthChannel1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -275,10 +284,15 @@ Acquire
 
 */
 func (a *HealthChannel1) Acquire() (dbus.UnixFD, error) {
+	return a.AcquireContext(context.Background())
+}
+
+// AcquireContext call Acquire, the call is aborted when ctx is done
+func (a *HealthChannel1) AcquireContext(ctx context.Context) (dbus.UnixFD, error) {
 	
 	var val0 dbus.UnixFD
-	err := a.client.Call("Acquire", 0, ).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "Acquire", 0, ).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -286,8 +300,13 @@ Release
 
 */
 func (a *HealthChannel1) Release() error {
+	return a.ReleaseContext(context.Background())
+}
+
+// ReleaseContext call Release, the call is aborted when ctx is done
+func (a *HealthChannel1) ReleaseContext(ctx context.Context) error {
 	
-	return a.client.Call("Release", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Release", 0, ).Store())
 	
 }
 
@@ -299,8 +318,13 @@ close
 
 */
 func (a *HealthChannel1) close() error {
+	return a.closeContext(context.Background())
+}
+
+// closeContext call close, the call is aborted when ctx is done
+func (a *HealthChannel1) closeContext(ctx context.Context) error {
 	
-	return a.client.Call("close", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "close", 0, ).Store())
 	
 }
 
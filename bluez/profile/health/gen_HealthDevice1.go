@@ -3,6 +3,7 @@ package health
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var HealthDevice1Interface = "org.bluez.HealthDevice1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX
 func NewHealthDevice1(objectPath dbus.ObjectPath) (*HealthDevice1, error) {
+	return NewHealthDevice1WithConn(nil, objectPath)
+}
+
+// NewHealthDevice1WithConn create a new instance of HealthDevice1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewHealthDevice1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*HealthDevice1, error) {
 	a := new(HealthDevice1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewHealthDevice1(objectPath dbus.ObjectPath) (*HealthDevice1, error) {
 			Iface: HealthDevice1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -119,7 +127,7 @@ func (a *HealthDevice1) GetObjectManagerSignal() (chan *dbus.Signal, func(), err
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -208,6 +216,7 @@ func (a *HealthDevice1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *HealthDevice1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -236,10 +245,15 @@ Echo
 
 */
 func (a *HealthDevice1) Echo() (bool, error) {
+	return a.EchoContext(context.Background())
+}
+
+// EchoContext call Echo, the call is aborted when ctx is done
+func (a *HealthDevice1) EchoContext(ctx context.Context) (bool, error) {
 	
 	var val0 bool
-	err := a.client.Call("Echo", 0, ).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "Echo", 0, ).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -257,10 +271,15 @@ CreateChannel
 
 */
 func (a *HealthDevice1) CreateChannel(application dbus.ObjectPath, configuration string) (dbus.ObjectPath, error) {
+	return a.CreateChannelContext(context.Background(), application, configuration)
+}
+
+// CreateChannelContext call CreateChannel, the call is aborted when ctx is done
+func (a *HealthDevice1) CreateChannelContext(ctx context.Context, application dbus.ObjectPath, configuration string) (dbus.ObjectPath, error) {
 	
 	var val0 dbus.ObjectPath
-	err := a.client.Call("CreateChannel", 0, application, configuration).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "CreateChannel", 0, application, configuration).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -277,8 +296,13 @@ DestroyChannel
 
 */
 func (a *HealthDevice1) DestroyChannel(channel dbus.ObjectPath) error {
+	return a.DestroyChannelContext(context.Background(), channel)
+}
+
+// DestroyChannelContext call DestroyChannel, the call is aborted when ctx is done
+func (a *HealthDevice1) DestroyChannelContext(ctx context.Context, channel dbus.ObjectPath) error {
 	
-	return a.client.Call("DestroyChannel", 0, channel).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "DestroyChannel", 0, channel).Store())
 	
 }
 
@@ -3,6 +3,7 @@ package health
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var HealthManager1Interface = "org.bluez.HealthManager1"
 // Args:
 
 func NewHealthManager1() (*HealthManager1, error) {
+	return NewHealthManager1WithConn(nil)
+}
+
+// NewHealthManager1WithConn create a new instance of HealthManager1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewHealthManager1WithConn(conn *bluez.Conn) (*HealthManager1, error) {
 	a := new(HealthManager1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewHealthManager1() (*HealthManager1, error) {
 			Iface: HealthManager1Interface,
 			Path:  dbus.ObjectPath("/org/bluez/"),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -97,7 +105,7 @@ func (a *HealthManager1) GetObjectManagerSignal() (chan *dbus.Signal, func(), er
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -186,6 +194,7 @@ func (a *HealthManager1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *HealthManager1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -232,10 +241,15 @@ CreateApplication
 
 */
 func (a *HealthManager1) CreateApplication(config map[string]interface{}) (dbus.ObjectPath, error) {
+	return a.CreateApplicationContext(context.Background(), config)
+}
+
+// CreateApplicationContext call CreateApplication, the call is aborted when ctx is done
+func (a *HealthManager1) CreateApplicationContext(ctx context.Context, config map[string]interface{}) (dbus.ObjectPath, error) {
 	
 	var val0 dbus.ObjectPath
-	err := a.client.Call("CreateApplication", 0, config).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "CreateApplication", 0, config).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -253,8 +267,13 @@ DestroyApplication
 
 */
 func (a *HealthManager1) DestroyApplication(application dbus.ObjectPath) error {
+	return a.DestroyApplicationContext(context.Background(), application)
+}
+
+// DestroyApplicationContext call DestroyApplication, the call is aborted when ctx is done
+func (a *HealthManager1) DestroyApplicationContext(ctx context.Context, application dbus.ObjectPath) error {
 	
-	return a.client.Call("DestroyApplication", 0, application).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "DestroyApplication", 0, application).Store())
 	
 }
 
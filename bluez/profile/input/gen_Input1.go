@@ -18,6 +18,12 @@ var Input1Interface = "org.bluez.Input1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX
 func NewInput1(objectPath dbus.ObjectPath) (*Input1, error) {
+	return NewInput1WithConn(nil, objectPath)
+}
+
+// NewInput1WithConn create a new instance of Input1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewInput1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*Input1, error) {
 	a := new(Input1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +31,7 @@ func NewInput1(objectPath dbus.ObjectPath) (*Input1, error) {
 			Iface: Input1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -135,7 +142,7 @@ func (a *Input1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error) {
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -224,6 +231,7 @@ func (a *Input1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *Input1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
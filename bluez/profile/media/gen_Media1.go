@@ -3,6 +3,7 @@ package media
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var Media1Interface = "org.bluez.Media1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}
 func NewMedia1(objectPath dbus.ObjectPath) (*Media1, error) {
+	return NewMedia1WithConn(nil, objectPath)
+}
+
+// NewMedia1WithConn create a new instance of Media1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMedia1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*Media1, error) {
 	a := new(Media1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewMedia1(objectPath dbus.ObjectPath) (*Media1, error) {
 			Iface: Media1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -97,7 +105,7 @@ func (a *Media1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error) {
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -186,6 +194,7 @@ func (a *Media1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *Media1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -237,8 +246,13 @@ RegisterEndpoint
 
 */
 func (a *Media1) RegisterEndpoint(endpoint dbus.ObjectPath, properties map[string]interface{}) error {
+	return a.RegisterEndpointContext(context.Background(), endpoint, properties)
+}
+
+// RegisterEndpointContext call RegisterEndpoint, the call is aborted when ctx is done
+func (a *Media1) RegisterEndpointContext(ctx context.Context, endpoint dbus.ObjectPath, properties map[string]interface{}) error {
 	
-	return a.client.Call("RegisterEndpoint", 0, endpoint, properties).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RegisterEndpoint", 0, endpoint, properties).Store())
 	
 }
 
@@ -249,8 +263,13 @@ UnregisterEndpoint
 
 */
 func (a *Media1) UnregisterEndpoint(endpoint dbus.ObjectPath) error {
+	return a.UnregisterEndpointContext(context.Background(), endpoint)
+}
+
+// UnregisterEndpointContext call UnregisterEndpoint, the call is aborted when ctx is done
+func (a *Media1) UnregisterEndpointContext(ctx context.Context, endpoint dbus.ObjectPath) error {
 	
-	return a.client.Call("UnregisterEndpoint", 0, endpoint).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "UnregisterEndpoint", 0, endpoint).Store())
 	
 }
 
@@ -274,8 +293,13 @@ RegisterPlayer
 
 */
 func (a *Media1) RegisterPlayer(player dbus.ObjectPath, properties map[string]interface{}) error {
+	return a.RegisterPlayerContext(context.Background(), player, properties)
+}
+
+// RegisterPlayerContext call RegisterPlayer, the call is aborted when ctx is done
+func (a *Media1) RegisterPlayerContext(ctx context.Context, player dbus.ObjectPath, properties map[string]interface{}) error {
 	
-	return a.client.Call("RegisterPlayer", 0, player, properties).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RegisterPlayer", 0, player, properties).Store())
 	
 }
 
@@ -287,8 +311,13 @@ UnregisterPlayer
 
 */
 func (a *Media1) UnregisterPlayer(player dbus.ObjectPath) error {
+	return a.UnregisterPlayerContext(context.Background(), player)
+}
+
+// UnregisterPlayerContext call UnregisterPlayer, the call is aborted when ctx is done
+func (a *Media1) UnregisterPlayerContext(ctx context.Context, player dbus.ObjectPath) error {
 	
-	return a.client.Call("UnregisterPlayer", 0, player).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "UnregisterPlayer", 0, player).Store())
 	
 }
 
@@ -3,6 +3,7 @@ package media
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -19,6 +20,12 @@ var MediaControl1Interface = "org.bluez.MediaControl1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX
 func NewMediaControl1(objectPath dbus.ObjectPath) (*MediaControl1, error) {
+	return NewMediaControl1WithConn(nil, objectPath)
+}
+
+// NewMediaControl1WithConn create a new instance of MediaControl1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMediaControl1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*MediaControl1, error) {
 	a := new(MediaControl1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -26,6 +33,7 @@ func NewMediaControl1(objectPath dbus.ObjectPath) (*MediaControl1, error) {
 			Iface: MediaControl1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -42,6 +50,12 @@ func NewMediaControl1(objectPath dbus.ObjectPath) (*MediaControl1, error) {
 // NewMediaControl1FromAdapterID create a new instance of MediaControl1
 // adapterID: ID of an adapter eg. hci0
 func NewMediaControl1FromAdapterID(adapterID string) (*MediaControl1, error) {
+	return NewMediaControl1FromAdapterIDWithConn(nil, adapterID)
+}
+
+// NewMediaControl1FromAdapterIDWithConn create a new instance of MediaControl1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMediaControl1FromAdapterIDWithConn(conn *bluez.Conn, adapterID string) (*MediaControl1, error) {
 	a := new(MediaControl1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -49,6 +63,7 @@ func NewMediaControl1FromAdapterID(adapterID string) (*MediaControl1, error) {
 			Iface: MediaControl1Interface,
 			Path:  dbus.ObjectPath(fmt.Sprintf("/org/bluez/%s", adapterID)),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -159,7 +174,7 @@ func (a *MediaControl1) GetObjectManagerSignal() (chan *dbus.Signal, func(), err
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -248,6 +263,7 @@ func (a *MediaControl1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *MediaControl1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -271,8 +287,13 @@ Play
 
 */
 func (a *MediaControl1) Play() error {
+	return a.PlayContext(context.Background())
+}
+
+// PlayContext call Play, the call is aborted when ctx is done
+func (a *MediaControl1) PlayContext(ctx context.Context) error {
 	
-	return a.client.Call("Play", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Play", 0, ).Store())
 	
 }
 
@@ -283,8 +304,13 @@ Pause
 
 */
 func (a *MediaControl1) Pause() error {
+	return a.PauseContext(context.Background())
+}
+
+// PauseContext call Pause, the call is aborted when ctx is done
+func (a *MediaControl1) PauseContext(ctx context.Context) error {
 	
-	return a.client.Call("Pause", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Pause", 0, ).Store())
 	
 }
 
@@ -295,8 +321,13 @@ Stop
 
 */
 func (a *MediaControl1) Stop() error {
+	return a.StopContext(context.Background())
+}
+
+// StopContext call Stop, the call is aborted when ctx is done
+func (a *MediaControl1) StopContext(ctx context.Context) error {
 	
-	return a.client.Call("Stop", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Stop", 0, ).Store())
 	
 }
 
@@ -307,8 +338,13 @@ Next
 
 */
 func (a *MediaControl1) Next() error {
+	return a.NextContext(context.Background())
+}
+
+// NextContext call Next, the call is aborted when ctx is done
+func (a *MediaControl1) NextContext(ctx context.Context) error {
 	
-	return a.client.Call("Next", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Next", 0, ).Store())
 	
 }
 
@@ -319,8 +355,13 @@ Previous
 
 */
 func (a *MediaControl1) Previous() error {
+	return a.PreviousContext(context.Background())
+}
+
+// PreviousContext call Previous, the call is aborted when ctx is done
+func (a *MediaControl1) PreviousContext(ctx context.Context) error {
 	
-	return a.client.Call("Previous", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Previous", 0, ).Store())
 	
 }
 
@@ -331,8 +372,13 @@ VolumeUp
 
 */
 func (a *MediaControl1) VolumeUp() error {
+	return a.VolumeUpContext(context.Background())
+}
+
+// VolumeUpContext call VolumeUp, the call is aborted when ctx is done
+func (a *MediaControl1) VolumeUpContext(ctx context.Context) error {
 	
-	return a.client.Call("VolumeUp", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "VolumeUp", 0, ).Store())
 	
 }
 
@@ -343,8 +389,13 @@ VolumeDown
 
 */
 func (a *MediaControl1) VolumeDown() error {
+	return a.VolumeDownContext(context.Background())
+}
+
+// VolumeDownContext call VolumeDown, the call is aborted when ctx is done
+func (a *MediaControl1) VolumeDownContext(ctx context.Context) error {
 	
-	return a.client.Call("VolumeDown", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "VolumeDown", 0, ).Store())
 	
 }
 
@@ -356,8 +407,13 @@ FastForward
 
 */
 func (a *MediaControl1) FastForward() error {
+	return a.FastForwardContext(context.Background())
+}
+
+// FastForwardContext call FastForward, the call is aborted when ctx is done
+func (a *MediaControl1) FastForwardContext(ctx context.Context) error {
 	
-	return a.client.Call("FastForward", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "FastForward", 0, ).Store())
 	
 }
 
@@ -378,8 +434,13 @@ Properties
 
 */
 func (a *MediaControl1) Rewind() error {
+	return a.RewindContext(context.Background())
+}
+
+// RewindContext call Rewind, the call is aborted when ctx is done
+func (a *MediaControl1) RewindContext(ctx context.Context) error {
 	
-	return a.client.Call("Rewind", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Rewind", 0, ).Store())
 	
 }
 
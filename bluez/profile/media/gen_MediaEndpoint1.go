@@ -3,6 +3,7 @@ package media
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -19,6 +20,12 @@ var MediaEndpoint1Interface = "org.bluez.MediaEndpoint1"
 // - servicePath: unique name
 // - objectPath: freely definable
 func NewMediaEndpoint1(servicePath string, objectPath dbus.ObjectPath) (*MediaEndpoint1, error) {
+	return NewMediaEndpoint1WithConn(nil, servicePath, objectPath)
+}
+
+// NewMediaEndpoint1WithConn create a new instance of MediaEndpoint1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMediaEndpoint1WithConn(conn *bluez.Conn, servicePath string, objectPath dbus.ObjectPath) (*MediaEndpoint1, error) {
 	a := new(MediaEndpoint1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -26,6 +33,7 @@ func NewMediaEndpoint1(servicePath string, objectPath dbus.ObjectPath) (*MediaEn
 			Iface: MediaEndpoint1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -98,7 +106,7 @@ func (a *MediaEndpoint1) GetObjectManagerSignal() (chan *dbus.Signal, func(), er
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -187,6 +195,7 @@ func (a *MediaEndpoint1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *MediaEndpoint1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -210,8 +219,13 @@ SetConfiguration
 
 */
 func (a *MediaEndpoint1) SetConfiguration(transport dbus.ObjectPath, properties map[string]interface{}) error {
+	return a.SetConfigurationContext(context.Background(), transport, properties)
+}
+
+// SetConfigurationContext call SetConfiguration, the call is aborted when ctx is done
+func (a *MediaEndpoint1) SetConfigurationContext(ctx context.Context, transport dbus.ObjectPath, properties map[string]interface{}) error {
 	
-	return a.client.Call("SetConfiguration", 0, transport, properties).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "SetConfiguration", 0, transport, properties).Store())
 	
 }
 
@@ -230,10 +244,15 @@ SelectConfiguration
 
 */
 func (a *MediaEndpoint1) SelectConfiguration(capabilities []byte) ([]byte, error) {
+	return a.SelectConfigurationContext(context.Background(), capabilities)
+}
+
+// SelectConfigurationContext call SelectConfiguration, the call is aborted when ctx is done
+func (a *MediaEndpoint1) SelectConfigurationContext(ctx context.Context, capabilities []byte) ([]byte, error) {
 	
 	var val0 []byte
-	err := a.client.Call("SelectConfiguration", 0, capabilities).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "SelectConfiguration", 0, capabilities).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -243,8 +262,13 @@ ClearConfiguration
 
 */
 func (a *MediaEndpoint1) ClearConfiguration(transport dbus.ObjectPath) error {
+	return a.ClearConfigurationContext(context.Background(), transport)
+}
+
+// ClearConfigurationContext call ClearConfiguration, the call is aborted when ctx is done
+func (a *MediaEndpoint1) ClearConfigurationContext(ctx context.Context, transport dbus.ObjectPath) error {
 	
-	return a.client.Call("ClearConfiguration", 0, transport).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "ClearConfiguration", 0, transport).Store())
 	
 }
 
@@ -260,8 +284,13 @@ Release
 
 */
 func (a *MediaEndpoint1) Release() error {
+	return a.ReleaseContext(context.Background())
+}
+
+// ReleaseContext call Release, the call is aborted when ctx is done
+func (a *MediaEndpoint1) ReleaseContext(ctx context.Context) error {
 	
-	return a.client.Call("Release", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Release", 0, ).Store())
 	
 }
 
@@ -3,6 +3,7 @@ package media
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -19,6 +20,12 @@ var MediaFolder1Interface = "org.bluez.MediaFolder1"
 // - servicePath: unique name
 // - objectPath: freely definable
 func NewMediaFolder1(servicePath string, objectPath dbus.ObjectPath) (*MediaFolder1, error) {
+	return NewMediaFolder1WithConn(nil, servicePath, objectPath)
+}
+
+// NewMediaFolder1WithConn create a new instance of MediaFolder1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMediaFolder1WithConn(conn *bluez.Conn, servicePath string, objectPath dbus.ObjectPath) (*MediaFolder1, error) {
 	a := new(MediaFolder1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -26,6 +33,7 @@ func NewMediaFolder1(servicePath string, objectPath dbus.ObjectPath) (*MediaFold
 			Iface: MediaFolder1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -44,6 +52,12 @@ func NewMediaFolder1(servicePath string, objectPath dbus.ObjectPath) (*MediaFold
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX/playerX
 func NewMediaFolder1Controller(objectPath dbus.ObjectPath) (*MediaFolder1, error) {
+	return NewMediaFolder1ControllerWithConn(nil, objectPath)
+}
+
+// NewMediaFolder1ControllerWithConn create a new instance of MediaFolder1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMediaFolder1ControllerWithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*MediaFolder1, error) {
 	a := new(MediaFolder1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -51,6 +65,7 @@ func NewMediaFolder1Controller(objectPath dbus.ObjectPath) (*MediaFolder1, error
 			Iface: MediaFolder1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -255,7 +270,7 @@ func (a *MediaFolder1) GetObjectManagerSignal() (chan *dbus.Signal, func(), erro
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -344,6 +359,7 @@ func (a *MediaFolder1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *MediaFolder1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -373,10 +389,15 @@ Search
 
 */
 func (a *MediaFolder1) Search(value string, filter map[string]interface{}) (dbus.ObjectPath, error) {
+	return a.SearchContext(context.Background(), value, filter)
+}
+
+// SearchContext call Search, the call is aborted when ctx is done
+func (a *MediaFolder1) SearchContext(ctx context.Context, value string, filter map[string]interface{}) (dbus.ObjectPath, error) {
 	
 	var val0 dbus.ObjectPath
-	err := a.client.Call("Search", 0, value, filter).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "Search", 0, value, filter).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -390,11 +411,16 @@ ListItems
 
 */
 func (a *MediaFolder1) ListItems(filter map[string]interface{}) ([]dbus.ObjectPath, string, error) {
+	return a.ListItemsContext(context.Background(), filter)
+}
+
+// ListItemsContext call ListItems, the call is aborted when ctx is done
+func (a *MediaFolder1) ListItemsContext(ctx context.Context, filter map[string]interface{}) ([]dbus.ObjectPath, string, error) {
 	
 	var val0 []dbus.ObjectPath
   var val1 string
-	err := a.client.Call("ListItems", 0, filter).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "ListItems", 0, filter).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
 /*
@@ -413,8 +439,13 @@ ChangeFolder
 
 */
 func (a *MediaFolder1) ChangeFolder(folder dbus.ObjectPath) error {
+	return a.ChangeFolderContext(context.Background(), folder)
+}
+
+// ChangeFolderContext call ChangeFolder, the call is aborted when ctx is done
+func (a *MediaFolder1) ChangeFolderContext(ctx context.Context, folder dbus.ObjectPath) error {
 	
-	return a.client.Call("ChangeFolder", 0, folder).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "ChangeFolder", 0, folder).Store())
 	
 }
 
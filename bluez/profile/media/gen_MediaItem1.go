@@ -3,6 +3,7 @@ package media
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -19,6 +20,12 @@ var MediaItem1Interface = "org.bluez.MediaItem1"
 // - servicePath: unique name
 // - objectPath: freely definable
 func NewMediaItem1(servicePath string, objectPath dbus.ObjectPath) (*MediaItem1, error) {
+	return NewMediaItem1WithConn(nil, servicePath, objectPath)
+}
+
+// NewMediaItem1WithConn create a new instance of MediaItem1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMediaItem1WithConn(conn *bluez.Conn, servicePath string, objectPath dbus.ObjectPath) (*MediaItem1, error) {
 	a := new(MediaItem1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -26,6 +33,7 @@ func NewMediaItem1(servicePath string, objectPath dbus.ObjectPath) (*MediaItem1,
 			Iface: MediaItem1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -44,6 +52,12 @@ func NewMediaItem1(servicePath string, objectPath dbus.ObjectPath) (*MediaItem1,
 // Args:
 // - objectPath: [variable	prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX/playerX/itemX
 func NewMediaItem1Controller(objectPath dbus.ObjectPath) (*MediaItem1, error) {
+	return NewMediaItem1ControllerWithConn(nil, objectPath)
+}
+
+// NewMediaItem1ControllerWithConn create a new instance of MediaItem1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMediaItem1ControllerWithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*MediaItem1, error) {
 	a := new(MediaItem1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -51,6 +65,7 @@ func NewMediaItem1Controller(objectPath dbus.ObjectPath) (*MediaItem1, error) {
 			Iface: MediaItem1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -436,7 +451,7 @@ func (a *MediaItem1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error)
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -525,6 +540,7 @@ func (a *MediaItem1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *MediaItem1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -551,8 +567,13 @@ Play
 
 */
 func (a *MediaItem1) Play() error {
+	return a.PlayContext(context.Background())
+}
+
+// PlayContext call Play, the call is aborted when ctx is done
+func (a *MediaItem1) PlayContext(ctx context.Context) error {
 	
-	return a.client.Call("Play", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Play", 0, ).Store())
 	
 }
 
@@ -566,8 +587,13 @@ AddtoNowPlaying
 
 */
 func (a *MediaItem1) AddtoNowPlaying() error {
+	return a.AddtoNowPlayingContext(context.Background())
+}
+
+// AddtoNowPlayingContext call AddtoNowPlaying, the call is aborted when ctx is done
+func (a *MediaItem1) AddtoNowPlayingContext(ctx context.Context) error {
 	
-	return a.client.Call("AddtoNowPlaying", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "AddtoNowPlaying", 0, ).Store())
 	
 }
 
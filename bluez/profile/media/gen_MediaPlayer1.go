@@ -3,6 +3,7 @@ package media
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var MediaPlayer1Interface = "org.bluez.MediaPlayer1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX/playerX
 func NewMediaPlayer1(objectPath dbus.ObjectPath) (*MediaPlayer1, error) {
+	return NewMediaPlayer1WithConn(nil, objectPath)
+}
+
+// NewMediaPlayer1WithConn create a new instance of MediaPlayer1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMediaPlayer1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*MediaPlayer1, error) {
 	a := new(MediaPlayer1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewMediaPlayer1(objectPath dbus.ObjectPath) (*MediaPlayer1, error) {
 			Iface: MediaPlayer1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -600,7 +608,7 @@ func (a *MediaPlayer1) GetObjectManagerSignal() (chan *dbus.Signal, func(), erro
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -689,6 +697,7 @@ func (a *MediaPlayer1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *MediaPlayer1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -715,8 +724,13 @@ Play
 
 */
 func (a *MediaPlayer1) Play() error {
+	return a.PlayContext(context.Background())
+}
+
+// PlayContext call Play, the call is aborted when ctx is done
+func (a *MediaPlayer1) PlayContext(ctx context.Context) error {
 	
-	return a.client.Call("Play", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Play", 0, ).Store())
 	
 }
 
@@ -730,8 +744,13 @@ Pause
 
 */
 func (a *MediaPlayer1) Pause() error {
+	return a.PauseContext(context.Background())
+}
+
+// PauseContext call Pause, the call is aborted when ctx is done
+func (a *MediaPlayer1) PauseContext(ctx context.Context) error {
 	
-	return a.client.Call("Pause", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Pause", 0, ).Store())
 	
 }
 
@@ -745,8 +764,13 @@ Stop
 
 */
 func (a *MediaPlayer1) Stop() error {
+	return a.StopContext(context.Background())
+}
+
+// StopContext call Stop, the call is aborted when ctx is done
+func (a *MediaPlayer1) StopContext(ctx context.Context) error {
 	
-	return a.client.Call("Stop", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Stop", 0, ).Store())
 	
 }
 
@@ -760,8 +784,13 @@ Next
 
 */
 func (a *MediaPlayer1) Next() error {
+	return a.NextContext(context.Background())
+}
+
+// NextContext call Next, the call is aborted when ctx is done
+func (a *MediaPlayer1) NextContext(ctx context.Context) error {
 	
-	return a.client.Call("Next", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Next", 0, ).Store())
 	
 }
 
@@ -775,8 +804,13 @@ Previous
 
 */
 func (a *MediaPlayer1) Previous() error {
+	return a.PreviousContext(context.Background())
+}
+
+// PreviousContext call Previous, the call is aborted when ctx is done
+func (a *MediaPlayer1) PreviousContext(ctx context.Context) error {
 	
-	return a.client.Call("Previous", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Previous", 0, ).Store())
 	
 }
 
@@ -791,8 +825,13 @@ FastForward
 
 */
 func (a *MediaPlayer1) FastForward() error {
+	return a.FastForwardContext(context.Background())
+}
+
+// FastForwardContext call FastForward, the call is aborted when ctx is done
+func (a *MediaPlayer1) FastForwardContext(ctx context.Context) error {
 	
-	return a.client.Call("FastForward", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "FastForward", 0, ).Store())
 	
 }
 
@@ -807,8 +846,13 @@ Rewind
 
 */
 func (a *MediaPlayer1) Rewind() error {
+	return a.RewindContext(context.Background())
+}
+
+// RewindContext call Rewind, the call is aborted when ctx is done
+func (a *MediaPlayer1) RewindContext(ctx context.Context) error {
 	
-	return a.client.Call("Rewind", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Rewind", 0, ).Store())
 	
 }
 
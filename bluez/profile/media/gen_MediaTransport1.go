@@ -3,6 +3,7 @@ package media
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var MediaTransport1Interface = "org.bluez.MediaTransport1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX/fdX
 func NewMediaTransport1(objectPath dbus.ObjectPath) (*MediaTransport1, error) {
+	return NewMediaTransport1WithConn(nil, objectPath)
+}
+
+// NewMediaTransport1WithConn create a new instance of MediaTransport1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMediaTransport1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*MediaTransport1, error) {
 	a := new(MediaTransport1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewMediaTransport1(objectPath dbus.ObjectPath) (*MediaTransport1, error) {
 			Iface: MediaTransport1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -253,7 +261,7 @@ func (a *MediaTransport1) GetObjectManagerSignal() (chan *dbus.Signal, func(), e
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -342,6 +350,7 @@ func (a *MediaTransport1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *MediaTransport1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -369,12 +378,17 @@ Acquire
 
 */
 func (a *MediaTransport1) Acquire() (dbus.UnixFD, uint16, uint16, error) {
+	return a.AcquireContext(context.Background())
+}
+
+// AcquireContext call Acquire, the call is aborted when ctx is done
+func (a *MediaTransport1) AcquireContext(ctx context.Context) (dbus.UnixFD, uint16, uint16, error) {
 	
 	var val0 dbus.UnixFD
   var val1 uint16
   var val2 uint16
-	err := a.client.Call("Acquire", 0, ).Store(&val0, &val1, &val2)
-	return val0, val1, val2, err	
+	err := a.client.CallWithContext(ctx, "Acquire", 0, ).Store(&val0, &val1, &val2)
+	return val0, val1, val2, bluez.ParseError(err)	
 }
 
 /*
@@ -392,12 +406,17 @@ TryAcquire
 
 */
 func (a *MediaTransport1) TryAcquire() (dbus.UnixFD, uint16, uint16, error) {
+	return a.TryAcquireContext(context.Background())
+}
+
+// TryAcquireContext call TryAcquire, the call is aborted when ctx is done
+func (a *MediaTransport1) TryAcquireContext(ctx context.Context) (dbus.UnixFD, uint16, uint16, error) {
 	
 	var val0 dbus.UnixFD
   var val1 uint16
   var val2 uint16
-	err := a.client.Call("TryAcquire", 0, ).Store(&val0, &val1, &val2)
-	return val0, val1, val2, err	
+	err := a.client.CallWithContext(ctx, "TryAcquire", 0, ).Store(&val0, &val1, &val2)
+	return val0, val1, val2, bluez.ParseError(err)	
 }
 
 /*
@@ -407,8 +426,13 @@ Release
 
 */
 func (a *MediaTransport1) Release() error {
+	return a.ReleaseContext(context.Background())
+}
+
+// ReleaseContext call Release, the call is aborted when ctx is done
+func (a *MediaTransport1) ReleaseContext(ctx context.Context) error {
 	
-	return a.client.Call("Release", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Release", 0, ).Store())
 	
 }
 
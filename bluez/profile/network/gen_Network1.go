@@ -3,6 +3,7 @@ package network
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var Network1Interface = "org.bluez.Network1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX
 func NewNetwork1(objectPath dbus.ObjectPath) (*Network1, error) {
+	return NewNetwork1WithConn(nil, objectPath)
+}
+
+// NewNetwork1WithConn create a new instance of Network1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewNetwork1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*Network1, error) {
 	a := new(Network1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewNetwork1(objectPath dbus.ObjectPath) (*Network1, error) {
 			Iface: Network1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -154,7 +162,7 @@ func (a *Network1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error) {
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -243,6 +251,7 @@ func (a *Network1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *Network1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -273,10 +282,15 @@ Connect
 
 */
 func (a *Network1) Connect(uuid string) (string, error) {
+	return a.ConnectContext(context.Background(), uuid)
+}
+
+// ConnectContext call Connect, the call is aborted when ctx is done
+func (a *Network1) ConnectContext(ctx context.Context, uuid string) (string, error) {
 	
 	var val0 string
-	err := a.client.Call("Connect", 0, uuid).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "Connect", 0, uuid).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -291,8 +305,13 @@ Disconnect
 
 */
 func (a *Network1) Disconnect() error {
+	return a.DisconnectContext(context.Background())
+}
+
+// DisconnectContext call Disconnect, the call is aborted when ctx is done
+func (a *Network1) DisconnectContext(ctx context.Context) error {
 	
-	return a.client.Call("Disconnect", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Disconnect", 0, ).Store())
 	
 }
 
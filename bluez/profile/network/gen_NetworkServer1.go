@@ -3,6 +3,7 @@ package network
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var NetworkServer1Interface = "org.bluez.NetworkServer1"
 // Args:
 // - objectPath: /org/bluez/{hci0,hci1,...}
 func NewNetworkServer1(objectPath dbus.ObjectPath) (*NetworkServer1, error) {
+	return NewNetworkServer1WithConn(nil, objectPath)
+}
+
+// NewNetworkServer1WithConn create a new instance of NetworkServer1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewNetworkServer1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*NetworkServer1, error) {
 	a := new(NetworkServer1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewNetworkServer1(objectPath dbus.ObjectPath) (*NetworkServer1, error) {
 			Iface: NetworkServer1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -97,7 +105,7 @@ func (a *NetworkServer1) GetObjectManagerSignal() (chan *dbus.Signal, func(), er
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -186,6 +194,7 @@ func (a *NetworkServer1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *NetworkServer1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -218,8 +227,13 @@ Register
 
 */
 func (a *NetworkServer1) Register(uuid string, bridge string) error {
+	return a.RegisterContext(context.Background(), uuid, bridge)
+}
+
+// RegisterContext call Register, the call is aborted when ctx is done
+func (a *NetworkServer1) RegisterContext(ctx context.Context, uuid string, bridge string) error {
 	
-	return a.client.Call("Register", 0, uuid, bridge).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Register", 0, uuid, bridge).Store())
 	
 }
 
@@ -232,8 +246,13 @@ Unregister
 
 */
 func (a *NetworkServer1) Unregister(uuid string) error {
+	return a.UnregisterContext(context.Background(), uuid)
+}
+
+// UnregisterContext call Unregister, the call is aborted when ctx is done
+func (a *NetworkServer1) UnregisterContext(ctx context.Context, uuid string) error {
 	
-	return a.client.Call("Unregister", 0, uuid).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Unregister", 0, uuid).Store())
 	
 }
 
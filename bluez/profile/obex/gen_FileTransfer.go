@@ -3,6 +3,7 @@ package obex
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var FileTransferInterface = "org.bluez.obex.FileTransfer"
 // Args:
 // - objectPath: [Session object path]
 func NewFileTransfer(objectPath dbus.ObjectPath) (*FileTransfer, error) {
+	return NewFileTransferWithConn(nil, objectPath)
+}
+
+// NewFileTransferWithConn create a new instance of FileTransfer using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewFileTransferWithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*FileTransfer, error) {
 	a := new(FileTransfer)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewFileTransfer(objectPath dbus.ObjectPath) (*FileTransfer, error) {
 			Iface: FileTransferInterface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -97,7 +105,7 @@ func (a *FileTransfer) GetObjectManagerSignal() (chan *dbus.Signal, func(), erro
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -186,6 +194,7 @@ func (a *FileTransfer) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *FileTransfer) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -212,8 +221,13 @@ ChangeFolder
 
 */
 func (a *FileTransfer) ChangeFolder(folder string) error {
+	return a.ChangeFolderContext(context.Background(), folder)
+}
+
+// ChangeFolderContext call ChangeFolder, the call is aborted when ctx is done
+func (a *FileTransfer) ChangeFolderContext(ctx context.Context, folder string) error {
 	
-	return a.client.Call("ChangeFolder", 0, folder).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "ChangeFolder", 0, folder).Store())
 	
 }
 
@@ -227,8 +241,13 @@ CreateFolder
 
 */
 func (a *FileTransfer) CreateFolder(folder string) error {
+	return a.CreateFolderContext(context.Background(), folder)
+}
+
+// CreateFolderContext call CreateFolder, the call is aborted when ctx is done
+func (a *FileTransfer) CreateFolderContext(ctx context.Context, folder string) error {
 	
-	return a.client.Call("CreateFolder", 0, folder).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "CreateFolder", 0, folder).Store())
 	
 }
 
@@ -254,10 +273,15 @@ ListFolder
 
 */
 func (a *FileTransfer) ListFolder() ([]map[string]interface{}, error) {
+	return a.ListFolderContext(context.Background())
+}
+
+// ListFolderContext call ListFolder, the call is aborted when ctx is done
+func (a *FileTransfer) ListFolderContext(ctx context.Context) ([]map[string]interface{}, error) {
 	
 	var val0 []map[string]interface{}
-	err := a.client.Call("ListFolder", 0, ).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "ListFolder", 0, ).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -281,11 +305,16 @@ GetFile
 
 */
 func (a *FileTransfer) GetFile(targetfile string, sourcefile string) (dbus.ObjectPath, map[string]interface{}, error) {
+	return a.GetFileContext(context.Background(), targetfile, sourcefile)
+}
+
+// GetFileContext call GetFile, the call is aborted when ctx is done
+func (a *FileTransfer) GetFileContext(ctx context.Context, targetfile string, sourcefile string) (dbus.ObjectPath, map[string]interface{}, error) {
 	
 	var val0 dbus.ObjectPath
   var val1 map[string]interface{}
-	err := a.client.Call("GetFile", 0, targetfile, sourcefile).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "GetFile", 0, targetfile, sourcefile).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
 /*
@@ -306,11 +335,16 @@ PutFile
 
 */
 func (a *FileTransfer) PutFile(sourcefile string, targetfile string) (dbus.ObjectPath, map[string]interface{}, error) {
+	return a.PutFileContext(context.Background(), sourcefile, targetfile)
+}
+
+// PutFileContext call PutFile, the call is aborted when ctx is done
+func (a *FileTransfer) PutFileContext(ctx context.Context, sourcefile string, targetfile string) (dbus.ObjectPath, map[string]interface{}, error) {
 	
 	var val0 dbus.ObjectPath
   var val1 map[string]interface{}
-	err := a.client.Call("PutFile", 0, sourcefile, targetfile).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "PutFile", 0, sourcefile, targetfile).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
 /*
@@ -324,8 +358,13 @@ CopyFile
 
 */
 func (a *FileTransfer) CopyFile(sourcefile string, targetfile string) error {
+	return a.CopyFileContext(context.Background(), sourcefile, targetfile)
+}
+
+// CopyFileContext call CopyFile, the call is aborted when ctx is done
+func (a *FileTransfer) CopyFileContext(ctx context.Context, sourcefile string, targetfile string) error {
 	
-	return a.client.Call("CopyFile", 0, sourcefile, targetfile).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "CopyFile", 0, sourcefile, targetfile).Store())
 	
 }
 
@@ -340,8 +379,13 @@ MoveFile
 
 */
 func (a *FileTransfer) MoveFile(sourcefile string, targetfile string) error {
+	return a.MoveFileContext(context.Background(), sourcefile, targetfile)
+}
+
+// MoveFileContext call MoveFile, the call is aborted when ctx is done
+func (a *FileTransfer) MoveFileContext(ctx context.Context, sourcefile string, targetfile string) error {
 	
-	return a.client.Call("MoveFile", 0, sourcefile, targetfile).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "MoveFile", 0, sourcefile, targetfile).Store())
 	
 }
 
@@ -356,8 +400,13 @@ Delete
 
 */
 func (a *FileTransfer) Delete(file string) error {
+	return a.DeleteContext(context.Background(), file)
+}
+
+// DeleteContext call Delete, the call is aborted when ctx is done
+func (a *FileTransfer) DeleteContext(ctx context.Context, file string) error {
 	
-	return a.client.Call("Delete", 0, file).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Delete", 0, file).Store())
 	
 }
 
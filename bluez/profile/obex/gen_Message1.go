@@ -3,6 +3,7 @@ package obex
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var Message1Interface = "org.bluez.obex.Message1"
 // Args:
 // - objectPath: [Session object path]/{message0,...}
 func NewMessage1(objectPath dbus.ObjectPath) (*Message1, error) {
+	return NewMessage1WithConn(nil, objectPath)
+}
+
+// NewMessage1WithConn create a new instance of Message1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMessage1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*Message1, error) {
 	a := new(Message1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewMessage1(objectPath dbus.ObjectPath) (*Message1, error) {
 			Iface: Message1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -402,7 +410,7 @@ func (a *Message1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error) {
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -491,6 +499,7 @@ func (a *Message1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *Message1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -527,10 +536,15 @@ Get
 
 */
 func (a *Message1) Get(targetfile string, attachment bool) (dbus.ObjectPath, map[string]interface{}, error) {
+	return a.GetContext(context.Background(), targetfile, attachment)
+}
+
+// GetContext call Get, the call is aborted when ctx is done
+func (a *Message1) GetContext(ctx context.Context, targetfile string, attachment bool) (dbus.ObjectPath, map[string]interface{}, error) {
 	
 	var val0 dbus.ObjectPath
   var val1 map[string]interface{}
-	err := a.client.Call("Get", 0, targetfile, attachment).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "Get", 0, targetfile, attachment).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
@@ -3,6 +3,7 @@ package obex
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var MessageAccess1Interface = "org.bluez.obex.MessageAccess1"
 // Args:
 // - objectPath: [Session object path]
 func NewMessageAccess1(objectPath dbus.ObjectPath) (*MessageAccess1, error) {
+	return NewMessageAccess1WithConn(nil, objectPath)
+}
+
+// NewMessageAccess1WithConn create a new instance of MessageAccess1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewMessageAccess1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*MessageAccess1, error) {
 	a := new(MessageAccess1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewMessageAccess1(objectPath dbus.ObjectPath) (*MessageAccess1, error) {
 			Iface: MessageAccess1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -97,7 +105,7 @@ func (a *MessageAccess1) GetObjectManagerSignal() (chan *dbus.Signal, func(), er
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -186,6 +194,7 @@ func (a *MessageAccess1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *MessageAccess1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -213,8 +222,13 @@ SetFolder
 
 */
 func (a *MessageAccess1) SetFolder(name string) error {
+	return a.SetFolderContext(context.Background(), name)
+}
+
+// SetFolderContext call SetFolder, the call is aborted when ctx is done
+func (a *MessageAccess1) SetFolderContext(ctx context.Context, name string) error {
 	
-	return a.client.Call("SetFolder", 0, name).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "SetFolder", 0, name).Store())
 	
 }
 
@@ -235,10 +249,15 @@ ListFolders
 
 */
 func (a *MessageAccess1) ListFolders(filter map[string]interface{}) ([]map[string]interface{}, error) {
+	return a.ListFoldersContext(context.Background(), filter)
+}
+
+// ListFoldersContext call ListFolders, the call is aborted when ctx is done
+func (a *MessageAccess1) ListFoldersContext(ctx context.Context, filter map[string]interface{}) ([]map[string]interface{}, error) {
 	
 	var val0 []map[string]interface{}
-	err := a.client.Call("ListFolders", 0, filter).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "ListFolders", 0, filter).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -251,10 +270,15 @@ ListFilterFields
 
 */
 func (a *MessageAccess1) ListFilterFields() ([]string, error) {
+	return a.ListFilterFieldsContext(context.Background())
+}
+
+// ListFilterFieldsContext call ListFilterFields, the call is aborted when ctx is done
+func (a *MessageAccess1) ListFilterFieldsContext(ctx context.Context) ([]string, error) {
 	
 	var val0 []string
-	err := a.client.Call("ListFilterFields", 0, ).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "ListFilterFields", 0, ).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -355,19 +379,29 @@ ListMessages
 
 */
 func (a *MessageAccess1) ListMessages(folder string, filter map[string]interface{}) ([]dbus.ObjectPath, map[string]interface{}, error) {
+	return a.ListMessagesContext(context.Background(), folder, filter)
+}
+
+// ListMessagesContext call ListMessages, the call is aborted when ctx is done
+func (a *MessageAccess1) ListMessagesContext(ctx context.Context, folder string, filter map[string]interface{}) ([]dbus.ObjectPath, map[string]interface{}, error) {
 	
 	var val0 []dbus.ObjectPath
   var val1 map[string]interface{}
-	err := a.client.Call("ListMessages", 0, folder, filter).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "ListMessages", 0, folder, filter).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
 /*
 UpdateInbox 
 */
 func (a *MessageAccess1) UpdateInbox() error {
+	return a.UpdateInboxContext(context.Background())
+}
+
+// UpdateInboxContext call UpdateInbox, the call is aborted when ctx is done
+func (a *MessageAccess1) UpdateInboxContext(ctx context.Context) error {
 	
-	return a.client.Call("UpdateInbox", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "UpdateInbox", 0, ).Store())
 	
 }
 
@@ -454,8 +488,13 @@ Filter:		uint16 Offset:
 
 */
 func (a *MessageAccess1) PushMessage(sourcefile string, folder string, args map[string]interface{}) error {
+	return a.PushMessageContext(context.Background(), sourcefile, folder, args)
+}
+
+// PushMessageContext call PushMessage, the call is aborted when ctx is done
+func (a *MessageAccess1) PushMessageContext(ctx context.Context, sourcefile string, folder string, args map[string]interface{}) error {
 	
-	return a.client.Call("PushMessage", 0, sourcefile, folder, args).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "PushMessage", 0, sourcefile, folder, args).Store())
 	
 }
 
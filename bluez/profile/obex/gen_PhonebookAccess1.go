@@ -3,6 +3,7 @@ package obex
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var PhonebookAccess1Interface = "org.bluez.obex.PhonebookAccess1"
 // Args:
 // - objectPath: [Session object path]
 func NewPhonebookAccess1(objectPath dbus.ObjectPath) (*PhonebookAccess1, error) {
+	return NewPhonebookAccess1WithConn(nil, objectPath)
+}
+
+// NewPhonebookAccess1WithConn create a new instance of PhonebookAccess1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewPhonebookAccess1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*PhonebookAccess1, error) {
 	a := new(PhonebookAccess1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewPhonebookAccess1(objectPath dbus.ObjectPath) (*PhonebookAccess1, error)
 			Iface: PhonebookAccess1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -204,7 +212,7 @@ func (a *PhonebookAccess1) GetObjectManagerSignal() (chan *dbus.Signal, func(),
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -293,6 +301,7 @@ func (a *PhonebookAccess1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *PhonebookAccess1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -336,8 +345,13 @@ Select
 
 */
 func (a *PhonebookAccess1) Select(location string, phonebook string) error {
+	return a.SelectContext(context.Background(), location, phonebook)
+}
+
+// SelectContext call Select, the call is aborted when ctx is done
+func (a *PhonebookAccess1) SelectContext(ctx context.Context, location string, phonebook string) error {
 	
-	return a.client.Call("Select", 0, location, phonebook).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Select", 0, location, phonebook).Store())
 	
 }
 
@@ -366,11 +380,16 @@ PullAll
 
 */
 func (a *PhonebookAccess1) PullAll(targetfile string, filters map[string]interface{}) (dbus.ObjectPath, map[string]interface{}, error) {
+	return a.PullAllContext(context.Background(), targetfile, filters)
+}
+
+// PullAllContext call PullAll, the call is aborted when ctx is done
+func (a *PhonebookAccess1) PullAllContext(ctx context.Context, targetfile string, filters map[string]interface{}) (dbus.ObjectPath, map[string]interface{}, error) {
 	
 	var val0 dbus.ObjectPath
   var val1 map[string]interface{}
-	err := a.client.Call("PullAll", 0, targetfile, filters).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "PullAll", 0, targetfile, filters).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
 /*
@@ -388,11 +407,16 @@ List
 
 */
 func (a *PhonebookAccess1) List(filters map[string]interface{}) ([]string, string, error) {
+	return a.ListContext(context.Background(), filters)
+}
+
+// ListContext call List, the call is aborted when ctx is done
+func (a *PhonebookAccess1) ListContext(ctx context.Context, filters map[string]interface{}) ([]string, string, error) {
 	
 	var val0 []string
   var val1 string
-	err := a.client.Call("List", 0, filters).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "List", 0, filters).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
 /*
@@ -419,8 +443,13 @@ Pull
 
 */
 func (a *PhonebookAccess1) Pull(vcard string, targetfile string, filters map[string]interface{}) error {
+	return a.PullContext(context.Background(), vcard, targetfile, filters)
+}
+
+// PullContext call Pull, the call is aborted when ctx is done
+func (a *PhonebookAccess1) PullContext(ctx context.Context, vcard string, targetfile string, filters map[string]interface{}) error {
 	
-	return a.client.Call("Pull", 0, vcard, targetfile, filters).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Pull", 0, vcard, targetfile, filters).Store())
 	
 }
 
@@ -447,8 +476,13 @@ Search
 
 */
 func (a *PhonebookAccess1) Search(field string, value string, filters map[string]interface{}) error {
+	return a.SearchContext(context.Background(), field, value, filters)
+}
+
+// SearchContext call Search, the call is aborted when ctx is done
+func (a *PhonebookAccess1) SearchContext(ctx context.Context, field string, value string, filters map[string]interface{}) error {
 	
-	return a.client.Call("Search", 0, field, value, filters).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Search", 0, field, value, filters).Store())
 	
 }
 
@@ -464,10 +498,15 @@ GetSize
 
 */
 func (a *PhonebookAccess1) GetSize() (uint16, error) {
+	return a.GetSizeContext(context.Background())
+}
+
+// GetSizeContext call GetSize, the call is aborted when ctx is done
+func (a *PhonebookAccess1) GetSizeContext(ctx context.Context) (uint16, error) {
 	
 	var val0 uint16
-	err := a.client.Call("GetSize", 0, ).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "GetSize", 0, ).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -481,8 +520,13 @@ UpdateVersion
 
 */
 func (a *PhonebookAccess1) UpdateVersion() error {
+	return a.UpdateVersionContext(context.Background())
+}
+
+// UpdateVersionContext call UpdateVersion, the call is aborted when ctx is done
+func (a *PhonebookAccess1) UpdateVersionContext(ctx context.Context) error {
 	
-	return a.client.Call("UpdateVersion", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "UpdateVersion", 0, ).Store())
 	
 }
 
@@ -542,9 +586,14 @@ Filter:		string Format:
 
 */
 func (a *PhonebookAccess1) ListFilterFields() ([]string, error) {
+	return a.ListFilterFieldsContext(context.Background())
+}
+
+// ListFilterFieldsContext call ListFilterFields, the call is aborted when ctx is done
+func (a *PhonebookAccess1) ListFilterFieldsContext(ctx context.Context) ([]string, error) {
 	
 	var val0 []string
-	err := a.client.Call("ListFilterFields", 0, ).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "ListFilterFields", 0, ).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
@@ -3,6 +3,7 @@ package obex
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var Synchronization1Interface = "org.bluez.obex.Synchronization1"
 // Args:
 // - objectPath: [Session object path]
 func NewSynchronization1(objectPath dbus.ObjectPath) (*Synchronization1, error) {
+	return NewSynchronization1WithConn(nil, objectPath)
+}
+
+// NewSynchronization1WithConn create a new instance of Synchronization1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewSynchronization1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*Synchronization1, error) {
 	a := new(Synchronization1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewSynchronization1(objectPath dbus.ObjectPath) (*Synchronization1, error)
 			Iface: Synchronization1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -97,7 +105,7 @@ func (a *Synchronization1) GetObjectManagerSignal() (chan *dbus.Signal, func(),
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -186,6 +194,7 @@ func (a *Synchronization1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *Synchronization1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -220,8 +229,13 @@ SetLocation
 
 */
 func (a *Synchronization1) SetLocation(location string) error {
+	return a.SetLocationContext(context.Background(), location)
+}
+
+// SetLocationContext call SetLocation, the call is aborted when ctx is done
+func (a *Synchronization1) SetLocationContext(ctx context.Context, location string) error {
 	
-	return a.client.Call("SetLocation", 0, location).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "SetLocation", 0, location).Store())
 	
 }
 
@@ -246,11 +260,16 @@ GetPhonebook
 
 */
 func (a *Synchronization1) GetPhonebook(targetfile string) (dbus.ObjectPath, map[string]interface{}, error) {
+	return a.GetPhonebookContext(context.Background(), targetfile)
+}
+
+// GetPhonebookContext call GetPhonebook, the call is aborted when ctx is done
+func (a *Synchronization1) GetPhonebookContext(ctx context.Context, targetfile string) (dbus.ObjectPath, map[string]interface{}, error) {
 	
 	var val0 dbus.ObjectPath
   var val1 map[string]interface{}
-	err := a.client.Call("GetPhonebook", 0, targetfile).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "GetPhonebook", 0, targetfile).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
 /*
@@ -271,10 +290,15 @@ PutPhonebook
 
 */
 func (a *Synchronization1) PutPhonebook(sourcefile string) (dbus.ObjectPath, map[string]interface{}, error) {
+	return a.PutPhonebookContext(context.Background(), sourcefile)
+}
+
+// PutPhonebookContext call PutPhonebook, the call is aborted when ctx is done
+func (a *Synchronization1) PutPhonebookContext(ctx context.Context, sourcefile string) (dbus.ObjectPath, map[string]interface{}, error) {
 	
 	var val0 dbus.ObjectPath
   var val1 map[string]interface{}
-	err := a.client.Call("PutPhonebook", 0, sourcefile).Store(&val0, &val1)
-	return val0, val1, err	
+	err := a.client.CallWithContext(ctx, "PutPhonebook", 0, sourcefile).Store(&val0, &val1)
+	return val0, val1, bluez.ParseError(err)	
 }
 
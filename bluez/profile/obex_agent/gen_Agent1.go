@@ -3,6 +3,7 @@ package obex_agent
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -19,6 +20,12 @@ var Agent1Interface = "org.bluez.obex.Agent1"
 // - servicePath: unique name
 // - objectPath: freely definable
 func NewAgent1(servicePath string, objectPath dbus.ObjectPath) (*Agent1, error) {
+	return NewAgent1WithConn(nil, servicePath, objectPath)
+}
+
+// NewAgent1WithConn create a new instance of Agent1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewAgent1WithConn(conn *bluez.Conn, servicePath string, objectPath dbus.ObjectPath) (*Agent1, error) {
 	a := new(Agent1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -26,6 +33,7 @@ func NewAgent1(servicePath string, objectPath dbus.ObjectPath) (*Agent1, error)
 			Iface: Agent1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -98,7 +106,7 @@ func (a *Agent1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error) {
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -187,6 +195,7 @@ func (a *Agent1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *Agent1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -214,8 +223,13 @@ Release
 
 */
 func (a *Agent1) Release() error {
+	return a.ReleaseContext(context.Background())
+}
+
+// ReleaseContext call Release, the call is aborted when ctx is done
+func (a *Agent1) ReleaseContext(ctx context.Context) error {
 	
-	return a.client.Call("Release", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Release", 0, ).Store())
 	
 }
 
@@ -235,10 +249,15 @@ AuthorizePush
 
 */
 func (a *Agent1) AuthorizePush(transfer dbus.ObjectPath) (string, error) {
+	return a.AuthorizePushContext(context.Background(), transfer)
+}
+
+// AuthorizePushContext call AuthorizePush, the call is aborted when ctx is done
+func (a *Agent1) AuthorizePushContext(ctx context.Context, transfer dbus.ObjectPath) (string, error) {
 	
 	var val0 string
-	err := a.client.Call("AuthorizePush", 0, transfer).Store(&val0)
-	return val0, err	
+	err := a.client.CallWithContext(ctx, "AuthorizePush", 0, transfer).Store(&val0)
+	return val0, bluez.ParseError(err)	
 }
 
 /*
@@ -249,8 +268,13 @@ Cancel
 
 */
 func (a *Agent1) Cancel() error {
+	return a.CancelContext(context.Background())
+}
+
+// CancelContext call Cancel, the call is aborted when ctx is done
+func (a *Agent1) CancelContext(ctx context.Context) error {
 	
-	return a.client.Call("Cancel", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Cancel", 0, ).Store())
 	
 }
 
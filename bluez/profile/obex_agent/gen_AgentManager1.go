@@ -3,6 +3,7 @@ package obex_agent
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var AgentManager1Interface = "org.bluez.obex.AgentManager1"
 // Args:
 
 func NewAgentManager1() (*AgentManager1, error) {
+	return NewAgentManager1WithConn(nil)
+}
+
+// NewAgentManager1WithConn create a new instance of AgentManager1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewAgentManager1WithConn(conn *bluez.Conn) (*AgentManager1, error) {
 	a := new(AgentManager1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewAgentManager1() (*AgentManager1, error) {
 			Iface: AgentManager1Interface,
 			Path:  dbus.ObjectPath("/org/bluez/obex"),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -97,7 +105,7 @@ func (a *AgentManager1) GetObjectManagerSignal() (chan *dbus.Signal, func(), err
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -186,6 +194,7 @@ func (a *AgentManager1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *AgentManager1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -213,8 +222,13 @@ RegisterAgent
 
 */
 func (a *AgentManager1) RegisterAgent(agent dbus.ObjectPath) error {
+	return a.RegisterAgentContext(context.Background(), agent)
+}
+
+// RegisterAgentContext call RegisterAgent, the call is aborted when ctx is done
+func (a *AgentManager1) RegisterAgentContext(ctx context.Context, agent dbus.ObjectPath) error {
 	
-	return a.client.Call("RegisterAgent", 0, agent).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RegisterAgent", 0, agent).Store())
 	
 }
 
@@ -230,8 +244,13 @@ UnregisterAgent
 
 */
 func (a *AgentManager1) UnregisterAgent(agent dbus.ObjectPath) error {
+	return a.UnregisterAgentContext(context.Background(), agent)
+}
+
+// UnregisterAgentContext call UnregisterAgent, the call is aborted when ctx is done
+func (a *AgentManager1) UnregisterAgentContext(ctx context.Context, agent dbus.ObjectPath) error {
 	
-	return a.client.Call("UnregisterAgent", 0, agent).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "UnregisterAgent", 0, agent).Store())
 	
 }
 
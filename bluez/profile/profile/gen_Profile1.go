@@ -3,6 +3,7 @@ package profile
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/godbus/dbus/v5"
@@ -17,6 +18,12 @@ var Profile1Interface = "org.bluez.Profile1"
 // - servicePath: unique name
 // - objectPath: freely definable
 func NewProfile1(servicePath string, objectPath dbus.ObjectPath) (*Profile1, error) {
+	return NewProfile1WithConn(nil, servicePath, objectPath)
+}
+
+// NewProfile1WithConn create a new instance of Profile1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewProfile1WithConn(conn *bluez.Conn, servicePath string, objectPath dbus.ObjectPath) (*Profile1, error) {
 	a := new(Profile1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -24,6 +31,7 @@ func NewProfile1(servicePath string, objectPath dbus.ObjectPath) (*Profile1, err
 			Iface: Profile1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -87,7 +95,7 @@ func (a *Profile1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error) {
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -127,8 +135,13 @@ Release
 
 */
 func (a *Profile1) Release() error {
+	return a.ReleaseContext(context.Background())
+}
+
+// ReleaseContext call Release, the call is aborted when ctx is done
+func (a *Profile1) ReleaseContext(ctx context.Context) error {
 	
-	return a.client.Call("Release", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Release", 0, ).Store())
 	
 }
 
@@ -148,8 +161,13 @@ NewConnection
 
 */
 func (a *Profile1) NewConnection(device dbus.ObjectPath, fd int32, fd_properties map[string]interface{}) error {
+	return a.NewConnectionContext(context.Background(), device, fd, fd_properties)
+}
+
+// NewConnectionContext call NewConnection, the call is aborted when ctx is done
+func (a *Profile1) NewConnectionContext(ctx context.Context, device dbus.ObjectPath, fd int32, fd_properties map[string]interface{}) error {
 	
-	return a.client.Call("NewConnection", 0, device, fd, fd_properties).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "NewConnection", 0, device, fd, fd_properties).Store())
 	
 }
 
@@ -172,8 +190,13 @@ RequestDisconnection
 
 */
 func (a *Profile1) RequestDisconnection(device dbus.ObjectPath) error {
+	return a.RequestDisconnectionContext(context.Background(), device)
+}
+
+// RequestDisconnectionContext call RequestDisconnection, the call is aborted when ctx is done
+func (a *Profile1) RequestDisconnectionContext(ctx context.Context, device dbus.ObjectPath) error {
 	
-	return a.client.Call("RequestDisconnection", 0, device).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RequestDisconnection", 0, device).Store())
 	
 }
 
@@ -3,6 +3,7 @@ package profile
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/godbus/dbus/v5"
@@ -16,6 +17,12 @@ var ProfileManager1Interface = "org.bluez.ProfileManager1"
 // Args:
 
 func NewProfileManager1() (*ProfileManager1, error) {
+	return NewProfileManager1WithConn(nil)
+}
+
+// NewProfileManager1WithConn create a new instance of ProfileManager1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewProfileManager1WithConn(conn *bluez.Conn) (*ProfileManager1, error) {
 	a := new(ProfileManager1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -23,6 +30,7 @@ func NewProfileManager1() (*ProfileManager1, error) {
 			Iface: ProfileManager1Interface,
 			Path:  dbus.ObjectPath("/org/bluez"),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -86,7 +94,7 @@ func (a *ProfileManager1) GetObjectManagerSignal() (chan *dbus.Signal, func(), e
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -138,8 +146,13 @@ RegisterProfile
 
 */
 func (a *ProfileManager1) RegisterProfile(profile dbus.ObjectPath, uuid string, options map[string]interface{}) error {
+	return a.RegisterProfileContext(context.Background(), profile, uuid, options)
+}
+
+// RegisterProfileContext call RegisterProfile, the call is aborted when ctx is done
+func (a *ProfileManager1) RegisterProfileContext(ctx context.Context, profile dbus.ObjectPath, uuid string, options map[string]interface{}) error {
 	
-	return a.client.Call("RegisterProfile", 0, profile, uuid, options).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RegisterProfile", 0, profile, uuid, options).Store())
 	
 }
 
@@ -155,8 +168,13 @@ UnregisterProfile
 
 */
 func (a *ProfileManager1) UnregisterProfile(profile dbus.ObjectPath) error {
+	return a.UnregisterProfileContext(context.Background(), profile)
+}
+
+// UnregisterProfileContext call UnregisterProfile, the call is aborted when ctx is done
+func (a *ProfileManager1) UnregisterProfileContext(ctx context.Context, profile dbus.ObjectPath) error {
 	
-	return a.client.Call("UnregisterProfile", 0, profile).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "UnregisterProfile", 0, profile).Store())
 	
 }
 
@@ -3,6 +3,7 @@ package sap
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var SimAccess1Interface = "org.bluez.SimAccess1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}
 func NewSimAccess1(objectPath dbus.ObjectPath) (*SimAccess1, error) {
+	return NewSimAccess1WithConn(nil, objectPath)
+}
+
+// NewSimAccess1WithConn create a new instance of SimAccess1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewSimAccess1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*SimAccess1, error) {
 	a := new(SimAccess1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewSimAccess1(objectPath dbus.ObjectPath) (*SimAccess1, error) {
 			Iface: SimAccess1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -116,7 +124,7 @@ func (a *SimAccess1) GetObjectManagerSignal() (chan *dbus.Signal, func(), error)
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -205,6 +213,7 @@ func (a *SimAccess1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *SimAccess1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -230,8 +239,13 @@ Disconnect
 
 */
 func (a *SimAccess1) Disconnect() error {
+	return a.DisconnectContext(context.Background())
+}
+
+// DisconnectContext call Disconnect, the call is aborted when ctx is done
+func (a *SimAccess1) DisconnectContext(ctx context.Context) error {
 	
-	return a.client.Call("Disconnect", 0, ).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "Disconnect", 0, ).Store())
 	
 }
 
@@ -18,6 +18,12 @@ var Thermometer1Interface = "org.bluez.Thermometer1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}/dev_XX_XX_XX_XX_XX_XX
 func NewThermometer1(objectPath dbus.ObjectPath) (*Thermometer1, error) {
+	return NewThermometer1WithConn(nil, objectPath)
+}
+
+// NewThermometer1WithConn create a new instance of Thermometer1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewThermometer1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*Thermometer1, error) {
 	a := new(Thermometer1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +31,7 @@ func NewThermometer1(objectPath dbus.ObjectPath) (*Thermometer1, error) {
 			Iface: Thermometer1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -186,7 +193,7 @@ func (a *Thermometer1) GetObjectManagerSignal() (chan *dbus.Signal, func(), erro
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -275,6 +282,7 @@ func (a *Thermometer1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *Thermometer1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -3,6 +3,7 @@ package thermometer
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -18,6 +19,12 @@ var ThermometerManager1Interface = "org.bluez.ThermometerManager1"
 // Args:
 // - objectPath: [variable prefix]/{hci0,hci1,...}
 func NewThermometerManager1(objectPath dbus.ObjectPath) (*ThermometerManager1, error) {
+	return NewThermometerManager1WithConn(nil, objectPath)
+}
+
+// NewThermometerManager1WithConn create a new instance of ThermometerManager1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewThermometerManager1WithConn(conn *bluez.Conn, objectPath dbus.ObjectPath) (*ThermometerManager1, error) {
 	a := new(ThermometerManager1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -25,6 +32,7 @@ func NewThermometerManager1(objectPath dbus.ObjectPath) (*ThermometerManager1, e
 			Iface: ThermometerManager1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -97,7 +105,7 @@ func (a *ThermometerManager1) GetObjectManagerSignal() (chan *dbus.Signal, func(
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -186,6 +194,7 @@ func (a *ThermometerManager1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *ThermometerManager1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -211,8 +220,13 @@ RegisterWatcher
 
 */
 func (a *ThermometerManager1) RegisterWatcher(agent dbus.ObjectPath) error {
+	return a.RegisterWatcherContext(context.Background(), agent)
+}
+
+// RegisterWatcherContext call RegisterWatcher, the call is aborted when ctx is done
+func (a *ThermometerManager1) RegisterWatcherContext(ctx context.Context, agent dbus.ObjectPath) error {
 	
-	return a.client.Call("RegisterWatcher", 0, agent).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "RegisterWatcher", 0, agent).Store())
 	
 }
 
@@ -221,8 +235,13 @@ UnregisterWatcher
 
 */
 func (a *ThermometerManager1) UnregisterWatcher(agent dbus.ObjectPath) error {
+	return a.UnregisterWatcherContext(context.Background(), agent)
+}
+
+// UnregisterWatcherContext call UnregisterWatcher, the call is aborted when ctx is done
+func (a *ThermometerManager1) UnregisterWatcherContext(ctx context.Context, agent dbus.ObjectPath) error {
 	
-	return a.client.Call("UnregisterWatcher", 0, agent).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "UnregisterWatcher", 0, agent).Store())
 	
 }
 
@@ -235,8 +254,13 @@ EnableIntermediateMeasurement
 
 */
 func (a *ThermometerManager1) EnableIntermediateMeasurement(agent dbus.ObjectPath) error {
+	return a.EnableIntermediateMeasurementContext(context.Background(), agent)
+}
+
+// EnableIntermediateMeasurementContext call EnableIntermediateMeasurement, the call is aborted when ctx is done
+func (a *ThermometerManager1) EnableIntermediateMeasurementContext(ctx context.Context, agent dbus.ObjectPath) error {
 	
-	return a.client.Call("EnableIntermediateMeasurement", 0, agent).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "EnableIntermediateMeasurement", 0, agent).Store())
 	
 }
 
@@ -253,8 +277,13 @@ DisableIntermediateMeasurement
 
 */
 func (a *ThermometerManager1) DisableIntermediateMeasurement(agent dbus.ObjectPath) error {
+	return a.DisableIntermediateMeasurementContext(context.Background(), agent)
+}
+
+// DisableIntermediateMeasurementContext call DisableIntermediateMeasurement, the call is aborted when ctx is done
+func (a *ThermometerManager1) DisableIntermediateMeasurementContext(ctx context.Context, agent dbus.ObjectPath) error {
 	
-	return a.client.Call("DisableIntermediateMeasurement", 0, agent).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "DisableIntermediateMeasurement", 0, agent).Store())
 	
 }
 
@@ -3,6 +3,7 @@ package thermometer
 
 
 import (
+   "context"
    "sync"
    "github.com/dimonzozo/go-bluetooth/bluez"
    "github.com/dimonzozo/go-bluetooth/util"
@@ -19,6 +20,12 @@ var ThermometerWatcher1Interface = "org.bluez.ThermometerWatcher1"
 // - servicePath: unique name
 // - objectPath: freely definable
 func NewThermometerWatcher1(servicePath string, objectPath dbus.ObjectPath) (*ThermometerWatcher1, error) {
+	return NewThermometerWatcher1WithConn(nil, servicePath, objectPath)
+}
+
+// NewThermometerWatcher1WithConn create a new instance of ThermometerWatcher1 using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func NewThermometerWatcher1WithConn(conn *bluez.Conn, servicePath string, objectPath dbus.ObjectPath) (*ThermometerWatcher1, error) {
 	a := new(ThermometerWatcher1)
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -26,6 +33,7 @@ func NewThermometerWatcher1(servicePath string, objectPath dbus.ObjectPath) (*Th
 			Iface: ThermometerWatcher1Interface,
 			Path:  dbus.ObjectPath(objectPath),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	
@@ -98,7 +106,7 @@ func (a *ThermometerWatcher1) GetObjectManagerSignal() (chan *dbus.Signal, func(
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -187,6 +195,7 @@ func (a *ThermometerWatcher1) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *ThermometerWatcher1) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -220,8 +229,13 @@ MeasurementReceived
 
 */
 func (a *ThermometerWatcher1) MeasurementReceived(measurement map[string]interface{}) error {
+	return a.MeasurementReceivedContext(context.Background(), measurement)
+}
+
+// MeasurementReceivedContext call MeasurementReceived, the call is aborted when ctx is done
+func (a *ThermometerWatcher1) MeasurementReceivedContext(ctx context.Context, measurement map[string]interface{}) error {
 	
-	return a.client.Call("MeasurementReceived", 0, measurement).Store()
+	return bluez.ParseError(a.client.CallWithContext(ctx, "MeasurementReceived", 0, measurement).Store())
 	
 }
 
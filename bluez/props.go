@@ -2,6 +2,7 @@ package bluez
 
 import (
 	"reflect"
+	"sync"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/dimonzozo/go-bluetooth/util"
@@ -14,36 +15,57 @@ type WatchableClient interface {
 	ToProps() Properties
 }
 
+type propertiesWatch struct {
+	signal  chan *dbus.Signal
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+var watches = map[chan *PropertyChanged]*propertiesWatch{}
+var watchesLock sync.Mutex
+
 // WatchProperties updates on property changes
 func WatchProperties(wprop WatchableClient) (chan *PropertyChanged, error) {
 
-	channel, err := wprop.Client().Register(wprop.Path(), PropertiesInterface)
+	channel, err := wprop.Client().RegisterWithOptions(
+		SubscribeOptions{Overflow: OverflowCoalesce},
+		SignalFilter{
+			Path:      wprop.Path(),
+			Interface: PropertiesInterface,
+			Member:    "PropertiesChanged",
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
 	ch := make(chan *PropertyChanged)
 
+	watch := &propertiesWatch{
+		signal:  channel,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	watchesLock.Lock()
+	watches[ch] = watch
+	watchesLock.Unlock()
+
 	go (func() {
+		defer close(watch.stopped)
 		for {
 
-			if channel == nil {
-				break
+			var sig *dbus.Signal
+			select {
+			case sig = <-channel:
+			case <-watch.done:
+				return
 			}
 
-			sig := <-channel
-
 			if sig == nil {
 				return
 			}
 
-			if sig.Name != PropertiesChanged {
-				continue
-			}
-			if sig.Path != wprop.Path() {
-				continue
-			}
-
 			iface := sig.Body[0].(string)
 			changes := sig.Body[1].(map[string]dbus.Variant)
 
@@ -63,6 +85,7 @@ func WatchProperties(wprop WatchableClient) (chan *PropertyChanged, error) {
 						// map[*]variant -> map[*]interface{}
 						ok, err := util.AssignMapVariantToInterface(f, x)
 						if err != nil {
+							wprop.ToProps().Unlock()
 							log.Errorf("Failed to set %s: %s", f.String(), err)
 							continue
 						}
@@ -79,7 +102,12 @@ func WatchProperties(wprop WatchableClient) (chan *PropertyChanged, error) {
 					Name:      field,
 					Value:     val.Value(),
 				}
-				ch <- propChanged
+
+				select {
+				case ch <- propChanged:
+				case <-watch.done:
+					return
+				}
 			}
 
 		}
@@ -89,7 +117,20 @@ func WatchProperties(wprop WatchableClient) (chan *PropertyChanged, error) {
 }
 
 func UnwatchProperties(wprop WatchableClient, ch chan *PropertyChanged) error {
+
+	watchesLock.Lock()
+	watch, ok := watches[ch]
+	delete(watches, ch)
+	watchesLock.Unlock()
+
+	var err error
+	if ok {
+		close(watch.done)
+		<-watch.stopped
+		err = wprop.Client().Unregister(wprop.Path(), PropertiesInterface, watch.signal)
+	}
+
 	ch <- nil
 	close(ch)
-	return nil
+	return err
 }
@@ -0,0 +1,482 @@
+package bluez
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// OverflowPolicy define how a subscription handles signals when its buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowCoalesce merge PropertiesChanged signals pending for the same
+	// object interface, if still full the oldest pending signal is discarded.
+	// This is the default policy
+	OverflowCoalesce OverflowPolicy = iota
+	// OverflowDrop discard the new signal
+	OverflowDrop
+	// OverflowBlock wait for the subscriber to consume pending signals. The
+	// dispatch of the connection signals is stalled meanwhile, use it only for
+	// subscribers which never block
+	OverflowBlock
+)
+
+// DefaultSignalBufferSize is the subscription buffer size used when not specified
+const DefaultSignalBufferSize = 100
+
+// SignalFilter select signals by sender, path, interface and member.
+// Empty fields match any value
+type SignalFilter struct {
+	Sender string
+	// Path match the exact object path
+	Path dbus.ObjectPath
+	// PathNamespace match an object path and its children
+	PathNamespace dbus.ObjectPath
+	Interface     string
+	Member        string
+}
+
+// MatchRule return the DBus match rule for the filter
+func (f SignalFilter) MatchRule() string {
+	rule := []string{"type='signal'"}
+	if f.Sender != "" {
+		rule = append(rule, fmt.Sprintf("sender='%s'", f.Sender))
+	}
+	if f.Path != "" {
+		rule = append(rule, fmt.Sprintf("path='%s'", f.Path))
+	}
+	if f.PathNamespace != "" {
+		rule = append(rule, fmt.Sprintf("path_namespace='%s'", f.PathNamespace))
+	}
+	if f.Interface != "" {
+		rule = append(rule, fmt.Sprintf("interface='%s'", f.Interface))
+	}
+	if f.Member != "" {
+		rule = append(rule, fmt.Sprintf("member='%s'", f.Member))
+	}
+	return strings.Join(rule, ",")
+}
+
+// Match check if a signal match the filter. Sender is not checked
+// as signals report the unique name of the sender
+func (f SignalFilter) Match(sig *dbus.Signal) bool {
+
+	if f.Path != "" && sig.Path != f.Path {
+		return false
+	}
+
+	if f.PathNamespace != "" && f.PathNamespace != "/" {
+		ns := string(f.PathNamespace)
+		path := string(sig.Path)
+		if path != ns && !strings.HasPrefix(path, ns+"/") {
+			return false
+		}
+	}
+
+	if f.Interface == "" && f.Member == "" {
+		return true
+	}
+
+	// sig.Name is in the form interface.member
+	i := strings.LastIndex(sig.Name, ".")
+	if i == -1 {
+		return false
+	}
+	iface, member := sig.Name[:i], sig.Name[i+1:]
+
+	if f.Interface != "" && iface != f.Interface {
+		return false
+	}
+	if f.Member != "" && member != f.Member {
+		return false
+	}
+
+	return true
+}
+
+// SubscribeOptions configure a signal subscription
+type SubscribeOptions struct {
+	// BufferSize is the number of pending signals, DefaultSignalBufferSize if zero
+	BufferSize int
+	// Overflow is the policy applied when the buffer is full, OverflowCoalesce if zero
+	Overflow OverflowPolicy
+}
+
+var routers = map[*dbus.Conn]*SignalRouter{}
+var routersLock sync.Mutex
+
+// GetSignalRouter return the signal router of a DBus connection
+func GetSignalRouter(conn *dbus.Conn) *SignalRouter {
+	routersLock.Lock()
+	defer routersLock.Unlock()
+
+	if r, ok := routers[conn]; ok {
+		return r
+	}
+
+	r := NewSignalRouter(conn)
+	routers[conn] = r
+	return r
+}
+
+func removeSignalRouter(conn *dbus.Conn) {
+	routersLock.Lock()
+	defer routersLock.Unlock()
+	delete(routers, conn)
+}
+
+// NewSignalRouter create a router which owns the conn.Signal channel of
+// a DBus connection and dispatches signals to subscribers
+func NewSignalRouter(conn *dbus.Conn) *SignalRouter {
+	return &SignalRouter{
+		conn:          conn,
+		subscriptions: make(map[chan *dbus.Signal]*subscription),
+		matches:       make(map[string]int),
+	}
+}
+
+// SignalRouter dispatch the signals of a connection to subscribers
+type SignalRouter struct {
+	conn *dbus.Conn
+
+	lock          sync.Mutex
+	signal        chan *dbus.Signal
+	quit          chan struct{}
+	subscriptions map[chan *dbus.Signal]*subscription
+	// matches reference count the match rules added to the bus
+	matches map[string]int
+}
+
+// Subscribe return a channel receiving the signals matching at least one of the filters
+func (r *SignalRouter) Subscribe(opts SubscribeOptions, filters ...SignalFilter) (chan *dbus.Signal, error) {
+
+	if len(filters) == 0 {
+		return nil, errors.New("At least a filter is required")
+	}
+
+	// match rules are added outside the lock, as the bus round trip
+	// would stall the dispatch of the signals
+	added := []string{}
+	for _, filter := range filters {
+		rule := filter.MatchRule()
+		err := r.addMatch(rule)
+		if err != nil {
+			for _, rule := range added {
+				r.removeMatch(rule)
+			}
+			return nil, fmt.Errorf("AddMatch: %s", err)
+		}
+		added = append(added, rule)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	sub := newSubscription(opts, filters)
+	r.subscriptions[sub.ch] = sub
+
+	if r.signal == nil {
+		r.signal = make(chan *dbus.Signal, DefaultSignalBufferSize)
+		r.quit = make(chan struct{})
+		r.conn.Signal(r.signal)
+		go r.dispatch(r.signal, r.quit)
+	}
+
+	return sub.ch, nil
+}
+
+// Unsubscribe stop delivering signals to ch and release its match rules
+func (r *SignalRouter) Unsubscribe(ch chan *dbus.Signal) error {
+
+	r.lock.Lock()
+
+	sub, ok := r.subscriptions[ch]
+	if !ok {
+		r.lock.Unlock()
+		return nil
+	}
+	delete(r.subscriptions, ch)
+
+	if len(r.subscriptions) == 0 && r.signal != nil {
+		r.conn.RemoveSignal(r.signal)
+		close(r.quit)
+		r.signal = nil
+		r.quit = nil
+	}
+
+	r.lock.Unlock()
+
+	// stop outside the lock as a blocked delivery may be waiting on it
+	sub.stop()
+
+	var err error
+	for _, filter := range sub.filters {
+		if err1 := r.removeMatch(filter.MatchRule()); err1 != nil {
+			err = fmt.Errorf("RemoveMatch: %s", err1)
+		}
+	}
+
+	return err
+}
+
+// addMatch add the rule to the bus on its first reference, the lock is
+// not held during the call. The bus reference count the rules too, so
+// concurrent add and remove calls keep the rule consistent
+func (r *SignalRouter) addMatch(rule string) error {
+
+	r.lock.Lock()
+	r.matches[rule]++
+	first := r.matches[rule] == 1
+	r.lock.Unlock()
+
+	if !first {
+		return nil
+	}
+
+	err := r.conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, rule).Err
+	if err != nil {
+		r.lock.Lock()
+		r.release(rule)
+		r.lock.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// removeMatch remove the rule from the bus on its last reference, the
+// lock is not held during the call
+func (r *SignalRouter) removeMatch(rule string) error {
+
+	r.lock.Lock()
+	last := r.matches[rule] == 1
+	r.release(rule)
+	r.lock.Unlock()
+
+	if !last {
+		return nil
+	}
+
+	return r.conn.BusObject().Call("org.freedesktop.DBus.RemoveMatch", 0, rule).Err
+}
+
+// release decrement the references of a rule
+func (r *SignalRouter) release(rule string) {
+	if r.matches[rule] == 0 {
+		return
+	}
+	r.matches[rule]--
+	if r.matches[rule] == 0 {
+		delete(r.matches, rule)
+	}
+}
+
+func (r *SignalRouter) dispatch(ch chan *dbus.Signal, quit chan struct{}) {
+	for {
+		var sig *dbus.Signal
+		select {
+		case s, ok := <-ch:
+			if !ok {
+				// channel closed by the connection
+				removeSignalRouter(r.conn)
+				return
+			}
+			sig = s
+		case <-quit:
+			return
+		}
+
+		r.lock.Lock()
+		subs := []*subscription{}
+		for _, sub := range r.subscriptions {
+			if sub.match(sig) {
+				subs = append(subs, sub)
+			}
+		}
+		r.lock.Unlock()
+
+		for _, sub := range subs {
+			sub.deliver(sig)
+		}
+	}
+}
+
+func newSubscription(opts SubscribeOptions, filters []SignalFilter) *subscription {
+
+	size := opts.BufferSize
+	if size <= 0 {
+		size = DefaultSignalBufferSize
+	}
+
+	sub := &subscription{
+		// buffered to allow the subscriber to send nil to cancel its reader
+		ch:      make(chan *dbus.Signal, 1),
+		filters: filters,
+		policy:  opts.Overflow,
+		size:    size,
+		done:    make(chan struct{}),
+	}
+	sub.cond = sync.NewCond(&sub.lock)
+
+	go sub.pump()
+
+	return sub
+}
+
+// subscription queue the signals for a subscriber
+type subscription struct {
+	ch      chan *dbus.Signal
+	filters []SignalFilter
+	policy  OverflowPolicy
+	size    int
+
+	lock    sync.Mutex
+	cond    *sync.Cond
+	queue   []*dbus.Signal
+	stopped bool
+	done    chan struct{}
+}
+
+func (s *subscription) match(sig *dbus.Signal) bool {
+	for _, f := range s.filters {
+		if f.Match(sig) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *subscription) deliver(sig *dbus.Signal) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stopped {
+		return
+	}
+
+	if len(s.queue) >= s.size {
+		switch s.policy {
+		case OverflowDrop:
+			return
+		case OverflowCoalesce:
+			if s.coalesce(sig) {
+				s.cond.Broadcast()
+				return
+			}
+			s.queue = s.queue[1:]
+		default:
+			for len(s.queue) >= s.size && !s.stopped {
+				s.cond.Wait()
+			}
+			if s.stopped {
+				return
+			}
+		}
+	}
+
+	s.queue = append(s.queue, sig)
+	s.cond.Broadcast()
+}
+
+// coalesce merge a PropertiesChanged signal with a pending one for the same object interface
+func (s *subscription) coalesce(sig *dbus.Signal) bool {
+
+	if sig.Name != PropertiesChanged || len(sig.Body) < 2 {
+		return false
+	}
+	iface, ok := sig.Body[0].(string)
+	if !ok {
+		return false
+	}
+	changed, ok := sig.Body[1].(map[string]dbus.Variant)
+	if !ok {
+		return false
+	}
+
+	for i, pending := range s.queue {
+		if pending.Name != PropertiesChanged || pending.Path != sig.Path || len(pending.Body) < 2 {
+			continue
+		}
+		if pendingIface, ok := pending.Body[0].(string); !ok || pendingIface != iface {
+			continue
+		}
+		pendingChanged, ok := pending.Body[1].(map[string]dbus.Variant)
+		if !ok {
+			continue
+		}
+
+		merged := make(map[string]dbus.Variant, len(pendingChanged)+len(changed))
+		for k, v := range pendingChanged {
+			merged[k] = v
+		}
+		for k, v := range changed {
+			merged[k] = v
+		}
+
+		invalidated := []string{}
+		if len(pending.Body) > 2 {
+			if list, ok := pending.Body[2].([]string); ok {
+				for _, name := range list {
+					if _, ok := changed[name]; !ok {
+						invalidated = append(invalidated, name)
+					}
+				}
+			}
+		}
+		if len(sig.Body) > 2 {
+			if list, ok := sig.Body[2].([]string); ok {
+				for _, name := range list {
+					delete(merged, name)
+					invalidated = append(invalidated, name)
+				}
+			}
+		}
+
+		s.queue[i] = &dbus.Signal{
+			Sender: sig.Sender,
+			Path:   sig.Path,
+			Name:   sig.Name,
+			Body:   []interface{}{iface, merged, invalidated},
+		}
+		return true
+	}
+
+	return false
+}
+
+func (s *subscription) pump() {
+	for {
+		s.lock.Lock()
+		for len(s.queue) == 0 && !s.stopped {
+			s.cond.Wait()
+		}
+		if s.stopped {
+			s.lock.Unlock()
+			return
+		}
+		sig := s.queue[0]
+		s.queue = s.queue[1:]
+		s.cond.Broadcast()
+		s.lock.Unlock()
+
+		select {
+		case s.ch <- sig:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *subscription) stop() {
+	s.lock.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.done)
+	}
+	s.cond.Broadcast()
+	s.lock.Unlock()
+}
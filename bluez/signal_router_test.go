@@ -0,0 +1,112 @@
+package bluez
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func propertiesChangedSignal(path dbus.ObjectPath, changed map[string]dbus.Variant, invalidated ...string) *dbus.Signal {
+	return &dbus.Signal{
+		Path: path,
+		Name: PropertiesChanged,
+		Body: []interface{}{"org.bluez.Device1", changed, invalidated},
+	}
+}
+
+// newQueueSubscription create a subscription without the pump, to inspect the queue
+func newQueueSubscription(size int, policy OverflowPolicy) *subscription {
+	sub := &subscription{size: size, policy: policy}
+	sub.cond = sync.NewCond(&sub.lock)
+	return sub
+}
+
+func TestSignalFilterMatch(t *testing.T) {
+
+	sig := propertiesChangedSignal("/org/bluez/hci0/dev_00", nil)
+
+	assert.True(t, SignalFilter{Path: "/org/bluez/hci0/dev_00"}.Match(sig))
+	assert.False(t, SignalFilter{Path: "/org/bluez/hci0"}.Match(sig))
+	assert.True(t, SignalFilter{PathNamespace: "/org/bluez"}.Match(sig))
+	assert.False(t, SignalFilter{PathNamespace: "/org/bluez/hci1"}.Match(sig))
+	assert.True(t, SignalFilter{Interface: PropertiesInterface, Member: "PropertiesChanged"}.Match(sig))
+	assert.False(t, SignalFilter{Interface: ObjectManagerInterface}.Match(sig))
+	assert.False(t, SignalFilter{Member: "InterfacesAdded"}.Match(sig))
+
+	assert.Equal(t,
+		"type='signal',path='/org/bluez/hci0',interface='org.freedesktop.DBus.Properties'",
+		SignalFilter{Path: "/org/bluez/hci0", Interface: PropertiesInterface}.MatchRule(),
+	)
+}
+
+func TestSubscriptionDrop(t *testing.T) {
+	sub := newQueueSubscription(1, OverflowDrop)
+	sub.deliver(propertiesChangedSignal("/a", nil))
+	sub.deliver(propertiesChangedSignal("/b", nil))
+	assert.Len(t, sub.queue, 1)
+	assert.Equal(t, dbus.ObjectPath("/a"), sub.queue[0].Path)
+}
+
+func TestSubscriptionCoalesce(t *testing.T) {
+	sub := newQueueSubscription(1, OverflowCoalesce)
+
+	sub.deliver(propertiesChangedSignal("/a", map[string]dbus.Variant{
+		"RSSI":      dbus.MakeVariant(int16(-60)),
+		"Connected": dbus.MakeVariant(false),
+	}))
+	sub.deliver(propertiesChangedSignal("/a", map[string]dbus.Variant{
+		"RSSI": dbus.MakeVariant(int16(-50)),
+	}, "Connected"))
+
+	assert.Len(t, sub.queue, 1)
+	changed := sub.queue[0].Body[1].(map[string]dbus.Variant)
+	assert.Equal(t, int16(-50), changed["RSSI"].Value())
+	_, ok := changed["Connected"]
+	assert.False(t, ok)
+	assert.Equal(t, []string{"Connected"}, sub.queue[0].Body[2])
+
+	// no pending signal to merge, the oldest is discarded
+	sub.deliver(propertiesChangedSignal("/b", nil))
+	assert.Len(t, sub.queue, 1)
+	assert.Equal(t, dbus.ObjectPath("/b"), sub.queue[0].Path)
+}
+
+func TestSubscriptionPump(t *testing.T) {
+	sub := newSubscription(SubscribeOptions{BufferSize: 2, Overflow: OverflowBlock}, []SignalFilter{{Path: "/a"}})
+	defer sub.stop()
+
+	assert.True(t, sub.match(propertiesChangedSignal("/a", nil)))
+	assert.False(t, sub.match(propertiesChangedSignal("/b", nil)))
+
+	for i := 0; i < 3; i++ {
+		sub.deliver(propertiesChangedSignal("/a", nil))
+	}
+	for i := 0; i < 3; i++ {
+		sig := <-sub.ch
+		assert.Equal(t, dbus.ObjectPath("/a"), sig.Path)
+	}
+}
+
+func TestSubscribeOptionsDefault(t *testing.T) {
+	// the zero value never blocks the dispatch
+	assert.Equal(t, OverflowCoalesce, SubscribeOptions{}.Overflow)
+
+	sub := newQueueSubscription(1, SubscribeOptions{}.Overflow)
+	sub.deliver(propertiesChangedSignal("/a", nil))
+	sub.deliver(propertiesChangedSignal("/b", nil))
+	assert.Len(t, sub.queue, 1)
+}
+
+func TestSignalRouterMatchesRefCount(t *testing.T) {
+	r := NewSignalRouter(nil)
+	r.matches["rule"] = 2
+	r.release("rule")
+	assert.Equal(t, 1, r.matches["rule"])
+	r.release("rule")
+	_, ok := r.matches["rule"]
+	assert.False(t, ok)
+	r.release("rule")
+	assert.Len(t, r.matches, 0)
+}
@@ -0,0 +1,156 @@
+package bluez
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+//go:generate sh -c "cd .. && go run gen/siggen/main.go"
+
+// BaseUUIDSuffix is the suffix of the Bluetooth Base UUID used to expand
+// 16 and 32 bit UUIDs to their 128 bit form
+const BaseUUIDSuffix = "-0000-1000-8000-00805f9b34fb"
+
+// baseUUID is 00000000-0000-1000-8000-00805f9b34fb
+var baseUUID = UUID{0, 0, 0, 0, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0x80, 0x5f, 0x9b, 0x34, 0xfb}
+
+// UUID is a Bluetooth UUID in its 128 bit form. 16 and 32 bit UUIDs are
+// expanded with the Bluetooth Base UUID, so UUIDs compare with ==
+type UUID [16]byte
+
+// UUID16 return the UUID of a 16 bit SIG assigned number
+func UUID16(v uint16) UUID {
+	return UUID32(uint32(v))
+}
+
+// UUID32 return the UUID of a 32 bit value
+func UUID32(v uint32) UUID {
+	u := baseUUID
+	binary.BigEndian.PutUint32(u[:4], v)
+	return u
+}
+
+// ParseUUID parse a UUID in 16 bit (180f, 0x180F), 32 bit or 128 bit form,
+// with or without dashes
+func ParseUUID(s string) (UUID, error) {
+
+	str := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "0x")
+	str = strings.Replace(str, "-", "", -1)
+
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return UUID{}, fmt.Errorf("invalid UUID %s: %s", s, err)
+	}
+
+	switch len(b) {
+	case 2:
+		return UUID16(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return UUID32(binary.BigEndian.Uint32(b)), nil
+	case 16:
+		u := UUID{}
+		copy(u[:], b)
+		return u, nil
+	}
+
+	return UUID{}, fmt.Errorf("invalid UUID %s: unexpected length", s)
+}
+
+// MustParseUUID parse a UUID and panic on failure
+func MustParseUUID(s string) UUID {
+	u, err := ParseUUID(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// String return the lower case 128 bit form, as used by Bluez
+func (u UUID) String() string {
+	b := make([]byte, 36)
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b)
+}
+
+// isBase return true if the UUID is derived from the Bluetooth Base UUID
+func (u UUID) isBase() bool {
+	for i := 4; i < 16; i++ {
+		if u[i] != baseUUID[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Uint16 return the 16 bit value of the UUID, false if it has no 16 bit form
+func (u UUID) Uint16() (uint16, bool) {
+	if !u.isBase() || u[0] != 0 || u[1] != 0 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(u[2:4]), true
+}
+
+// Uint32 return the 32 bit value of the UUID, false if it has no 32 bit form
+func (u UUID) Uint32() (uint32, bool) {
+	if !u.isBase() {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(u[0:4]), true
+}
+
+// Short return the shortest form of the UUID, eg. 180f, 0001abcd or the 128 bit form
+func (u UUID) Short() string {
+	if v, ok := u.Uint16(); ok {
+		return fmt.Sprintf("%04x", v)
+	}
+	if v, ok := u.Uint32(); ok {
+		return fmt.Sprintf("%08x", v)
+	}
+	return u.String()
+}
+
+// Bytes return the 16 bytes of the UUID in big endian order
+func (u UUID) Bytes() []byte {
+	return append([]byte{}, u[:]...)
+}
+
+// MarshalText encode the UUID in its 128 bit form
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText parse a UUID in 16, 32 or 128 bit form
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// ExpandUUID return the 128 bit lower case form of a 16, 32 or 128 bit UUID,
+// eg. 180f or 0x180F become 0000180f-0000-1000-8000-00805f9b34fb.
+// Invalid UUIDs are returned lower case
+func ExpandUUID(uuid string) string {
+	u, err := ParseUUID(uuid)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(uuid))
+	}
+	return u.String()
+}
+
+// EqualUUID compare two UUIDs in 16, 32 or 128 bit form
+func EqualUUID(a, b string) bool {
+	return ExpandUUID(a) == ExpandUUID(b)
+}
@@ -0,0 +1,83 @@
+package bluez
+
+import (
+	"sort"
+	"strings"
+)
+
+// Name return the SIG assigned name of a service, characteristic,
+// descriptor or member UUID, an empty string if unknown
+func (u UUID) Name() string {
+	v, ok := u.Uint16()
+	if !ok {
+		return ""
+	}
+	for _, table := range []map[uint16]string{sigServices, sigCharacteristics, sigDescriptors, sigMembers} {
+		if name, ok := table[v]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// ServiceUUID return the UUID of a SIG service by name, eg. Battery
+func ServiceUUID(name string) (UUID, bool) {
+	return lookupSIGUUID(sigServicesByName, name)
+}
+
+// CharacteristicUUID return the UUID of a SIG characteristic by name, eg. Battery Level
+func CharacteristicUUID(name string) (UUID, bool) {
+	return lookupSIGUUID(sigCharacteristicsByName, name)
+}
+
+// DescriptorUUID return the UUID of a SIG descriptor by name,
+// eg. Client Characteristic Configuration
+func DescriptorUUID(name string) (UUID, bool) {
+	return lookupSIGUUID(sigDescriptorsByName, name)
+}
+
+// CompanyName return the name of a SIG company identifier
+func CompanyName(id uint16) (string, bool) {
+	name, ok := sigCompanies[id]
+	return name, ok
+}
+
+// CompanyID return the SIG company identifier of a company by name
+func CompanyID(name string) (uint16, bool) {
+	id, ok := sigCompaniesByName[strings.ToLower(name)]
+	return id, ok
+}
+
+func lookupSIGUUID(reverse map[string]uint16, name string) (UUID, bool) {
+	v, ok := reverse[strings.ToLower(name)]
+	if !ok {
+		return UUID{}, false
+	}
+	return UUID16(v), true
+}
+
+var (
+	sigServicesByName        = reverseSIGTable(sigServices)
+	sigCharacteristicsByName = reverseSIGTable(sigCharacteristics)
+	sigDescriptorsByName     = reverseSIGTable(sigDescriptors)
+	sigCompaniesByName       = reverseSIGTable(sigCompanies)
+)
+
+// reverseSIGTable index a table by lower case name, the lowest value
+// is kept when a name is assigned more than once
+func reverseSIGTable(table map[uint16]string) map[string]uint16 {
+
+	values := []uint16{}
+	for v := range table {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i] > values[j]
+	})
+
+	reverse := map[string]uint16{}
+	for _, v := range values {
+		reverse[strings.ToLower(table[v])] = v
+	}
+	return reverse
+}
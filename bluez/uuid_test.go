@@ -0,0 +1,117 @@
+package bluez
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandUUID(t *testing.T) {
+	assert.Equal(t, "0000180f-0000-1000-8000-00805f9b34fb", ExpandUUID("180F"))
+	assert.Equal(t, "0000180f-0000-1000-8000-00805f9b34fb", ExpandUUID("0x180f"))
+	assert.Equal(t, "f000aa01-0000-1000-8000-00805f9b34fb", ExpandUUID("F000AA01"))
+	assert.Equal(t, "f000aa01-0451-4000-b000-000000000000", ExpandUUID("F000AA01-0451-4000-B000-000000000000"))
+}
+
+func TestEqualUUID(t *testing.T) {
+	assert.True(t, EqualUUID("2a19", "00002A19-0000-1000-8000-00805F9B34FB"))
+	assert.True(t, EqualUUID("00002a19", "2A19"))
+	assert.False(t, EqualUUID("2a19", "2a18"))
+}
+
+func TestParseUUID(t *testing.T) {
+
+	battery := UUID16(0x180f)
+	assert.Equal(t, "0000180f-0000-1000-8000-00805f9b34fb", battery.String())
+
+	for _, s := range []string{"180f", "0x180F", "0000180f", "0000180F-0000-1000-8000-00805F9B34FB", "0000180f00001000800000805f9b34fb"} {
+		u, err := ParseUUID(s)
+		assert.NoError(t, err)
+		assert.Equal(t, battery, u, s)
+	}
+
+	_, err := ParseUUID("180")
+	assert.Error(t, err)
+	_, err = ParseUUID("xyzw")
+	assert.Error(t, err)
+	_, err = ParseUUID("0000180f-0000")
+	assert.Error(t, err)
+}
+
+func TestUUIDShortForms(t *testing.T) {
+
+	v, ok := UUID16(0x2a19).Uint16()
+	assert.True(t, ok)
+	assert.Equal(t, uint16(0x2a19), v)
+	assert.Equal(t, "2a19", UUID16(0x2a19).Short())
+
+	u := UUID32(0x0001abcd)
+	_, ok = u.Uint16()
+	assert.False(t, ok)
+	v32, ok := u.Uint32()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(0x0001abcd), v32)
+	assert.Equal(t, "0001abcd", u.Short())
+
+	custom := MustParseUUID("F000AA01-0451-4000-B000-000000000000")
+	_, ok = custom.Uint32()
+	assert.False(t, ok)
+	assert.Equal(t, "f000aa01-0451-4000-b000-000000000000", custom.Short())
+}
+
+func TestUUIDText(t *testing.T) {
+	u := UUID{}
+	assert.NoError(t, u.UnmarshalText([]byte("2A37")))
+	assert.Equal(t, UUID16(0x2a37), u)
+	b, err := u.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "00002a37-0000-1000-8000-00805f9b34fb", string(b))
+}
+
+func TestUUIDNames(t *testing.T) {
+
+	assert.Equal(t, "Battery", UUID16(0x180f).Name())
+	assert.Equal(t, "Battery Level", UUID16(0x2a19).Name())
+	assert.Equal(t, "Client Characteristic Configuration", UUID16(0x2902).Name())
+	assert.Equal(t, "Google LLC", UUID16(0xfeaa).Name())
+	assert.Equal(t, "", UUID16(0xffff).Name())
+
+	u, ok := ServiceUUID("heart rate")
+	assert.True(t, ok)
+	assert.Equal(t, UUID16(0x180d), u)
+
+	u, ok = CharacteristicUUID("Heart Rate Measurement")
+	assert.True(t, ok)
+	assert.Equal(t, UUID16(0x2a37), u)
+
+	u, ok = DescriptorUUID("Client Characteristic Configuration")
+	assert.True(t, ok)
+	assert.Equal(t, UUID16(0x2902), u)
+
+	_, ok = ServiceUUID("Battery Level")
+	assert.False(t, ok)
+
+	name, ok := CompanyName(0x004c)
+	assert.True(t, ok)
+	assert.Equal(t, "Apple, Inc.", name)
+
+	id, ok := CompanyID("nordic semiconductor asa")
+	assert.True(t, ok)
+	assert.Equal(t, uint16(0x0059), id)
+}
+
+// TestSIGTables check the generated tables hold the full assigned numbers,
+// not the trimmed gen/testdata fixture. Run make gen/sig to regenerate them
+func TestSIGTables(t *testing.T) {
+
+	assert.True(t, len(sigCompanies) > 1000, "%d companies", len(sigCompanies))
+	assert.True(t, len(sigCharacteristics) > 200, "%d characteristics", len(sigCharacteristics))
+
+	// well known entries missing from the fixture
+	assert.NotEmpty(t, UUID16(0x2ad2).Name(), "Indoor Bike Data")
+	assert.NotEmpty(t, UUID16(0x2ad9).Name(), "Fitness Machine Control Point")
+	_, ok := CompanyName(0x0171)
+	assert.True(t, ok, "Amazon")
+	_, ok = CompanyName(0x0003)
+	assert.True(t, ok, "IBM")
+}
@@ -35,11 +35,48 @@ var discoveryCmd = &cobra.Command{
 			fail(err)
 		}
 
-		fail(discovery_example.Run(adapterID, onlyBeacon))
+		opts := discovery_example.FilterOptions{}
+		opts.AddressPrefix, err = cmd.Flags().GetString("address-prefix")
+		if err != nil {
+			fail(err)
+		}
+		opts.AddressType, err = cmd.Flags().GetString("address-type")
+		if err != nil {
+			fail(err)
+		}
+		opts.Name, err = cmd.Flags().GetString("name")
+		if err != nil {
+			fail(err)
+		}
+		opts.Manufacturer, err = cmd.Flags().GetString("manufacturer")
+		if err != nil {
+			fail(err)
+		}
+		opts.ServiceData, err = cmd.Flags().GetString("service-data")
+		if err != nil {
+			fail(err)
+		}
+		opts.MatchAny, err = cmd.Flags().GetBool("match-any")
+		if err != nil {
+			fail(err)
+		}
+
+		filter, err := opts.Filter()
+		if err != nil {
+			fail(err)
+		}
+
+		fail(discovery_example.Run(adapterID, onlyBeacon, filter))
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(discoveryCmd)
 	discoveryCmd.Flags().BoolP("beacon", "b", false, "Only report beacons")
+	discoveryCmd.Flags().String("address-prefix", "", "Only report addresses starting with a prefix or OUI, eg. 00:11:22")
+	discoveryCmd.Flags().String("address-type", "", "Only report public or random addresses")
+	discoveryCmd.Flags().String("name", "", "Only report names matching a regular expression")
+	discoveryCmd.Flags().String("manufacturer", "", "Only report manufacturer data in the form company[:data[:mask]] (hex), eg. 004c:0215")
+	discoveryCmd.Flags().String("service-data", "", "Only report service data of an UUID")
+	discoveryCmd.Flags().Bool("match-any", false, "Report devices matching any of the filters instead of all")
 }
@@ -1,11 +1,13 @@
 package sensortag
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/godbus/dbus/v5"
+	"github.com/dimonzozo/go-bluetooth/api"
 	"github.com/dimonzozo/go-bluetooth/bluez/profile/device"
 	"github.com/dimonzozo/go-bluetooth/bluez/profile/gatt"
 	log "github.com/sirupsen/logrus"
@@ -155,7 +157,10 @@ func NewSensorTag(d *device.Device1) (*SensorTag, error) {
 
 	if !d.Properties.Connected {
 		log.Debug("Connecting")
-		err := d.Connect()
+		// wait for the services to be resolved before looking up the sensors
+		conn := api.NewConnectionManager(d, api.ConnectionOptions{})
+		err := conn.Connect(context.Background())
+		conn.Close()
 		if err != nil {
 			return nil, err
 		}
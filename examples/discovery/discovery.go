@@ -11,7 +11,7 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func Run(adapterID string, onlyBeacon bool) error {
+func Run(adapterID string, onlyBeacon bool, filter api.ScanFilter) error {
 
 	//clean up connection on exit
 	defer api.Exit()
@@ -27,6 +27,10 @@ func Run(adapterID string, onlyBeacon bool) error {
 		return err
 	}
 
+	if filter != nil {
+		return runFiltered(a, onlyBeacon, filter)
+	}
+
 	log.Debug("Start discovery")
 	discovery, cancel, err := api.Discover(a, nil)
 	if err != nil {
@@ -66,6 +70,41 @@ func Run(adapterID string, onlyBeacon bool) error {
 	select {}
 }
 
+// runFiltered report the advertisements matching the local filter,
+// only the beacons if onlyBeacon is set
+func runFiltered(a *adapter.Adapter1, onlyBeacon bool, filter api.ScanFilter) error {
+
+	if onlyBeacon {
+		filter = api.And(filter, matchBeacon)
+	}
+
+	log.Debug("Start filtered discovery")
+	reports, cancel, err := api.DiscoverWithFilter(a, api.NewExtendedDiscoveryFilter(filter))
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	for report := range reports {
+		log.Infof("name=%s addr=%s (%s) rssi=%d", report.Name, report.Address, report.AddressType, report.RSSI)
+	}
+
+	return nil
+}
+
+// matchBeacon match the iBeacon, AltBeacon and Eddystone advertisements
+func matchBeacon(report *api.AdvertisementReport) bool {
+	for id, data := range report.ManufacturerData {
+		if id == 0x004C && len(data) >= 2 && data[0] == 0x02 && data[1] == 0x15 {
+			return true
+		}
+		if _, err := beacon.ParseAltBeacon(id, data); err == nil {
+			return true
+		}
+	}
+	return api.MatchServiceData(beacon.EddystoneServiceUUID.String())(report)
+}
+
 func handleBeacon(dev *device.Device1) error {
 
 	b, err := beacon.NewBeacon(dev)
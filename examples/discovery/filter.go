@@ -0,0 +1,93 @@
+package discovery_example
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dimonzozo/go-bluetooth/api"
+)
+
+// FilterOptions describe the local filters applied to the discovered devices
+type FilterOptions struct {
+	// AddressPrefix match an address prefix or OUI, eg. 00:11:22
+	AddressPrefix string
+	// AddressType match public or random addresses
+	AddressType string
+	// Name is a regular expression matching the device name
+	Name string
+	// Manufacturer is in the form company[:data[:mask]], all in hex, eg. 004c:0215
+	Manufacturer string
+	// ServiceData match a service data UUID
+	ServiceData string
+	// MatchAny combine the filters with OR instead of AND
+	MatchAny bool
+}
+
+// Filter return the ScanFilter matching the options, nil if no filter is set
+func (o FilterOptions) Filter() (api.ScanFilter, error) {
+
+	filters := []api.ScanFilter{}
+
+	if o.AddressPrefix != "" {
+		filters = append(filters, api.MatchAddressPrefix(o.AddressPrefix))
+	}
+	if o.AddressType != "" {
+		filters = append(filters, api.MatchAddressType(o.AddressType))
+	}
+	if o.Name != "" {
+		f, err := api.MatchName(o.Name)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if o.Manufacturer != "" {
+		f, err := parseManufacturer(o.Manufacturer)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if o.ServiceData != "" {
+		filters = append(filters, api.MatchServiceData(o.ServiceData))
+	}
+
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	if o.MatchAny {
+		return api.Or(filters...), nil
+	}
+	return api.And(filters...), nil
+}
+
+func parseManufacturer(spec string) (api.ScanFilter, error) {
+
+	parts := strings.Split(spec, ":")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("manufacturer: expected company[:data[:mask]]")
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(parts[0], "0x"), 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("manufacturer: invalid company ID %s", parts[0])
+	}
+
+	var data, mask []byte
+	if len(parts) > 1 {
+		data, err = hex.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("manufacturer: invalid data %s", parts[1])
+		}
+	}
+	if len(parts) > 2 {
+		mask, err = hex.DecodeString(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("manufacturer: invalid mask %s", parts[2])
+		}
+	}
+
+	return api.MatchManufacturer(uint16(id), data, mask), nil
+}
@@ -150,9 +150,17 @@ func (g *ApiGroup) parseMethod(raw []byte) (method Method, err error) {
 	re2 := regexp.MustCompile(`(?s)(org\.bluez\.Error\.\w+)`)
 	matches2 := re2.FindAllSubmatch(raw, -1)
 
-	if len(matches2) >= 1 {
-		for _, merr := range matches2[0] {
-			method.Errors = append(method.Errors, string(merr))
+	for _, merr := range matches2 {
+		errName := string(merr[1])
+		found := false
+		for _, e := range method.Errors {
+			if e == errName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			method.Errors = append(method.Errors, errName)
 		}
 	}
 
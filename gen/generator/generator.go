@@ -20,6 +20,14 @@ func Generate(bluezApi gen.BluezAPI, outDir string, forceOverwrite bool) error {
 		return err
 	}
 
+	bluezErrorsFile := path.Join(outDir, "gen_errors.go")
+	if forceOverwrite || !gen.Exists(bluezErrorsFile) {
+		err = BluezErrorsTemplate(bluezErrorsFile, apiGroups)
+		if err != nil {
+			return err
+		}
+	}
+
 	outDir += "/profile"
 	err = gen.Mkdir(outDir)
 	if err != nil {
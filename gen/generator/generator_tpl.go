@@ -32,7 +32,35 @@ func RootTemplate(filename string, api gen.ApiGroup) error {
 	return nil
 }
 
+// SIGTemplate generate the tables of the Bluetooth SIG assigned numbers
+func SIGTemplate(filename string, table gen.SIGTable) error {
+
+	fw, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("create file: %s", err)
+	}
+	defer fw.Close()
+
+	tmpl := loadtpl("sig")
+	err = tmpl.Execute(fw, table)
+	if err != nil {
+		return fmt.Errorf("tpl write: %s", err)
+	}
+
+	return nil
+}
+
+// ErrorsTemplate generate the dbus.Error list used to reply to Bluez
 func ErrorsTemplate(filename string, apis []gen.ApiGroup) error {
+	return errorsTemplate(filename, "errors", apis)
+}
+
+// BluezErrorsTemplate generate the sentinel errors matching the errors returned by Bluez
+func BluezErrorsTemplate(filename string, apis []gen.ApiGroup) error {
+	return errorsTemplate(filename, "bluez_errors", apis)
+}
+
+func errorsTemplate(filename string, tplName string, apis []gen.ApiGroup) error {
 
 	fw, err := os.Create(filename)
 	if err != nil {
@@ -60,7 +88,7 @@ func ErrorsTemplate(filename string, apis []gen.ApiGroup) error {
 		}
 	}
 
-	tmpl := loadtpl("errors")
+	tmpl := loadtpl(tplName)
 	err = tmpl.Execute(fw, errorsList)
 	if err != nil {
 		return fmt.Errorf("tpl write: %s", err)
@@ -188,9 +188,17 @@ func ApiTemplate(filename string, api gen.Api, apiGroup gen.ApiGroup) error {
 			continue
 		}
 
+		if cancelMethod, ok := override.GetCancelMethod(api.Interface, mm.Method.Name); ok {
+			mm.CancelMethod = cancelMethod
+		}
+
 		methods = append(methods, mm)
 	}
 
+	if len(methods) > 0 {
+		imports = append([]string{"context"}, imports...)
+	}
+
 	if importDbus {
 		imports = append(imports, "github.com/godbus/dbus/v5")
 	}
@@ -146,13 +146,13 @@ func appendIfMissing(slice []string, i string) []string {
 	return append(slice, i)
 }
 
-// getRawType clean tag from type
+// getRawType clean tag from type. Optional properties are pointers in the
+// properties struct, their accessors use the value type
 func getRawType(t string) string {
 	if strings.Contains(t, "`") {
-		p1 := strings.Trim(strings.Split(t, "`")[0], " ")
-		return p1
+		t = strings.Trim(strings.Split(t, "`")[0], " ")
 	}
-	return t
+	return strings.TrimPrefix(t, "*")
 }
 
 // getRawTypeInitializer return field initializer
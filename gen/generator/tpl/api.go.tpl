@@ -10,6 +10,12 @@ var {{.InterfaceName}}Interface = "{{.Api.Interface}}"
 // New{{$InterfaceName}}{{.Role}} create a new instance of {{$InterfaceName}}
 {{.ArgsDocs}}
 func New{{$InterfaceName}}{{.Role}}({{.Args}}) (*{{$InterfaceName}}, error) {
+	return New{{$InterfaceName}}{{.Role}}WithConn(nil{{if .Params}}, {{.Params}}{{end}})
+}
+
+// New{{$InterfaceName}}{{.Role}}WithConn create a new instance of {{$InterfaceName}} using the provided connection.
+// A nil conn falls back to the shared SystemBus connection
+func New{{$InterfaceName}}{{.Role}}WithConn(conn *bluez.Conn{{if .Args}}, {{.Args}}{{end}}) (*{{$InterfaceName}}, error) {
 	a := new({{$InterfaceName}})
 	a.client = bluez.NewClient(
 		&bluez.Config{
@@ -17,6 +23,7 @@ func New{{$InterfaceName}}{{.Role}}({{.Args}}) (*{{$InterfaceName}}, error) {
 			Iface: {{$InterfaceName}}Interface,
 			Path:  dbus.ObjectPath({{.ObjectPath}}),
 			Bus:   bluez.SystemBus,
+			Conn:  conn,
 		},
 	)
 	{{if $ExposeProperties }}
@@ -113,7 +120,7 @@ func (a *{{.InterfaceName}}) GetObjectManagerSignal() (chan *dbus.Signal, func()
 
 	if a.objectManagerSignal == nil {
 		if a.objectManager == nil {
-			om, err := bluez.GetObjectManager()
+			om, err := a.client.GetObjectManager()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -202,6 +209,7 @@ func (a *{{.InterfaceName}}) GetPropertiesSignal() (chan *dbus.Signal, error) {
 func (a *{{.InterfaceName}}) unregisterPropertiesSignal() {
 	if a.propertiesSignal != nil {
 		a.propertiesSignal <- nil
+		a.client.Unregister(a.client.Config.Path, bluez.PropertiesInterface, a.propertiesSignal)
 		a.propertiesSignal = nil
 	}
 }
@@ -222,11 +230,26 @@ func (a *{{.InterfaceName}}) UnwatchProperties(ch chan *bluez.PropertyChanged) e
 {{.Name}} {{.Docs}}
 */
 func (a *{{$InterfaceName}}) {{.Name}}({{.ArgsList}}) {{.Method.ReturnType}} {
-	{{if .SingleReturn}}
-	return a.client.Call("{{.Name}}", 0, {{.ParamsList}}).Store()
+	return a.{{.Name}}Context(context.Background(){{if .ParamsList}}, {{.ParamsList}}{{end}})
+}
+
+// {{.Name}}Context call {{.Name}}, the call is aborted when ctx is done{{if .CancelMethod}}
+// and {{.CancelMethod}} is called to stop the ongoing operation{{end}}
+func (a *{{$InterfaceName}}) {{.Name}}Context(ctx context.Context{{if .ArgsList}}, {{.ArgsList}}{{end}}) {{.Method.ReturnType}} {
+	{{if .SingleReturn}}{{if .CancelMethod}}
+	err := a.client.CallWithContext(ctx, "{{.Name}}", 0, {{.ParamsList}}).Store()
+	if err != nil && ctx.Err() != nil {
+		a.client.Call("{{.CancelMethod}}", 0).Store()
+	}
+	return bluez.ParseError(err)
 	{{else}}
+	return bluez.ParseError(a.client.CallWithContext(ctx, "{{.Name}}", 0, {{.ParamsList}}).Store())
+	{{end}}{{else}}
 	{{.ReturnVarsDefinition}}
-	err := a.client.Call("{{.Name}}", 0, {{.ParamsList}}).Store({{.ReturnVarsRefs}})
-	return {{.ReturnVarsList}}, err	{{end}}
+	err := a.client.CallWithContext(ctx, "{{.Name}}", 0, {{.ParamsList}}).Store({{.ReturnVarsRefs}})
+	{{if .CancelMethod}}if err != nil && ctx.Err() != nil {
+		a.client.Call("{{.CancelMethod}}", 0).Store()
+	}
+	{{end}}return {{.ReturnVarsList}}, bluez.ParseError(err)	{{end}}
 }
 {{end}}
@@ -0,0 +1,18 @@
+
+package bluez
+
+import (
+	"errors"
+)
+
+var (
+{{ range .List }}
+	// Err{{.Name}} match org.bluez.Error.{{.Name}}
+	Err{{.Name}} = errors.New("org.bluez.Error.{{.Name}}")
+{{ end }}
+)
+
+// errorsMap map a Bluez error name to the corresponding error
+var errorsMap = map[string]error{
+{{ range .List }}	"org.bluez.Error.{{.Name}}": Err{{.Name}},
+{{ end }}}
@@ -17,6 +17,7 @@ type MethodDoc struct {
 	ReturnVarsDefinition string
 	ReturnVarsRefs       string
 	ReturnVarsList       string
+	CancelMethod         string
 }
 
 type InterfaceDoc struct {
@@ -59,6 +60,7 @@ type Constructor struct {
 	Role       string
 	ObjectPath string
 	Args       string
+	Params     string
 	ArgsDocs   string
 	Docs       []string
 }
@@ -0,0 +1,18 @@
+package override
+
+// CancelMethods maps a method to the one to call when its context is cancelled
+var CancelMethods = map[string]map[string]string{
+	"org.bluez.Device1": map[string]string{
+		"Pair": "CancelPairing",
+	},
+}
+
+// GetCancelMethod return the method to call to abort an ongoing method call
+func GetCancelMethod(iface string, method string) (string, bool) {
+	if methods, ok := CancelMethods[iface]; ok {
+		if cancel, ok := methods[method]; ok {
+			return cancel, ok
+		}
+	}
+	return "", false
+}
@@ -23,7 +23,7 @@ var PropertyTypes = map[string]map[string]string{
 	"org.bluez.GattService1": map[string]string{
 		"Characteristics": "[]dbus.ObjectPath `dbus:\"emit\"`",
 		"Includes":        "[]dbus.ObjectPath `dbus:\"omitEmpty\"`",
-		"Device":          "[]dbus.ObjectPath `dbus:\"ignore=IsService\"`",
+		"Device":          "dbus.ObjectPath `dbus:\"ignore=IsService\"`",
 		"IsService":       "bool `dbus:\"ignore\"`",
 	},
 	"org.bluez.LEAdvertisement1": map[string]string{
@@ -33,5 +33,16 @@ var PropertyTypes = map[string]map[string]string{
 		"ManufacturerData": "map[uint16]interface{}",
 		// dbus type: (s[v]) dict of string variant (array of bytes)
 		"ServiceData": "map[string]interface{}",
+		// extended advertising, Bluez 5.54+. Omitted when not set as
+		// older versions reject the registration
+		"SecondaryChannel": "string `dbus:\"omitEmpty\"`",
+		"MinInterval":      "uint32 `dbus:\"omitEmpty\"`",
+		"MaxInterval":      "uint32 `dbus:\"omitEmpty\"`",
+		"TxPower":          "*int16 `dbus:\"omitEmpty\"`",
+	},
+	"org.bluez.LEAdvertisingManager1": map[string]string{
+		// Bluez 5.54+
+		"SupportedFeatures":          "[]string",
+		"SupportedSecondaryChannels": "[]string",
 	},
 }
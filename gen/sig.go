@@ -0,0 +1,95 @@
+package gen
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// SIGAssignedNumber is a Bluetooth SIG assigned 16 bit number
+type SIGAssignedNumber struct {
+	Value uint16
+	Name  string
+}
+
+// SIGTable list the Bluetooth SIG assigned numbers
+type SIGTable struct {
+	Services        []SIGAssignedNumber
+	Characteristics []SIGAssignedNumber
+	Descriptors     []SIGAssignedNumber
+	Members         []SIGAssignedNumber
+	Companies       []SIGAssignedNumber
+}
+
+type sigUUIDsFile struct {
+	UUIDs []struct {
+		UUID uint16 `yaml:"uuid"`
+		Name string `yaml:"name"`
+	} `yaml:"uuids"`
+}
+
+type sigCompaniesFile struct {
+	Companies []struct {
+		Value uint16 `yaml:"value"`
+		Name  string `yaml:"name"`
+	} `yaml:"company_identifiers"`
+}
+
+// ParseSIG load the assigned numbers from the assigned_numbers folder of the
+// Bluetooth SIG public repository, found in dir
+func ParseSIG(dir string) (SIGTable, error) {
+
+	table := SIGTable{}
+
+	uuidFiles := map[string]*[]SIGAssignedNumber{
+		"service_uuids.yaml":        &table.Services,
+		"characteristic_uuids.yaml": &table.Characteristics,
+		"descriptors.yaml":          &table.Descriptors,
+		"member_uuids.yaml":         &table.Members,
+	}
+
+	for filename, list := range uuidFiles {
+		file := sigUUIDsFile{}
+		err := loadYAML(path.Join(dir, "uuids", filename), &file)
+		if err != nil {
+			return table, err
+		}
+		for _, entry := range file.UUIDs {
+			*list = append(*list, SIGAssignedNumber{entry.UUID, entry.Name})
+		}
+		sortAssignedNumbers(*list)
+	}
+
+	companies := sigCompaniesFile{}
+	err := loadYAML(path.Join(dir, "company_identifiers", "company_identifiers.yaml"), &companies)
+	if err != nil {
+		return table, err
+	}
+	for _, entry := range companies.Companies {
+		table.Companies = append(table.Companies, SIGAssignedNumber{entry.Value, entry.Name})
+	}
+	sortAssignedNumbers(table.Companies)
+
+	return table, nil
+}
+
+func loadYAML(filename string, out interface{}) error {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("read %s: %s", filename, err)
+	}
+	err = yaml.Unmarshal(b, out)
+	if err != nil {
+		return fmt.Errorf("parse %s: %s", filename, err)
+	}
+	return nil
+}
+
+func sortAssignedNumbers(list []SIGAssignedNumber) {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Value < list[j].Value
+	})
+}
@@ -0,0 +1,25 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSIG(t *testing.T) {
+
+	table, err := ParseSIG("./testdata/assigned_numbers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Contains(t, table.Services, SIGAssignedNumber{0x180F, "Battery"})
+	assert.Contains(t, table.Characteristics, SIGAssignedNumber{0x2A19, "Battery Level"})
+	assert.Contains(t, table.Descriptors, SIGAssignedNumber{0x2902, "Client Characteristic Configuration"})
+	assert.Contains(t, table.Members, SIGAssignedNumber{0xFEAA, "Google LLC"})
+	assert.Contains(t, table.Companies, SIGAssignedNumber{0x004C, "Apple, Inc."})
+
+	for i := 1; i < len(table.Characteristics); i++ {
+		assert.True(t, table.Characteristics[i-1].Value < table.Characteristics[i].Value)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/dimonzozo/go-bluetooth/gen"
+	"github.com/dimonzozo/go-bluetooth/gen/generator"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+
+	log.Info("Generating SIG assigned numbers")
+
+	table, err := gen.ParseSIG("./src/bluetooth-sig/assigned_numbers")
+	if err != nil {
+		log.Fatalf("Parse failed: %s", err)
+	}
+
+	err = generator.SIGTemplate("./bluez/gen_sig.go", table)
+	if err != nil {
+		log.Fatalf("Generation failed: %s", err)
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/dimonzozo/go-bluetooth
 
-go 1.12
+go 1.13
 
 require (
 	github.com/fatih/structs v1.1.0
@@ -9,5 +9,7 @@ require (
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.5
 	github.com/spf13/viper v1.4.0
+	github.com/stretchr/testify v1.2.2
 	github.com/suapapa/go_eddystone v0.0.0-20190827074641-8d8c1bb79363
+	gopkg.in/yaml.v2 v2.2.2
 )